@@ -13,6 +13,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
@@ -21,6 +22,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
@@ -49,10 +51,17 @@ type (
 	awsimpl struct {
 		t *targetrunner
 	}
+	// sessions are safe for concurrent use and are cached per credential set
+	awsSessions struct {
+		sync.Mutex
+		m map[string]*session.Session
+	}
 )
 
 var (
 	_ cloudif = &awsimpl{}
+
+	awsSessionPool = awsSessions{m: make(map[string]*session.Session, 4)}
 )
 
 func newAWSProvider(t *targetrunner) *awsimpl { return &awsimpl{t} }
@@ -101,10 +110,10 @@ func extractAWSCreds(credsList map[string]string) *awsCreds {
 
 //======
 //
-// session FIXME: optimize
+// session
 //
 //======
-// A new session is created in two ways:
+// A session is created (once per credential set and then reused) in two ways:
 // 1. Authn is disabled or directory with credentials is not defined
 //    In this case a session is created using default credentials from
 //    configuration file in ~/.aws/credentials and environment variables
@@ -120,31 +129,59 @@ func extractAWSCreds(credsList map[string]string) *awsCreds {
 // If creation of a session with provided directory and userID fails, it
 // tries to create a session with default parameters
 func createSession(ct context.Context) *session.Session {
-	// TODO: avoid creating sessions for each request
 	userID := getStringFromContext(ct, ctxUserID)
 	userCreds := userCredsFromContext(ct)
 	if userID == "" || userCreds == nil {
 		if glog.V(5) {
-			glog.Info("No user ID or empty credentials: opening default session")
+			glog.Info("No user ID or empty credentials: using default session")
 		}
-		// default session
-		return session.Must(session.NewSessionWithOptions(session.Options{
-			SharedConfigState: session.SharedConfigEnable}))
+		return awsSessionPool.get(nil)
 	}
 
 	creds := extractAWSCreds(userCreds)
 	if creds == nil {
 		glog.Errorf("Failed to retrieve %s credentials %s", cmn.ProviderAmazon, userID)
-		return session.Must(session.NewSessionWithOptions(session.Options{
-			SharedConfigState: session.SharedConfigEnable}))
 	}
+	return awsSessionPool.get(creds)
+}
 
-	awsCreds := credentials.NewStaticCredentials(creds.key, creds.secret, "")
-	conf := aws.Config{
-		Region:      aws.String(creds.region),
-		Credentials: awsCreds,
+// nil creds - default session
+func (ss *awsSessions) get(creds *awsCreds) *session.Session {
+	var key string
+	if creds != nil {
+		key = creds.region + "/" + creds.key + "/" + creds.secret
+	}
+	ss.Lock()
+	defer ss.Unlock()
+	if sess, ok := ss.m[key]; ok {
+		return sess
+	}
+	var sess *session.Session
+	if creds == nil {
+		sess = session.Must(session.NewSessionWithOptions(session.Options{
+			SharedConfigState: session.SharedConfigEnable}))
+	} else {
+		awsCreds := credentials.NewStaticCredentials(creds.key, creds.secret, "")
+		conf := aws.Config{
+			Region:      aws.String(creds.region),
+			Credentials: awsCreds,
+		}
+		sess = session.Must(session.NewSessionWithOptions(session.Options{Config: conf}))
 	}
-	return session.Must(session.NewSessionWithOptions(session.Options{Config: conf}))
+	ss.m[key] = sess
+	return sess
+}
+
+// newS3 returns S3 client for idempotent requests: the retries are done by
+// cloudRetry (hence, disabled in the SDK) that also needs to see Retry-After
+func newS3(ct context.Context) *s3.S3 {
+	svc := s3.New(createSession(ct), aws.NewConfig().WithMaxRetries(0))
+	svc.Handlers.Complete.PushBack(func(r *request.Request) {
+		if r.HTTPResponse != nil {
+			setRetryAfter(ct, r.HTTPResponse.Header)
+		}
+	})
+	return svc
 }
 
 func awsErrorToHTTP(awsError error) int {
@@ -168,8 +205,7 @@ func (awsimpl *awsimpl) listbucket(ct context.Context, bucket string, msg *cmn.G
 	if glog.V(4) {
 		glog.Infof("listbucket %s", bucket)
 	}
-	sess := createSession(ct)
-	svc := s3.New(sess)
+	svc := newS3(ct)
 
 	params := &s3.ListObjectsInput{Bucket: aws.String(bucket)}
 	if msg.GetPrefix != "" {
@@ -268,8 +304,7 @@ func (awsimpl *awsimpl) headbucket(ct context.Context, bucket string) (bucketpro
 	}
 	bucketprops = make(cmn.SimpleKVs)
 
-	sess := createSession(ct)
-	svc := s3.New(sess)
+	svc := newS3(ct)
 	input := &s3.HeadBucketInput{Bucket: aws.String(bucket)}
 
 	_, err := svc.HeadBucket(input)
@@ -296,8 +331,7 @@ func (awsimpl *awsimpl) headbucket(ct context.Context, bucket string) (bucketpro
 }
 
 func (awsimpl *awsimpl) getbucketnames(ct context.Context) (buckets []string, errstr string, errcode int) {
-	sess := createSession(ct)
-	svc := s3.New(sess)
+	svc := newS3(ct)
 	result, err := svc.ListBuckets(&s3.ListBucketsInput{})
 	if err != nil {
 		errcode = awsErrorToHTTP(err)
@@ -325,8 +359,7 @@ func (awsimpl *awsimpl) headobject(ct context.Context, bucket string, objname st
 	}
 	objmeta = make(cmn.SimpleKVs)
 
-	sess := createSession(ct)
-	svc := s3.New(sess)
+	svc := newS3(ct)
 	input := &s3.HeadObjectInput{Bucket: aws.String(bucket), Key: aws.String(objname)}
 
 	headOutput, err := svc.HeadObject(input)
@@ -353,8 +386,7 @@ func (awsimpl *awsimpl) getobj(ct context.Context, workFQN, bucket, objname stri
 		cksumToCheck cmn.CksumProvider
 	)
 
-	sess := createSession(ct)
-	svc := s3.New(sess)
+	svc := newS3(ct)
 	obj, err := svc.GetObject(&s3.GetObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(objname),
@@ -438,8 +470,7 @@ func (awsimpl *awsimpl) putobj(ct context.Context, file *os.File, bucket, objnam
 }
 
 func (awsimpl *awsimpl) deleteobj(ct context.Context, bucket, objname string) (errstr string, errcode int) {
-	sess := createSession(ct)
-	svc := s3.New(sess)
+	svc := newS3(ct)
 	_, err := svc.DeleteObject(&s3.DeleteObjectInput{Bucket: aws.String(bucket), Key: aws.String(objname)})
 	if err != nil {
 		errcode = awsErrorToHTTP(err)
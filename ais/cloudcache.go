@@ -0,0 +1,281 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+//
+// cloudCache wraps the Cloud provider (cloudif) to:
+// - remember (for up to (config) cloud.neg_cache_ttl) the objects that the Cloud
+//   reported as non-existent, so that repeated probes (HEAD, cold GET) fail fast
+//   without a round trip; our own PUTs invalidate the respective entries -
+//   both before and after the upstream PUT - and bump the per-key generation,
+//   so that the lookups that started before the PUT completed do not add
+//   (stale) entries, and the in-flight HEADs are not joined by the new callers;
+// - coalesce concurrent HEAD and list-bucket requests for the same key into
+//   a single upstream call. Only the requests of the same user are coalesced;
+//   the shared call executes in a context that carries the user's credentials
+//   but is otherwise detached from any of the callers.
+//
+
+type (
+	cloudCache struct {
+		cloudif                      // the actual Cloud provider
+		negmtx  sync.Mutex           // protects negmap and generations
+		negmap  map[string]time.Time // uname => expiration time
+		gens    map[string]int64     // uname => generation of the last invalidation
+		gen     int64                // current generation
+		genmin  int64                // generation of the uname-s dropped from gens
+		flights cloudFlights
+	}
+	cloudFlights struct {
+		sync.Mutex
+		m map[string]*cloudFlight
+	}
+	cloudFlight struct {
+		wg      sync.WaitGroup
+		uname   string // HEAD only
+		objmeta cmn.SimpleKVs
+		jsbytes []byte
+		errstr  string
+		errcode int
+	}
+)
+
+var (
+	_ cloudif = &cloudCache{}
+)
+
+func newCloudCache(provider cloudif) *cloudCache {
+	return &cloudCache{
+		cloudif: provider,
+		negmap:  make(map[string]time.Time, 256),
+		gens:    make(map[string]int64, 256),
+		flights: cloudFlights{m: make(map[string]*cloudFlight, 16)},
+	}
+}
+
+//
+// negative cache
+//
+
+// notFound returns true if the object is known to not exist in the Cloud
+func (cc *cloudCache) notFound(bucket, objname string) bool {
+	if cmn.GCO.Get().Cloud.NegCacheTTL == 0 {
+		return false
+	}
+	uname := cluster.Uname(bucket, objname)
+	cc.negmtx.Lock()
+	expires, ok := cc.negmap[uname]
+	if ok && time.Now().After(expires) {
+		delete(cc.negmap, uname)
+		ok = false
+	}
+	cc.negmtx.Unlock()
+	return ok
+}
+
+// generation returns the current generation - to be taken prior to the lookup
+// and passed to addNotFound
+func (cc *cloudCache) generation() int64 {
+	cc.negmtx.Lock()
+	gen := cc.gen
+	cc.negmtx.Unlock()
+	return gen
+}
+
+// addNotFound adds the entry unless the object was invalidated (PUT) after
+// the lookup had started (at generation gen)
+func (cc *cloudCache) addNotFound(bucket, objname string, gen int64) {
+	config := cmn.GCO.Get()
+	if config.Cloud.NegCacheTTL == 0 {
+		return
+	}
+	now := time.Now()
+	uname := cluster.Uname(bucket, objname)
+	maxEntries := config.Cloud.NegCacheMax
+	cc.negmtx.Lock()
+	last, ok := cc.gens[uname]
+	if !ok {
+		last = cc.genmin
+	}
+	if last > gen {
+		cc.negmtx.Unlock()
+		return
+	}
+	if len(cc.negmap) >= maxEntries {
+		for k, expires := range cc.negmap {
+			if now.After(expires) {
+				delete(cc.negmap, k)
+			}
+		}
+		// still full: make room by dropping arbitrary entries
+		for k := range cc.negmap {
+			if len(cc.negmap) < maxEntries {
+				break
+			}
+			delete(cc.negmap, k)
+		}
+	}
+	cc.negmap[uname] = now.Add(config.Cloud.NegCacheTTL)
+	cc.negmtx.Unlock()
+}
+
+func (cc *cloudCache) invalidate(bucket, objname string) {
+	uname := cluster.Uname(bucket, objname)
+	cc.negmtx.Lock()
+	delete(cc.negmap, uname)
+	if len(cc.gens) >= cmn.GCO.Get().Cloud.NegCacheMax {
+		// forget the generations of all the keys at once (see addNotFound)
+		cc.gens = make(map[string]int64, len(cc.gens))
+		cc.genmin = cc.gen
+	}
+	cc.gen++
+	cc.gens[uname] = cc.gen
+	cc.negmtx.Unlock()
+	cc.flights.forget(uname)
+}
+
+func negCacheHit(bucket, objname string) (errstr string, errcode int) {
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s/%s: cloud negative cache hit", bucket, objname)
+	}
+	return fmt.Sprintf("%s/%s %s (cached)", bucket, objname, cmn.DoesNotExist), http.StatusNotFound
+}
+
+//
+// request coalescing
+//
+
+// join returns the in-flight call for the key and false, or registers a new one
+// and returns true - in which case the caller must execute the call and then land()
+func (fl *cloudFlights) join(key, uname string) (flight *cloudFlight, leader bool) {
+	fl.Lock()
+	if flight = fl.m[key]; flight != nil {
+		fl.Unlock()
+		flight.wg.Wait()
+		return
+	}
+	flight = &cloudFlight{uname: uname}
+	flight.wg.Add(1)
+	fl.m[key] = flight
+	fl.Unlock()
+	leader = true
+	return
+}
+
+func (fl *cloudFlights) land(key string, flight *cloudFlight) {
+	fl.Lock()
+	if fl.m[key] == flight {
+		delete(fl.m, key)
+	}
+	fl.Unlock()
+	flight.wg.Done()
+}
+
+// forget unregisters the in-flight HEADs of the object, so that the new
+// callers do not join them
+func (fl *cloudFlights) forget(uname string) {
+	fl.Lock()
+	for key, flight := range fl.m {
+		if flight.uname == uname {
+			delete(fl.m, key)
+		}
+	}
+	fl.Unlock()
+}
+
+// flightKey prefixes the key with the caller's identity so that the requests
+// made on behalf of different users (and, therefore, with different Cloud
+// credentials) are never coalesced
+func flightKey(ct context.Context, op, key string) string {
+	return op + getStringFromContext(ct, ctxUserID) + "|" + key
+}
+
+// detachCtx returns a context that carries the caller's identity and credentials
+// but none of its other values (or deadlines, cancellation) - to execute the call
+// shared by all the coalesced requests
+func detachCtx(ct context.Context) context.Context {
+	dct := context.Background()
+	for _, field := range []contextID{ctxUserID, ctxCredsDir, ctxUserCreds} {
+		if v := ct.Value(field); v != nil {
+			dct = context.WithValue(dct, field, v)
+		}
+	}
+	return dct
+}
+
+//
+// cloudif
+//
+
+func (cc *cloudCache) headobject(ct context.Context, bucket string, objname string) (objmeta cmn.SimpleKVs, errstr string, errcode int) {
+	if cc.notFound(bucket, objname) {
+		errstr, errcode = negCacheHit(bucket, objname)
+		return
+	}
+	uname := cluster.Uname(bucket, objname)
+	key := flightKey(ct, "h", uname)
+	flight, leader := cc.flights.join(key, uname)
+	if leader {
+		gen := cc.generation()
+		flight.objmeta, flight.errstr, flight.errcode = cc.cloudif.headobject(detachCtx(ct), bucket, objname)
+		if flight.errcode == http.StatusNotFound {
+			cc.addNotFound(bucket, objname, gen)
+		}
+		cc.flights.land(key, flight)
+	}
+	// NOTE: the callers may modify the returned metadata - hence, the copy
+	if flight.objmeta != nil {
+		objmeta = make(cmn.SimpleKVs, len(flight.objmeta))
+		for k, v := range flight.objmeta {
+			objmeta[k] = v
+		}
+	}
+	return objmeta, flight.errstr, flight.errcode
+}
+
+func (cc *cloudCache) listbucket(ct context.Context, bucket string, msg *cmn.GetMsg) (jsbytes []byte, errstr string, errcode int) {
+	b, err := jsoniter.Marshal(msg)
+	cmn.AssertNoErr(err)
+	key := flightKey(ct, "l", bucket+"/"+string(b))
+	flight, leader := cc.flights.join(key, "")
+	if leader {
+		flight.jsbytes, flight.errstr, flight.errcode = cc.cloudif.listbucket(detachCtx(ct), bucket, msg)
+		cc.flights.land(key, flight)
+	}
+	return flight.jsbytes, flight.errstr, flight.errcode
+}
+
+func (cc *cloudCache) getobj(ct context.Context, fqn, bucket, objname string) (props *cluster.LOM, errstr string, errcode int) {
+	if cc.notFound(bucket, objname) {
+		errstr, errcode = negCacheHit(bucket, objname)
+		return
+	}
+	gen := cc.generation()
+	if props, errstr, errcode = cc.cloudif.getobj(ct, fqn, bucket, objname); errcode == http.StatusNotFound {
+		cc.addNotFound(bucket, objname, gen)
+	}
+	return
+}
+
+func (cc *cloudCache) putobj(ct context.Context, file *os.File, bucket, objname string, cksum cmn.CksumProvider) (version string, errstr string, errcode int) {
+	cc.invalidate(bucket, objname)
+	version, errstr, errcode = cc.cloudif.putobj(ct, file, bucket, objname, cksum)
+	cc.invalidate(bucket, objname)
+	return
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+type countingCloud struct {
+	emptyCloud
+	heads    int32
+	lists    int32
+	notFound bool
+	delay    time.Duration
+}
+
+func (m *countingCloud) headobject(ctx context.Context, bucket string, objname string) (objmeta cmn.SimpleKVs, errstr string, errcode int) {
+	atomic.AddInt32(&m.heads, 1)
+	time.Sleep(m.delay)
+	if m.notFound {
+		return nil, "not found", http.StatusNotFound
+	}
+	return cmn.SimpleKVs{cmn.HeaderObjVersion: "1"}, "", 0
+}
+
+func (m *countingCloud) listbucket(ctx context.Context, bucket string, msg *cmn.GetMsg) (jsbytes []byte, errstr string, errcode int) {
+	atomic.AddInt32(&m.lists, 1)
+	time.Sleep(m.delay)
+	return []byte("{}"), "", 0
+}
+
+func setNegCacheConf(ttl time.Duration) {
+	config := cmn.GCO.BeginUpdate()
+	config.Cloud.NegCacheTTL, config.Cloud.NegCacheMax = ttl, 16
+	cmn.GCO.CommitUpdate(config)
+}
+
+func TestCloudCacheNegative(t *testing.T) {
+	setNegCacheConf(time.Minute)
+	provider := &countingCloud{notFound: true}
+	cc := newCloudCache(provider)
+	for i := 0; i < 10; i++ {
+		if _, _, errcode := cc.headobject(context.Background(), "bucket", "obj"); errcode != http.StatusNotFound {
+			t.Fatalf("expected %d, got %d", http.StatusNotFound, errcode)
+		}
+	}
+	if provider.heads != 1 {
+		t.Errorf("expected a single upstream HEAD, got %d", provider.heads)
+	}
+
+	// our own PUT invalidates
+	file, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	cc.putobj(context.Background(), file, "bucket", "obj", nil)
+	if cc.notFound("bucket", "obj") {
+		t.Error("PUT must invalidate the negative cache entry")
+	}
+	provider.notFound = false
+	if _, errstr, _ := cc.headobject(context.Background(), "bucket", "obj"); errstr != "" {
+		t.Errorf("unexpected error: %s", errstr)
+	}
+	if provider.heads != 2 {
+		t.Errorf("expected 2 upstream HEADs, got %d", provider.heads)
+	}
+}
+
+// a lookup that started before our own PUT completed must not add a negative
+// entry, and the callers that come after the PUT must not join it
+func TestCloudCacheNegativeInflight(t *testing.T) {
+	setNegCacheConf(time.Minute)
+	provider := &countingCloud{notFound: true, delay: 100 * time.Millisecond}
+	cc := newCloudCache(provider)
+	file, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	wg := &sync.WaitGroup{}
+	headDuringPut := func(after bool) {
+		wg.Add(1)
+		go func() {
+			cc.headobject(context.Background(), "bucket", "obj")
+			wg.Done()
+		}()
+		time.Sleep(20 * time.Millisecond)
+		cc.putobj(context.Background(), file, "bucket", "obj", nil)
+		if after {
+			cc.headobject(context.Background(), "bucket", "obj")
+		}
+		wg.Wait()
+	}
+	headDuringPut(false)
+	if cc.notFound("bucket", "obj") {
+		t.Error("the HEAD that started before the PUT must not add a negative cache entry")
+	}
+	headDuringPut(true)
+	if n := atomic.LoadInt32(&provider.heads); n != 3 {
+		t.Errorf("expected 3 upstream HEADs, got %d", n)
+	}
+	// the HEAD that started after the PUT does add
+	if !cc.notFound("bucket", "obj") {
+		t.Error("expecting the negative cache entry")
+	}
+}
+
+func TestCloudCacheCoalesce(t *testing.T) {
+	const concurrency = 16
+	provider := &countingCloud{delay: 100 * time.Millisecond}
+	cc := newCloudCache(provider)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(2)
+		go func() {
+			objmeta, errstr, _ := cc.headobject(context.Background(), "bucket", "obj")
+			if errstr != "" || objmeta[cmn.HeaderObjVersion] != "1" {
+				t.Errorf("unexpected HEAD result: %v, %s", objmeta, errstr)
+			}
+			wg.Done()
+		}()
+		go func() {
+			if _, errstr, _ := cc.listbucket(context.Background(), "bucket", &cmn.GetMsg{GetPrefix: "a"}); errstr != "" {
+				t.Errorf("unexpected list error: %s", errstr)
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	if provider.heads >= concurrency || provider.lists >= concurrency {
+		t.Errorf("expected concurrent requests to be coalesced: %d HEADs, %d lists", provider.heads, provider.lists)
+	}
+}
+
+func TestCloudCacheNegativeDisabled(t *testing.T) {
+	setNegCacheConf(0)
+	provider := &countingCloud{notFound: true}
+	cc := newCloudCache(provider)
+	for i := 0; i < 3; i++ {
+		cc.headobject(context.Background(), "bucket", "obj")
+	}
+	if provider.heads != 3 {
+		t.Errorf("expected 3 upstream HEADs with the negative cache disabled, got %d", provider.heads)
+	}
+}
+
+func TestCloudCacheCoalescePerUser(t *testing.T) {
+	const concurrency = 8
+	provider := &countingCloud{delay: 100 * time.Millisecond}
+	cc := newCloudCache(provider)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		for _, user := range []string{"alice", "bob"} {
+			wg.Add(1)
+			go func(user string) {
+				ct := context.WithValue(context.Background(), ctxUserID, user)
+				cc.headobject(ct, "bucket", "obj")
+				wg.Done()
+			}(user)
+		}
+	}
+	wg.Wait()
+	if provider.heads < 2 {
+		t.Errorf("requests of different users must not be coalesced: %d HEADs", provider.heads)
+	}
+}
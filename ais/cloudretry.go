@@ -0,0 +1,308 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+//
+// cloudRetry wraps the Cloud provider (cloudif) to make it resilient vis-a-vis
+// transient failures:
+// - idempotent operations (list, HEAD, GET and DELETE) that fail with a 5xx
+//   or throttling (429) error are retried with jittered exponential backoff;
+//   if the Cloud responds with Retry-After, the latter takes precedence;
+// - the per-provider circuit breaker opens after (config) breaker_threshold
+//   consecutive transient failures and fails all requests fast for
+//   breaker_timeout, after which a single probe request is let through.
+// PUT is not retried (the object is streamed) but counts toward the breaker.
+// See cmn.CloudConf for the configuration.
+//
+
+const (
+	breakerClosed = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+type (
+	cloudRetry struct {
+		cloudif                // the actual Cloud provider
+		provider string        // cmn.ProviderAmazon, etc.
+		statsif  stats.Tracker // NOTE: nil when not running (e.g. unit tests)
+		breaker  cloudBreaker
+	}
+	cloudBreaker struct {
+		sync.Mutex
+		state    int
+		failures int       // consecutive
+		openedAt time.Time //
+		probing  bool      // half-open: the probe is in progress
+	}
+	// providers report Retry-After (if any) via the request context - see setRetryAfter
+	retryAfter struct {
+		d time.Duration
+	}
+)
+
+const ctxRetryAfter contextID = "retryAfter"
+
+var (
+	_ cloudif = &cloudRetry{}
+)
+
+func newCloudRetry(provider cloudif, name string, statsif stats.Tracker) *cloudRetry {
+	return &cloudRetry{cloudif: provider, provider: name, statsif: statsif}
+}
+
+// setRetryAfter is called by the Cloud providers upon receiving a response
+func setRetryAfter(ct context.Context, header http.Header) {
+	ra, ok := ct.Value(ctxRetryAfter).(*retryAfter)
+	if !ok || header == nil {
+		return
+	}
+	s := header.Get("Retry-After")
+	if s == "" {
+		return
+	}
+	if secs, err := strconv.Atoi(s); err == nil && secs >= 0 {
+		ra.d = time.Duration(secs) * time.Second
+	} else if tm, err := http.ParseTime(s); err == nil {
+		ra.d = time.Until(tm)
+	}
+}
+
+func retriable(errcode int) bool {
+	return errcode == http.StatusTooManyRequests || errcode >= http.StatusInternalServerError
+}
+
+func (cr *cloudRetry) statsAdd(name string) {
+	if cr.statsif != nil {
+		cr.statsif.Add(name, 1)
+	}
+}
+
+//
+// circuit breaker
+//
+
+func (cr *cloudRetry) admit(config *cmn.CloudConf) (errstr string, errcode int) {
+	if config.BreakerThreshold == 0 {
+		return
+	}
+	b := &cr.breaker
+	b.Lock()
+	defer b.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) >= config.BreakerTimeout {
+			b.state, b.probing = breakerHalfOpen, true
+			glog.Infof("%s circuit half-open: probing", cr.provider)
+			return
+		}
+	case breakerHalfOpen:
+		if !b.probing {
+			b.probing = true
+			return
+		}
+	default:
+		return
+	}
+	cr.statsAdd(stats.CloudBreakerRejectCount)
+	return fmt.Sprintf("%s circuit is open (%d consecutive failures): failing fast", cr.provider, b.failures),
+		http.StatusServiceUnavailable
+}
+
+// breakerStats returns the current state of the circuit breaker
+func (cr *cloudRetry) breakerStats() *cmn.CloudBreakerStats {
+	st := &cmn.CloudBreakerStats{Provider: cr.provider, State: cmn.CloudBreakerDisabled}
+	if cmn.GCO.Get().Cloud.BreakerThreshold == 0 {
+		return st
+	}
+	b := &cr.breaker
+	b.Lock()
+	switch b.state {
+	case breakerOpen:
+		st.State, st.OpenedAt = cmn.CloudBreakerOpen, b.openedAt
+	case breakerHalfOpen:
+		st.State, st.OpenedAt = cmn.CloudBreakerHalfOpen, b.openedAt
+	default:
+		st.State = cmn.CloudBreakerClosed
+	}
+	st.Failures = b.failures
+	b.Unlock()
+	return st
+}
+
+func (cr *cloudRetry) done(config *cmn.CloudConf, errcode int) {
+	if config.BreakerThreshold == 0 {
+		return
+	}
+	b := &cr.breaker
+	b.Lock()
+	defer b.Unlock()
+	b.probing = false
+	if !retriable(errcode) { // success or non-transient error: the Cloud is alive
+		if b.state != breakerClosed {
+			glog.Infof("%s circuit closed", cr.provider)
+		}
+		b.state, b.failures = breakerClosed, 0
+		return
+	}
+	b.failures++
+	if b.state == breakerHalfOpen || (b.state == breakerClosed && b.failures >= config.BreakerThreshold) {
+		glog.Errorf("%s circuit open: %d consecutive failures", cr.provider, b.failures)
+		b.state, b.openedAt = breakerOpen, time.Now()
+		cr.statsAdd(stats.CloudBreakerTripCount)
+	}
+}
+
+//
+// retries
+//
+
+// do executes the call while retrying transient failures if the call is idempotent
+func (cr *cloudRetry) do(ct context.Context, idempotent bool, call func(ct context.Context) (string, int)) (errstr string, errcode int) {
+	config := &cmn.GCO.Get().Cloud
+	for attempt := 0; ; attempt++ {
+		if errstr, errcode = cr.admit(config); errstr != "" {
+			return
+		}
+		ra := &retryAfter{}
+		errstr, errcode = call(context.WithValue(ct, ctxRetryAfter, ra))
+		cr.done(config, errcode)
+		if errstr == "" || !retriable(errcode) || !idempotent || attempt >= config.MaxRetries {
+			return
+		}
+		sleep := backoff(config, attempt)
+		if ra.d > 0 {
+			if ra.d > config.RetryBackoffMax {
+				return // not waiting that long
+			}
+			sleep = ra.d
+		}
+		glog.Warningf("%s (%d) - retrying in %v (attempt %d/%d)", errstr, errcode, sleep, attempt+1, config.MaxRetries)
+		cr.statsAdd(stats.CloudRetryCount)
+		select {
+		case <-time.After(sleep):
+		case <-ct.Done():
+			return
+		}
+	}
+}
+
+// jittered exponential backoff: a random value in the [d/2, d] range
+// where d doubles with every attempt (up to the configured max)
+func backoff(config *cmn.CloudConf, attempt int) time.Duration {
+	d := config.RetryBackoff
+	for i := 0; i < attempt && d < config.RetryBackoffMax; i++ {
+		d *= 2
+	}
+	if d > config.RetryBackoffMax {
+		d = config.RetryBackoffMax
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+//
+// cloudif
+//
+
+func (cr *cloudRetry) listbucket(ct context.Context, bucket string, msg *cmn.GetMsg) (jsbytes []byte, errstr string, errcode int) {
+	errstr, errcode = cr.do(ct, true, func(ct context.Context) (string, int) {
+		var (
+			errstr  string
+			errcode int
+		)
+		jsbytes, errstr, errcode = cr.cloudif.listbucket(ct, bucket, msg)
+		return errstr, errcode
+	})
+	return
+}
+
+func (cr *cloudRetry) headbucket(ct context.Context, bucket string) (bucketprops cmn.SimpleKVs, errstr string, errcode int) {
+	errstr, errcode = cr.do(ct, true, func(ct context.Context) (string, int) {
+		var (
+			errstr  string
+			errcode int
+		)
+		bucketprops, errstr, errcode = cr.cloudif.headbucket(ct, bucket)
+		return errstr, errcode
+	})
+	return
+}
+
+func (cr *cloudRetry) getbucketnames(ct context.Context) (buckets []string, errstr string, errcode int) {
+	errstr, errcode = cr.do(ct, true, func(ct context.Context) (string, int) {
+		var (
+			errstr  string
+			errcode int
+		)
+		buckets, errstr, errcode = cr.cloudif.getbucketnames(ct)
+		return errstr, errcode
+	})
+	return
+}
+
+func (cr *cloudRetry) headobject(ct context.Context, bucket string, objname string) (objmeta cmn.SimpleKVs, errstr string, errcode int) {
+	errstr, errcode = cr.do(ct, true, func(ct context.Context) (string, int) {
+		var (
+			errstr  string
+			errcode int
+		)
+		objmeta, errstr, errcode = cr.cloudif.headobject(ct, bucket, objname)
+		return errstr, errcode
+	})
+	return
+}
+
+func (cr *cloudRetry) getobj(ct context.Context, fqn, bucket, objname string) (props *cluster.LOM, errstr string, errcode int) {
+	errstr, errcode = cr.do(ct, true, func(ct context.Context) (string, int) {
+		var (
+			errstr  string
+			errcode int
+		)
+		props, errstr, errcode = cr.cloudif.getobj(ct, fqn, bucket, objname)
+		return errstr, errcode
+	})
+	return
+}
+
+func (cr *cloudRetry) putobj(ct context.Context, file *os.File, bucket, objname string, cksum cmn.CksumProvider) (version string, errstr string, errcode int) {
+	errstr, errcode = cr.do(ct, false, func(ct context.Context) (string, int) {
+		var (
+			errstr  string
+			errcode int
+		)
+		version, errstr, errcode = cr.cloudif.putobj(ct, file, bucket, objname, cksum)
+		return errstr, errcode
+	})
+	return
+}
+
+func (cr *cloudRetry) deleteobj(ct context.Context, bucket, objname string) (errstr string, errcode int) {
+	var retried bool
+	return cr.do(ct, true, func(ct context.Context) (string, int) {
+		errstr, errcode := cr.cloudif.deleteobj(ct, bucket, objname)
+		// a retry may find the object already deleted by the previous (failed) attempt
+		if retried && errcode == http.StatusNotFound {
+			return "", 0
+		}
+		retried = true
+		return errstr, errcode
+	})
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+type flakyCloud struct {
+	emptyCloud
+	calls    int
+	failures int // number of leading calls that fail with 503
+}
+
+func (m *flakyCloud) headobject(ct context.Context, bucket string, objname string) (objmeta cmn.SimpleKVs, errstr string, errcode int) {
+	m.calls++
+	if m.calls <= m.failures {
+		return nil, "service unavailable", http.StatusServiceUnavailable
+	}
+	return cmn.SimpleKVs{}, "", 0
+}
+
+func setCloudConf(maxRetries, threshold int, breakerTimeout time.Duration) {
+	config := cmn.GCO.BeginUpdate()
+	config.Cloud = cmn.CloudConf{
+		MaxRetries:       maxRetries,
+		RetryBackoff:     time.Millisecond,
+		RetryBackoffMax:  4 * time.Millisecond,
+		BreakerThreshold: threshold,
+		BreakerTimeout:   breakerTimeout,
+	}
+	cmn.GCO.CommitUpdate(config)
+}
+
+func TestCloudRetry(t *testing.T) {
+	setCloudConf(3, 0, 0)
+	provider := &flakyCloud{failures: 2}
+	cr := newCloudRetry(provider, "test", nil)
+	if _, errstr, _ := cr.headobject(context.Background(), "bucket", "obj"); errstr != "" {
+		t.Fatalf("expected success after retries, got: %s", errstr)
+	}
+	if provider.calls != 3 {
+		t.Errorf("expected 3 calls, got %d", provider.calls)
+	}
+
+	provider = &flakyCloud{failures: 10}
+	cr = newCloudRetry(provider, "test", nil)
+	if _, _, errcode := cr.headobject(context.Background(), "bucket", "obj"); errcode != http.StatusServiceUnavailable {
+		t.Fatalf("expected %d, got %d", http.StatusServiceUnavailable, errcode)
+	}
+	if provider.calls != 4 {
+		t.Errorf("expected 1 + 3 retries, got %d calls", provider.calls)
+	}
+}
+
+func TestCloudBreaker(t *testing.T) {
+	const breakerTimeout = 50 * time.Millisecond
+	setCloudConf(0, 3, breakerTimeout)
+	provider := &flakyCloud{failures: 4}
+	cr := newCloudRetry(provider, "test", nil)
+	for i := 0; i < 3; i++ {
+		cr.headobject(context.Background(), "bucket", "obj")
+	}
+	// open: failing fast
+	if _, errstr, errcode := cr.headobject(context.Background(), "bucket", "obj"); errcode != http.StatusServiceUnavailable || provider.calls != 3 {
+		t.Fatalf("expected the circuit to be open: %s (%d), %d calls", errstr, errcode, provider.calls)
+	}
+	if st := cr.breakerStats(); st.State != cmn.CloudBreakerOpen || st.Failures != 3 || st.OpenedAt.IsZero() {
+		t.Errorf("expected the open circuit to be reported, got %+v", st)
+	}
+	// half-open: the (failing) probe reopens the circuit
+	time.Sleep(breakerTimeout)
+	cr.headobject(context.Background(), "bucket", "obj")
+	if provider.calls != 4 || cr.breaker.state != breakerOpen {
+		t.Fatalf("expected failed probe to reopen the circuit: %d calls, state %d", provider.calls, cr.breaker.state)
+	}
+	// half-open: the successful probe closes the circuit
+	time.Sleep(breakerTimeout)
+	if _, errstr, _ := cr.headobject(context.Background(), "bucket", "obj"); errstr != "" {
+		t.Fatalf("expected successful probe, got: %s", errstr)
+	}
+	if cr.breaker.state != breakerClosed {
+		t.Errorf("expected the circuit to be closed, state %d", cr.breaker.state)
+	}
+	if st := cr.breakerStats(); st.State != cmn.CloudBreakerClosed || st.Failures != 0 {
+		t.Errorf("expected the closed circuit to be reported, got %+v", st)
+	}
+}
+
+func TestCloudBackoff(t *testing.T) {
+	config := &cmn.CloudConf{RetryBackoff: 100 * time.Millisecond, RetryBackoffMax: time.Second}
+	for attempt := 0; attempt < 10; attempt++ {
+		d := backoff(config, attempt)
+		if d < config.RetryBackoff/2 || d > config.RetryBackoffMax {
+			t.Errorf("attempt %d: backoff %v out of range", attempt, d)
+		}
+	}
+}
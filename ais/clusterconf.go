@@ -0,0 +1,554 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+//
+// Cluster-wide configuration
+//
+// The cluster-wide configuration (clusterConf) is versioned cluster metadata:
+// named values - the same names that setconfig accepts - that apply to all nodes,
+// and per-node overrides layered on top. The primary proxy creates a new version
+// upon each change (setconfig, unsetconfig, rollbackconfig) and metasync-s it to
+// all nodes, including those that join (or rejoin) the cluster later.
+//
+// Every node persists the latest version it has received (cmn.ClusterConfFile)
+// and applies the values on top of its own (local) configuration, remembering its
+// own values of the respective variables; when a value is no longer set - e.g.,
+// after a rollback to the version that did not have it - the node restores its
+// own. Previous versions (up to maxConfHistory) travel with the current one, so
+// that whichever proxy is primary can diff them and roll back.
+//
+// Prior to creating the next version the primary has each (responding) node validate
+// the values that change for it (see validateClusterConf) and fails the request with
+// the per-node errors, if any.
+//
+
+const maxConfHistory = 32
+
+type (
+	// clusterConf is immutable and versioned - a REVS (see metasync.go)
+	clusterConf struct {
+		cmn.ClusterConf
+		History []cmn.ClusterConf `json:"history,omitempty"` // previous versions, the oldest first
+	}
+	confowner struct {
+		revsOwner
+		daemonID string
+		setfn    func(cmn.SimpleKVs) string // applies named values to the node's config
+		orig     cmn.SimpleKVs              // the node's own values of the variables set cluster-wide
+	}
+	// persistent state of the confowner
+	confState struct {
+		Conf *clusterConf  `json:"conf"`
+		Orig cmn.SimpleKVs `json:"orig"`
+	}
+)
+
+func newClusterConf() *clusterConf {
+	return &clusterConf{ClusterConf: cmn.ClusterConf{Values: cmn.SimpleKVs{}}}
+}
+
+// revs interface
+func (c *clusterConf) tag() string    { return conftag }
+func (c *clusterConf) version() int64 { return c.Version }
+
+func (c *clusterConf) marshal() ([]byte, error) {
+	return jsonCompat.Marshal(c)
+}
+
+func copyKVs(kvs cmn.SimpleKVs) cmn.SimpleKVs {
+	dst := make(cmn.SimpleKVs, len(kvs))
+	for k, v := range kvs {
+		dst[k] = v
+	}
+	return dst
+}
+
+func copyConf(src *cmn.ClusterConf) cmn.ClusterConf {
+	dst := *src
+	dst.Values = copyKVs(src.Values)
+	if len(src.Overrides) > 0 {
+		dst.Overrides = make(map[string]cmn.SimpleKVs, len(src.Overrides))
+		for id, kvs := range src.Overrides {
+			dst.Overrides[id] = copyKVs(kvs)
+		}
+	}
+	return dst
+}
+
+// clone returns the next version, with the current one moved to history
+func (c *clusterConf) clone() *clusterConf {
+	dst := &clusterConf{ClusterConf: copyConf(&c.ClusterConf)}
+	dst.Version++
+	dst.History = append(make([]cmn.ClusterConf, 0, len(c.History)+1), c.History...)
+	if c.Version > 0 {
+		dst.History = append(dst.History, c.ClusterConf)
+	}
+	if l := len(dst.History); l > maxConfHistory {
+		dst.History = dst.History[l-maxConfHistory:]
+	}
+	return dst
+}
+
+// find returns the given version, current or previous
+func (c *clusterConf) find(version int64) (*cmn.ClusterConf, bool) {
+	if version == c.Version {
+		return &c.ClusterConf, true
+	}
+	for i := range c.History {
+		if c.History[i].Version == version {
+			return &c.History[i], true
+		}
+	}
+	return nil, false
+}
+
+// set sets cluster-wide value or, if node is not empty, the node's override
+func (c *clusterConf) set(node, name, value string) {
+	if node == "" {
+		c.Values[name] = value
+		return
+	}
+	if c.Overrides == nil {
+		c.Overrides = make(map[string]cmn.SimpleKVs)
+	}
+	if c.Overrides[node] == nil {
+		c.Overrides[node] = make(cmn.SimpleKVs)
+	}
+	c.Overrides[node][name] = value
+}
+
+func (c *clusterConf) unset(node, name string) (ok bool) {
+	if node == "" {
+		if _, ok = c.Values[name]; ok {
+			delete(c.Values, name)
+		}
+		return
+	}
+	if _, ok = c.Overrides[node][name]; ok {
+		delete(c.Overrides[node], name)
+		if len(c.Overrides[node]) == 0 {
+			delete(c.Overrides, node)
+		}
+	}
+	return
+}
+
+// rollback makes a previous version (its values and overrides) current again
+func (c *clusterConf) rollback(prev *cmn.ClusterConf) {
+	restored := copyConf(prev)
+	c.Values, c.Overrides = restored.Values, restored.Overrides
+}
+
+// effective returns the values that apply to a given node
+func (c *clusterConf) effective(daemonID string) cmn.SimpleKVs {
+	eff := copyKVs(c.Values)
+	for name, value := range c.Overrides[daemonID] {
+		eff[name] = value
+	}
+	return eff
+}
+
+// diffConf returns the changes between two versions - cluster-wide first, sorted by name
+func diffConf(from, to *cmn.ClusterConf) []cmn.ConfChange {
+	var (
+		changes = make([]cmn.ConfChange, 0, 8)
+		diff    = func(node string, a, b cmn.SimpleKVs) {
+			for name, v := range a {
+				if w := b[name]; w != v {
+					changes = append(changes, cmn.ConfChange{Node: node, Name: name, From: v, To: w})
+				}
+			}
+			for name, w := range b {
+				if _, ok := a[name]; !ok {
+					changes = append(changes, cmn.ConfChange{Node: node, Name: name, To: w})
+				}
+			}
+		}
+	)
+	diff("", from.Values, to.Values)
+	for node, kvs := range from.Overrides {
+		diff(node, kvs, to.Overrides[node])
+	}
+	for node, kvs := range to.Overrides {
+		if _, ok := from.Overrides[node]; !ok {
+			diff(node, nil, kvs)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Node != changes[j].Node {
+			return changes[i].Node < changes[j].Node
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+//
+// confowner
+//
+
+// init loads the persisted cluster-wide configuration, if any, and applies it
+func (r *confowner) init(confdir, daemonID string, setfn func(cmn.SimpleKVs) string) {
+	r.pathname, r.what = filepath.Join(confdir, cmn.ClusterConfFile), "cluster config"
+	r.daemonID, r.setfn = daemonID, setfn
+	state := &confState{}
+	if err := cmn.LocalLoad(r.pathname, state); err != nil || state.Conf == nil {
+		state = &confState{Conf: newClusterConf()}
+	}
+	r.orig = state.Orig
+	if r.orig == nil {
+		r.orig = make(cmn.SimpleKVs)
+	}
+	r.Lock()
+	r.apply(state.Conf)
+	r.Unlock()
+}
+
+// get never returns nil
+func (r *confowner) get() *clusterConf {
+	if conf := r.getRevs(); conf != nil {
+		return conf.(*clusterConf)
+	}
+	return newClusterConf()
+}
+
+// apply (under lock) sets the values that apply to this node, restores the node's own
+// values of the variables that are no longer set, and makes the new version current
+func (r *confowner) apply(conf *clusterConf) {
+	var (
+		eff    = conf.effective(r.daemonID)
+		kvs    = make(cmn.SimpleKVs, len(eff))
+		config = cmn.GCO.Get()
+	)
+	for name, value := range eff {
+		if _, ok := r.orig[name]; !ok {
+			orig, err := cmn.ConfigValue(config, name)
+			if err != nil {
+				glog.Errorf("cluster config v%d: %v", conf.version(), err)
+				continue
+			}
+			r.orig[name] = orig
+		}
+		kvs[name] = value
+	}
+	for name, orig := range r.orig {
+		if _, ok := eff[name]; !ok {
+			kvs[name] = orig
+			delete(r.orig, name)
+		}
+	}
+	if len(kvs) > 0 && r.setfn != nil {
+		if errstr := r.setfn(kvs); errstr != "" {
+			// one at a time, so that a single bad value would not block the rest
+			glog.Errorf("cluster config v%d: %s", conf.version(), errstr)
+			for name, value := range kvs {
+				if errstr := r.setfn(cmn.SimpleKVs{name: value}); errstr != "" {
+					glog.Errorf("cluster config v%d: %s", conf.version(), errstr)
+				}
+			}
+		}
+	}
+	r.persist(conf)
+	r.put(conf)
+}
+
+// persist stores the version along with the node's own values (confState)
+func (r *confowner) persist(conf *clusterConf) {
+	r.save(&confState{Conf: conf, Orig: r.orig}, conf.version())
+}
+
+// synchronize (non-primary) accepts and applies the newer version
+func (r *confowner) synchronize(newconf *clusterConf) {
+	r.revsOwner.synchronize(newconf, func(revs) {
+		r.apply(newconf)
+		glog.Infof("cluster config v%d: %s", newconf.version(), newconf.Action)
+	})
+}
+
+func (h *httprunner) extractClusterConf(payload cmn.SimpleKVs) (*clusterConf, string) {
+	conf := newClusterConf()
+	if ok, errstr := extractRevs(payload, conftag, "cluster config", conf); !ok {
+		return nil, errstr
+	}
+	if conf.Values == nil {
+		conf.Values = cmn.SimpleKVs{}
+	}
+	return conf, ""
+}
+
+//
+// proxy: API
+//
+
+// GET /v1/cluster?what=clusterconf[&version=N]
+// GET /v1/cluster?what=confhistory
+// GET /v1/cluster?what=confdiff&from=N[&to=M]
+func (p *proxyrunner) httpGetClusterConf(w http.ResponseWriter, r *http.Request, what string) {
+	var (
+		v       interface{}
+		conf    = p.confowner.get()
+		query   = r.URL.Query()
+		version = func(param string, dflt int64) (*cmn.ClusterConf, bool) {
+			n := dflt
+			if s := query.Get(param); s != "" {
+				var err error
+				if n, err = strconv.ParseInt(s, 10, 64); err != nil {
+					p.invalmsghdlr(w, r, fmt.Sprintf("invalid %s=%s", param, s))
+					return nil, false
+				}
+			}
+			c, ok := conf.find(n)
+			if !ok {
+				p.invalmsghdlr(w, r, fmt.Sprintf("cluster config v%d not found (current v%d, %d previous kept)",
+					n, conf.version(), len(conf.History)), http.StatusNotFound)
+			}
+			return c, ok
+		}
+	)
+	switch what {
+	case cmn.GetWhatClusterConf:
+		c, ok := version(cmn.URLParamConfVersion, conf.version())
+		if !ok {
+			return
+		}
+		v = c
+	case cmn.GetWhatConfHistory:
+		v = append(append([]cmn.ClusterConf{}, conf.History...), conf.ClusterConf)
+	case cmn.GetWhatConfDiff:
+		if query.Get(cmn.URLParamConfFrom) == "" {
+			p.invalmsghdlr(w, r, fmt.Sprintf("%s: missing %s version", what, cmn.URLParamConfFrom))
+			return
+		}
+		from, ok := version(cmn.URLParamConfFrom, 0)
+		if !ok {
+			return
+		}
+		to, ok := version(cmn.URLParamConfTo, conf.version())
+		if !ok {
+			return
+		}
+		v = diffConf(from, to)
+	}
+	jsbytes, err := jsoniter.Marshal(v)
+	cmn.AssertNoErr(err)
+	p.writeJSON(w, r, jsbytes, what)
+}
+
+// validateConfNode checks that the node to override the cluster-wide values for is a cluster member
+func (p *proxyrunner) validateConfNode(w http.ResponseWriter, r *http.Request, node string) bool {
+	if node == "" {
+		return true
+	}
+	smap := p.smapowner.get()
+	if smap.GetTarget(node) == nil && smap.GetProxy(node) == nil {
+		p.invalmsghdlr(w, r, fmt.Sprintf("node %s is not present in the %s", node, smap.pp()), http.StatusNotFound)
+		return false
+	}
+	return true
+}
+
+// setconfig: PUT /v1/cluster/setconfig?n1=v1&n2=v2...[&node=ID] or {"action": "setconfig", ...}
+func (p *proxyrunner) setClusterConf(w http.ResponseWriter, r *http.Request, msg *cmn.ActionMsg, node string,
+	kvs cmn.SimpleKVs) {
+	delete(kvs, cmn.ActPersist) // (the cluster config is always persisted)
+	if len(kvs) == 0 {
+		p.invalmsghdlr(w, r, fmt.Sprintf("%s: nothing to set", msg.Action))
+		return
+	}
+	values := make(cmn.SimpleKVs, len(kvs))
+	names := make([]string, 0, len(kvs))
+	for name, value := range kvs {
+		name = cmn.ConfigKey(name)
+		values[name] = value
+		names = append(names, name+"="+value)
+	}
+	sort.Strings(names)
+	p.updateClusterConf(w, r, msg, confAction(msg.Action, node, names), func(conf *clusterConf) (string, int) {
+		for name, value := range values {
+			conf.set(node, name, value)
+		}
+		return "", 0
+	})
+}
+
+// PUT /v1/cluster {"action": "unsetconfig", "name": ...}[?node=ID]
+// PUT /v1/cluster {"action": "rollbackconfig", "value": version}
+func (p *proxyrunner) changeClusterConf(w http.ResponseWriter, r *http.Request, msg *cmn.ActionMsg, node string) {
+	if msg.Action == cmn.ActUnsetConfig {
+		name := cmn.ConfigKey(msg.Name)
+		if name == "" {
+			p.invalmsghdlr(w, r, fmt.Sprintf("%s: config name is required", msg.Action))
+			return
+		}
+		p.updateClusterConf(w, r, msg, confAction(msg.Action, node, []string{name}), func(conf *clusterConf) (string, int) {
+			if !conf.unset(node, name) {
+				return fmt.Sprintf("%s: %q is not set", msg.Action, name), http.StatusNotFound
+			}
+			return "", 0
+		})
+		return
+	}
+	version, err := strconv.ParseInt(fmt.Sprintf("%v", msg.Value), 10, 64)
+	if err != nil {
+		p.invalmsghdlr(w, r, fmt.Sprintf("%s: invalid version (%+v, %T)", msg.Action, msg.Value, msg.Value))
+		return
+	}
+	p.updateClusterConf(w, r, msg, fmt.Sprintf("%s v%d", msg.Action, version), func(conf *clusterConf) (string, int) {
+		prev, ok := p.confowner.get().find(version)
+		if !ok {
+			return fmt.Sprintf("%s: v%d not found", msg.Action, version), http.StatusNotFound
+		}
+		conf.rollback(prev)
+		return "", 0
+	})
+}
+
+func confAction(action, node string, names []string) string {
+	s := action + " " + strings.Join(names, " ")
+	if node != "" {
+		s += " (node " + node + ")"
+	}
+	return s
+}
+
+// updateClusterConf creates, applies, and distributes the next version - only
+// when the primary has quorum, so that the primary in the minority would not fork it
+func (p *proxyrunner) updateClusterConf(w http.ResponseWriter, r *http.Request, msg *cmn.ActionMsg, action string,
+	change func(conf *clusterConf) (string, int)) {
+	if !p.checkQuorum(w, r, msg.Action) {
+		return
+	}
+	p.confowner.Lock()
+	conf := p.confowner.get()
+	clone := conf.clone()
+	errstr, status := change(clone)
+	p.confowner.Unlock()
+	if errstr != "" {
+		p.invalmsghdlr(w, r, errstr, status)
+		return
+	}
+	// validating outside the lock (the nodes may take a while to respond) - and then
+	// making sure that the version the validated changes are based upon is still current
+	if errstr := p.validateClusterConf(msg.Action, conf, clone); errstr != "" {
+		p.invalmsghdlr(w, r, errstr)
+		return
+	}
+	p.confowner.Lock()
+	if v := p.confowner.get().version(); v != conf.version() {
+		p.confowner.Unlock()
+		errstr := fmt.Sprintf("%s: cluster config v%d has changed (to v%d) while validating, please retry",
+			msg.Action, conf.version(), v)
+		p.invalmsghdlr(w, r, errstr, http.StatusConflict)
+		return
+	}
+	clone.Time, clone.Action = time.Now(), action
+	p.confowner.apply(clone)
+	p.confowner.Unlock()
+
+	msgInt := p.newActionMsgInternal(msg, nil, nil)
+	p.metasyncer.sync(true, clone, msgInt)
+	p.recordEvent(cmn.EventConfig, fmt.Sprintf("%s (v%d)", action, clone.version()))
+}
+
+// changedValues returns the values that the next version changes for a given node
+func changedValues(daemonID string, conf, next *clusterConf) cmn.SimpleKVs {
+	var (
+		curr    = conf.effective(daemonID)
+		changed = make(cmn.SimpleKVs)
+	)
+	for name, value := range next.effective(daemonID) {
+		if v, ok := curr[name]; !ok || v != value {
+			changed[name] = value
+		}
+	}
+	return changed
+}
+
+// validateClusterConf has each node validate the values that the next version changes
+// for it - against the node's own effective configuration (dry-run setconfig); returns
+// the per-node failures, if any. The nodes that do not respond are skipped - they
+// will get (and apply) the next version via metasync when they are back.
+func (p *proxyrunner) validateClusterConf(action string, conf, next *clusterConf) (errstr string) {
+	var (
+		smap   = p.smapowner.get()
+		config = cmn.GCO.Get()
+		mtx    sync.Mutex
+		wg     = &sync.WaitGroup{}
+		errs   = make([]string, 0, 4)
+		fail   = func(sid, errstr string) {
+			mtx.Lock()
+			errs = append(errs, sid+": "+errstr)
+			mtx.Unlock()
+		}
+	)
+	for _, nodemap := range []cluster.NodeMap{smap.Pmap, smap.Tmap} {
+		for sid, si := range nodemap {
+			kvs := changedValues(sid, conf, next)
+			if len(kvs) == 0 {
+				continue
+			}
+			if sid == p.si.DaemonID {
+				if errstr := cmn.ValidateConfigMany(kvs); errstr != "" {
+					fail(sid, errstr)
+				}
+				continue
+			}
+			query := url.Values{cmn.URLParamDryRun: []string{"true"}}
+			for name, value := range kvs {
+				query.Set(name, value)
+			}
+			wg.Add(1)
+			go func(si *cluster.Snode, query url.Values) {
+				defer wg.Done()
+				args := callArgs{
+					si: si,
+					req: reqArgs{
+						method: http.MethodPut,
+						path:   cmn.URLPath(cmn.Version, cmn.Daemon, cmn.ActSetConfig),
+						query:  query,
+					},
+					timeout: config.Timeout.CplaneOperation,
+				}
+				res := p.call(args)
+				if res.err == nil {
+					return
+				}
+				if res.status == 0 { // no response
+					glog.Warningf("%s: skipping validation by %s: %v", action, si, res.err)
+					return
+				}
+				errstr := res.errstr
+				if errstr == "" {
+					errstr = res.err.Error()
+				}
+				fail(si.DaemonID, errstr)
+			}(si, query)
+		}
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		errstr = fmt.Sprintf("%s: rejected by %d node(s): %s", action, len(errs), strings.Join(errs, "; "))
+	}
+	return
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+func TestClusterConfHistory(t *testing.T) {
+	conf := newClusterConf()
+	for i := 0; i < maxConfHistory+5; i++ {
+		conf = conf.clone()
+		conf.set("", "lru.lowwm", string(rune('a'+i%26)))
+	}
+	if conf.version() != maxConfHistory+5 || len(conf.History) != maxConfHistory {
+		t.Fatalf("expecting v%d with %d previous versions, got v%d with %d",
+			maxConfHistory+5, maxConfHistory, conf.version(), len(conf.History))
+	}
+	if _, ok := conf.find(4); ok {
+		t.Fatal("v4 must have been dropped from history")
+	}
+	if prev, ok := conf.find(conf.version() - 1); !ok || prev.Values["lru.lowwm"] == conf.Values["lru.lowwm"] {
+		t.Fatalf("expecting the previous version with a different value, got %+v", prev)
+	}
+	// previous versions are not affected by the changes to the current one
+	prev, _ := conf.find(conf.version() - 1)
+	before := prev.Values["lru.lowwm"]
+	conf.set("", "lru.lowwm", "changed")
+	if prev.Values["lru.lowwm"] != before {
+		t.Fatal("history must not share values with the current version")
+	}
+}
+
+func TestClusterConfDiffRollback(t *testing.T) {
+	v1 := newClusterConf().clone()
+	v1.set("", "lru.lowwm", "60")
+	v1.set("", "lru.highwm", "80")
+
+	v2 := v1.clone()
+	v2.set("", "lru.lowwm", "70")
+	v2.unset("", "lru.highwm")
+	v2.set("t1", "rebalance.bandwidth", "1GB")
+	v2.set("", "mirror.enabled", "true")
+
+	expected := []cmn.ConfChange{
+		{Name: "lru.highwm", From: "80"},
+		{Name: "lru.lowwm", From: "60", To: "70"},
+		{Name: "mirror.enabled", To: "true"},
+		{Node: "t1", Name: "rebalance.bandwidth", To: "1GB"},
+	}
+	if changes := diffConf(&v1.ClusterConf, &v2.ClusterConf); !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("expecting %+v, got %+v", expected, changes)
+	}
+	if eff := v2.effective("t1"); eff["rebalance.bandwidth"] != "1GB" || eff["lru.lowwm"] != "70" {
+		t.Fatalf("unexpected effective values: %+v", eff)
+	}
+	if eff := v2.effective("t2"); len(eff) != 2 {
+		t.Fatalf("unexpected effective values: %+v", eff)
+	}
+
+	v3 := v2.clone()
+	prev, ok := v3.find(1)
+	if !ok {
+		t.Fatal("v1 not found")
+	}
+	v3.rollback(prev)
+	if changes := diffConf(&v1.ClusterConf, &v3.ClusterConf); len(changes) != 0 {
+		t.Fatalf("expecting no changes after rollback, got %+v", changes)
+	}
+}
+
+func TestClusterConfApply(t *testing.T) {
+	var (
+		applied = make(cmn.SimpleKVs)
+		r       = &confowner{daemonID: "t1", orig: make(cmn.SimpleKVs)}
+	)
+	r.setfn = func(kvs cmn.SimpleKVs) string {
+		for k, v := range kvs {
+			applied[k] = v
+		}
+		return ""
+	}
+	orig, err := cmn.ConfigValue(cmn.GCO.Get(), "lru.lowwm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v1 := newClusterConf().clone()
+	v1.set("", "lru.lowwm", "60")
+	v1.set("t1", "lru.lowwm", "65")
+	r.apply(v1)
+	if applied["lru.lowwm"] != "65" || r.orig["lru.lowwm"] != orig || r.get() != v1 {
+		t.Fatalf("expecting the override applied, got %+v (orig %+v)", applied, r.orig)
+	}
+
+	v2 := v1.clone()
+	v2.unset("t1", "lru.lowwm")
+	r.apply(v2)
+	if applied["lru.lowwm"] != "60" {
+		t.Fatalf("expecting the cluster-wide value applied, got %+v", applied)
+	}
+
+	v3 := v2.clone()
+	v3.unset("", "lru.lowwm")
+	r.apply(v3)
+	if applied["lru.lowwm"] != orig || len(r.orig) != 0 {
+		t.Fatalf("expecting the node's own value restored, got %+v (orig %+v)", applied, r.orig)
+	}
+}
+
+func TestClusterConfChangedValues(t *testing.T) {
+	v1 := newClusterConf().clone()
+	v1.set("", "lru.lowwm", "60")
+	v1.set("t1", "lru.lowwm", "50")
+
+	v2 := v1.clone()
+	v2.set("", "lru.lowwm", "70")
+	v2.set("", "mirror.enabled", "true")
+
+	if changed := changedValues("t2", v1, v2); !reflect.DeepEqual(changed, cmn.SimpleKVs{"lru.lowwm": "70", "mirror.enabled": "true"}) {
+		t.Errorf("t2: unexpected changes %+v", changed)
+	}
+	// the node's override stays in effect
+	if changed := changedValues("t1", v1, v2); !reflect.DeepEqual(changed, cmn.SimpleKVs{"mirror.enabled": "true"}) {
+		t.Errorf("t1: unexpected changes %+v", changed)
+	}
+	// rollback
+	v3 := v2.clone()
+	v3.rollback(&v1.ClusterConf)
+	if changed := changedValues("t2", v2, v3); !reflect.DeepEqual(changed, cmn.SimpleKVs{"lru.lowwm": "60"}) {
+		t.Errorf("rollback: unexpected changes %+v", changed)
+	}
+}
+
+func TestClusterConfValidateSkipsDown(t *testing.T) {
+	var (
+		primary = newPrimary()
+		reject  = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "invalid lru.lowwm", http.StatusBadRequest)
+		}))
+		down = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	)
+	defer reject.Close()
+	down.Close()
+	primary.httpclient = &http.Client{}
+
+	addTarget := func(id, u string) {
+		clone := primary.smapowner.get().clone()
+		clone.addTarget(newSnode(id, httpProto, serverTCPAddr(u), &net.TCPAddr{}, &net.TCPAddr{}))
+		clone.Version++
+		primary.smapowner.put(clone)
+	}
+	conf := newClusterConf()
+	next := conf.clone()
+	next.set("t1", "lru.lowwm", "70")
+	next.set("t2", "lru.lowwm", "70")
+
+	addTarget("t1", down.URL)
+	if errstr := primary.validateClusterConf(cmn.ActSetConfig, conf, next); errstr != "" {
+		t.Errorf("expecting the node that is down to be skipped, got %q", errstr)
+	}
+	addTarget("t2", reject.URL)
+	if errstr := primary.validateClusterConf(cmn.ActSetConfig, conf, next); !strings.Contains(errstr, "rejected by 1 node(s): t2") {
+		t.Errorf("expecting t2 (only) to reject the change, got %q", errstr)
+	}
+}
@@ -128,9 +128,54 @@ func (m *smapX) delTarget(sid string) {
 		cmn.AssertMsg(false, fmt.Sprintf("FATAL: target: %s is not in the smap: %s", sid, m.pp()))
 	}
 	delete(m.Tmap, sid)
+	delete(m.Maintenance, sid)
+	delete(m.Weights, sid)
 	m.Version++
 }
 
+// NOTE: does not increment the version - the caller does
+func (m *smapX) setMaintenance(sid, mode string) {
+	if mode == "" {
+		delete(m.Maintenance, sid)
+		return
+	}
+	if m.Maintenance == nil {
+		m.Maintenance = make(cmn.SimpleKVs, 1)
+	}
+	m.Maintenance[sid] = mode
+}
+
+// NOTE: does not increment the version - the caller does
+func (m *smapX) setWeight(sid string, weight int64) {
+	if weight <= 0 {
+		delete(m.Weights, sid)
+		return
+	}
+	if m.Weights == nil {
+		m.Weights = make(map[string]int64, 1)
+	}
+	m.Weights[sid] = weight
+}
+
+// checkWeight returns an error if the weight declared by the (registering) target
+// does not compare with the ones declared by the others: capacity vs. number or default
+func (m *smapX) checkWeight(nsi *cluster.Snode) (errstr string) {
+	for sid, si := range m.Tmap {
+		if sid != nsi.DaemonID && si.WeightCap != nsi.WeightCap {
+			return fmt.Sprintf("%s declares %s weight, %s - %s: cannot mix the two",
+				tname(nsi), weightKind(nsi), tname(si), weightKind(si))
+		}
+	}
+	return
+}
+
+func weightKind(si *cluster.Snode) string {
+	if si.WeightCap {
+		return "capacity"
+	}
+	return "numeric (or default)"
+}
+
 func (m *smapX) delProxy(pid string) {
 	if m.GetProxy(pid) == nil {
 		cmn.AssertMsg(false, fmt.Sprintf("FATAL: proxy: %s is not in the smap: %s", pid, m.pp()))
@@ -147,6 +192,7 @@ func (m *smapX) clone() *smapX {
 
 func (m *smapX) deepcopy(dst *smapX) {
 	cmn.CopyStruct(dst, m)
+	dst.ClearPlacement() // (the copy is to be modified)
 	dst.init(len(m.Tmap), len(m.Pmap), len(m.NonElects))
 	for id, v := range m.Tmap {
 		dst.Tmap[id] = v
@@ -157,6 +203,20 @@ func (m *smapX) deepcopy(dst *smapX) {
 	for id, v := range m.NonElects {
 		dst.NonElects[id] = v
 	}
+	dst.Maintenance = nil // (not sharing with the source)
+	if len(m.Maintenance) > 0 {
+		dst.Maintenance = make(cmn.SimpleKVs, len(m.Maintenance))
+		for id, v := range m.Maintenance {
+			dst.Maintenance[id] = v
+		}
+	}
+	dst.Weights = nil // ditto
+	if len(m.Weights) > 0 {
+		dst.Weights = make(map[string]int64, len(m.Weights))
+		for id, v := range m.Weights {
+			dst.Weights[id] = v
+		}
+	}
 }
 
 func (m *smapX) merge(dst *smapX) {
@@ -215,6 +275,7 @@ func (r *smapowner) put(smap *smapX) {
 	for _, snode := range smap.Pmap {
 		snode.Digest()
 	}
+	smap.InitPlacement()
 	atomic.StorePointer(&r.smap, unsafe.Pointer(smap))
 
 	if r.listeners != nil {
@@ -257,7 +318,7 @@ func (r *smapowner) persist(newsmap *smapX, saveSmap bool) (errstr string) {
 
 	origURL := config.Proxy.PrimaryURL
 	config.Proxy.PrimaryURL = newsmap.ProxySI.PublicNet.DirectURL
-	if err := cmn.LocalSave(confFile, config); err != nil {
+	if err := cmn.SaveConfigFile(confFile, config); err != nil {
 		errstr = fmt.Sprintf("Error writing config file %s, err: %v", confFile, err)
 		config.Proxy.PrimaryURL = origURL
 		return
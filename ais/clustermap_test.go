@@ -0,0 +1,38 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+)
+
+func TestSmapCheckWeight(t *testing.T) {
+	smap := newSmap()
+	smap.addTarget(&cluster.Snode{DaemonID: "t1", Weight: 1800, WeightCap: true})
+	smap.addTarget(&cluster.Snode{DaemonID: "t2", Weight: 3600, WeightCap: true})
+	tests := []struct {
+		si    *cluster.Snode
+		valid bool
+	}{
+		{&cluster.Snode{DaemonID: "t3", Weight: 900, WeightCap: true}, true},
+		{&cluster.Snode{DaemonID: "t3", Weight: 2}, false},
+		{&cluster.Snode{DaemonID: "t3"}, false},
+		// re-registering with the other kind while being the only one
+		{&cluster.Snode{DaemonID: "t1", Weight: 2}, false},
+	}
+	for i, test := range tests {
+		if errstr := smap.checkWeight(test.si); (errstr == "") != test.valid {
+			t.Errorf("test case %d: expecting valid=%t, got %q", i, test.valid, errstr)
+		}
+	}
+
+	// a single target may change its kind of weight
+	smap = newSmap()
+	smap.addTarget(&cluster.Snode{DaemonID: "t1", Weight: 1800, WeightCap: true})
+	if errstr := smap.checkWeight(&cluster.Snode{DaemonID: "t1"}); errstr != "" {
+		t.Error(errstr)
+	}
+}
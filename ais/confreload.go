@@ -0,0 +1,163 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+//
+// Reloading the configuration file
+//
+// The node re-reads its configuration file (upon SIGHUP or reloadconfig request),
+// validates it as a whole, and applies the values that changed in the file since
+// it was last loaded (or stored by the node itself) with the same setter setconfig
+// uses - so that the config listeners get notified. Values the file does not change
+// (e.g., command-line overrides and setconfig) stay in effect. Values that require
+// restart are reported (and logged) but not applied - by each reload, until the
+// restart. Values set by the cluster-wide configuration stay in effect: the file
+// only updates the node's own values that get restored once the cluster-wide ones
+// are unset.
+//
+
+// reload (dry-run: only reports) the changes in the configuration file
+func (r *confowner) reload(dryRun bool) (*cmn.ConfigReload, error) {
+	changes, file, err := cmn.DiffConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	r.Lock()
+	defer r.Unlock()
+	var (
+		reload     = &cmn.ConfigReload{DryRun: dryRun, Changes: make([]cmn.ConfigFileChange, 0, len(changes))}
+		eff        = r.get().effective(r.daemonID)
+		kvs        = make(cmn.SimpleKVs)
+		overridden = make(cmn.SimpleKVs)
+	)
+	for _, change := range changes {
+		if _, ok := eff[change.Name]; ok {
+			orig, ok := r.orig[change.Name]
+			if ok && orig == change.To {
+				continue
+			}
+			if ok {
+				change.From = orig
+			}
+			change.Overridden = true
+			overridden[change.Name] = change.To
+		} else if !change.Restart {
+			kvs[change.Name] = change.To
+		}
+		reload.Changes = append(reload.Changes, change)
+	}
+	if dryRun {
+		return reload, nil
+	}
+	if len(kvs) > 0 && r.setfn != nil {
+		if errstr := r.setfn(kvs); errstr != "" {
+			return nil, errors.New(errstr)
+		}
+	}
+	if len(overridden) > 0 {
+		for name, value := range overridden {
+			r.orig[name] = value
+		}
+		r.persist(r.get())
+	}
+	cmn.GCO.SetFileConfig(file) // the next reload reports only what changes from now on (and what requires restart)
+	return reload, nil
+}
+
+// reloadConfig is called upon SIGHUP
+func (h *httprunner) reloadConfig() {
+	if h.confowner == nil {
+		glog.Warningf("%s: not ready to reload config", h.si)
+		return
+	}
+	reload, err := h.confowner.reload(false)
+	if err != nil {
+		glog.Errorf("%s: failed to reload config, err: %v", h.si, err)
+		return
+	}
+	for _, change := range reload.Changes {
+		switch {
+		case change.Overridden:
+			glog.Infof("%s: %s=%s (overridden by cluster config)", cmn.ActReloadConfig, change.Name, change.To)
+		case change.Restart:
+			glog.Warningf("%s: %s=%s requires restart", cmn.ActReloadConfig, change.Name, change.To)
+		}
+	}
+	glog.Infof("%s: %s done, %d change(s)", h.si, cmn.ActReloadConfig, len(reload.Changes))
+}
+
+// PUT /v1/daemon {"action": "reloadconfig"}[?dry_run=true]
+func (h *httprunner) httpReloadConfig(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := parsebool(r.URL.Query().Get(cmn.URLParamDryRun))
+	if err != nil {
+		h.invalmsghdlr(w, r, fmt.Sprintf("invalid %s: %v", cmn.URLParamDryRun, err))
+		return
+	}
+	reload, err := h.confowner.reload(dryRun)
+	if err != nil {
+		h.invalmsghdlr(w, r, fmt.Sprintf("%s: %v", cmn.ActReloadConfig, err))
+		return
+	}
+	jsbytes, err := jsoniter.Marshal(reload)
+	cmn.AssertNoErr(err)
+	h.writeJSON(w, r, jsbytes, cmn.ActReloadConfig)
+}
+
+// PUT /v1/cluster {"action": "reloadconfig"}[?dry_run=true] - all nodes, results by daemon ID
+func (p *proxyrunner) reloadClusterConfig(w http.ResponseWriter, r *http.Request, msg *cmn.ActionMsg) {
+	query := r.URL.Query()
+	dryRun, err := parsebool(query.Get(cmn.URLParamDryRun))
+	if err != nil {
+		p.invalmsghdlr(w, r, fmt.Sprintf("invalid %s: %v", cmn.URLParamDryRun, err))
+		return
+	}
+	var (
+		smap    = p.smapowner.get()
+		reloads = make(map[string]*cmn.ConfigReload, smap.CountTargets()+smap.CountProxies())
+	)
+	msgbytes, err := jsoniter.Marshal(msg)
+	cmn.AssertNoErr(err)
+	results := p.broadcastTo(
+		cmn.URLPath(cmn.Version, cmn.Daemon),
+		query,
+		http.MethodPut,
+		msgbytes,
+		smap,
+		cmn.GCO.Get().Timeout.CplaneOperation,
+		cmn.NetworkIntraControl,
+		cluster.AllNodes,
+	)
+	for res := range results {
+		reload := &cmn.ConfigReload{DryRun: dryRun}
+		if res.err != nil {
+			reload.Err = res.errstr
+			if reload.Err == "" {
+				reload.Err = res.err.Error()
+			}
+		} else if err := jsoniter.Unmarshal(res.outjson, reload); err != nil {
+			reload.Err = err.Error()
+		}
+		reloads[res.si.DaemonID] = reload
+	}
+	if reload, err := p.confowner.reload(dryRun); err != nil {
+		reloads[p.si.DaemonID] = &cmn.ConfigReload{DryRun: dryRun, Err: err.Error()}
+	} else {
+		reloads[p.si.DaemonID] = reload
+	}
+	jsbytes, err := jsoniter.Marshal(reloads)
+	cmn.AssertNoErr(err)
+	p.writeJSON(w, r, jsbytes, cmn.ActReloadConfig)
+}
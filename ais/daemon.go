@@ -14,6 +14,7 @@ import (
 	"github.com/NVIDIA/aistore/atime"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/governor"
 	"github.com/NVIDIA/aistore/health"
 	"github.com/NVIDIA/aistore/ios"
 	"github.com/NVIDIA/aistore/memsys"
@@ -38,6 +39,8 @@ const (
 	xmetasyncer      = "metasyncer"
 	xfshc            = "fshc"
 	xreadahead       = "readahead"
+	xjobscheduler    = "jobscheduler"
+	xgovernor        = "governor"
 	//lint:ignore U1000 unused
 	xreplication = "replication" // TODO: fix replication
 )
@@ -49,6 +52,8 @@ type (
 		confjson string        // JSON formatted "{name: value, ...}" string to override selected knob(s)
 		ntargets int           // expected number of targets in a starting-up cluster (proxy only)
 		persist  bool          // true: make cmn.ConfigCLI settings permanent, false: leave them transient
+		weight   string        // target's placement weight: number | "capacity" (target only)
+		labels   string        // target's failure domains: "zone=...,rack=...,host=..." (target only)
 	}
 	// daemon instance: proxy or storage target
 	daemon struct {
@@ -137,6 +142,8 @@ func init() {
 	flag.BoolVar(&clivars.persist, "persist", false, "true: apply command-line args to the configuration and save the latter to disk\nfalse: keep it transient (for this run only)")
 
 	flag.IntVar(&clivars.ntargets, "ntargets", 0, "number of storage targets to expect at startup (hint, proxy-only)")
+	flag.StringVar(&clivars.weight, "weight", "", "placement weight relative to other targets: positive number or \"capacity\" (in GiB, same for all targets); default - same for all (target-only)")
+	flag.StringVar(&clivars.labels, "labels", "", "failure domains of the target as comma-separated zone=...,rack=...,host=... (target-only)")
 
 	flag.BoolVar(&dryRun.disk, "nodiskio", false, "dry-run: if true, no disk operations for GET and PUT")
 	flag.BoolVar(&dryRun.network, "nonetio", false, "dry-run: if true, no network operations for GET and PUT")
@@ -180,6 +187,7 @@ func aisinit(version, build string) {
 	var (
 		err         error
 		confChanged bool
+		sigr        = &sigrunner{}
 	)
 	flag.Parse()
 	cmn.AssertMsg(clivars.role == xproxy || clivars.role == xtarget, "Invalid flag: role="+clivars.role)
@@ -196,7 +204,7 @@ func aisinit(version, build string) {
 	confChanged = cmn.LoadConfig(&clivars.config)
 	if confChanged {
 		config := cmn.GCO.Get()
-		if err := cmn.LocalSave(cmn.GCO.GetConfigFile(), config); err != nil {
+		if err := cmn.SaveConfigFile(cmn.GCO.GetConfigFile(), config); err != nil {
 			glog.Errorf("CLI %s: failed to write, err: %v", cmn.ActSetConfig, err)
 			os.Exit(1)
 		}
@@ -217,6 +225,7 @@ func aisinit(version, build string) {
 		p := &proxyrunner{}
 		p.initSI()
 		ctx.rg.add(p, xproxy)
+		sigr.reload = p.reloadConfig
 
 		ps := &stats.Prunner{}
 		ps.Init()
@@ -226,10 +235,12 @@ func aisinit(version, build string) {
 
 		ctx.rg.add(newProxyKeepaliveRunner(p), xproxykeepalive)
 		ctx.rg.add(newmetasyncer(p), xmetasyncer)
+		ctx.rg.add(newJobScheduler(p), xjobscheduler)
 	} else {
 		t := &targetrunner{}
 		t.initSI()
 		ctx.rg.add(t, xtarget)
+		sigr.reload = t.reloadConfig
 
 		ts := &stats.Trunner{T: t} // iostat below
 		ts.Init()
@@ -279,6 +290,7 @@ func aisinit(version, build string) {
 		ctx.rg.add(iostat, xiostat)
 		t.fsprg.Reg(iostat)
 		ts.Riostat = iostat
+		ctx.rg.add(governor.Gov, xgovernor) // (iostat-driven)
 
 		fshc := health.NewFSHC(fs.Mountpaths, gmem2, fs.CSM)
 		ctx.rg.add(fshc, xfshc)
@@ -302,7 +314,7 @@ func aisinit(version, build string) {
 		ctx.rg.add(atime, xatime)
 		t.fsprg.Reg(atime)
 	}
-	ctx.rg.add(&sigrunner{}, xsignal)
+	ctx.rg.add(sigr, xsignal)
 
 	// even more config changes, e.g:
 	// -config=/etc/ais.json -role=target -persist=true -confjson="{\"default_timeout\": \"13s\" }"
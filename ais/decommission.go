@@ -0,0 +1,34 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+)
+
+//
+// Graceful decommission (cmn.ActDecommission):
+// 1) the target is excluded from HRW placement (see cluster.HrwTarget) but keeps
+//    serving reads - the new owners look up the objects that haven't arrived yet
+//    (see restoreObjLBNeigh);
+// 2) global rebalance migrates the target's objects to the new HRW owners (the
+//    latter re-mirror them upon receiving), while ecRebJogger-s migrate EC slices
+//    and replicas, each to the target that replaces this one in the respective
+//    HRW list;
+// 3) upon completion the target unregisters itself (leaveCluster).
+//
+
+// the last step of decommissioning: leave the cluster map unless canceled in the meantime
+func (reb *rebManager) leaveCluster() {
+	smap := reb.t.smapowner.get()
+	if !smap.Decommissioning(reb.t.si.DaemonID) {
+		glog.Warningf("%s: decommission canceled (Smap v%d)", tname(reb.t.si), smap.version())
+		return
+	}
+	glog.Infof("%s: decommission complete - leaving the cluster", tname(reb.t.si))
+	if status, err := reb.t.unregister(); err != nil {
+		glog.Errorf("%s: failed to unregister, status %d, err: %v", tname(reb.t.si), status, err)
+	}
+}
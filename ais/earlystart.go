@@ -276,7 +276,14 @@ func (p *proxyrunner) primaryStartup(guessSmap *smapX, ntargets int) {
 	}
 	bmdowner := p.bmdowner.get()
 	msgInt := p.newActionMsgInternalStr(metaction2, smap, bmdowner)
-	p.metasyncer.sync(false, smap, msgInt, bmdowner, msgInt)
+	params := []interface{}{smap, msgInt, bmdowner, msgInt}
+	if jobs := p.jobs.get(); jobs.version() > 0 {
+		params = append(params, jobs, msgInt)
+	}
+	if conf := p.confowner.get(); conf.version() > 0 {
+		params = append(params, conf, msgInt)
+	}
+	p.metasyncer.sync(false, params...)
 	glog.Infof("%s: primary/cluster startup complete, Smap v%d, ntargets %d", pname(p.si), smap.version(), smap.CountTargets())
 	p.startedup(1) // started up as primary
 }
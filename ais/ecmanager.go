@@ -170,3 +170,10 @@ func (mgr *ecManager) RestoreObject(lom *cluster.LOM) error {
 	// wait for EC completes restoring the object
 	return <-req.ErrCh
 }
+
+func (mgr *ecManager) Migrate(daemonID, fqn string, meta *ec.Metadata, cb transport.SendCallback) error {
+	if mgr.xact == nil || mgr.xact.Finished() {
+		mgr.xact = mgr.t.xactions.renewEC()
+	}
+	return mgr.xact.Migrate(daemonID, fqn, meta, cb)
+}
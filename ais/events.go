@@ -0,0 +1,324 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+//
+// Cluster event log
+//
+// The primary proxy records cluster-level events - Smap and BMD versions (with
+// the respective diffs), elections, rebalance start and end, mountpath changes,
+// and cluster-wide config changes - in a bounded journal that gets persisted
+// (cmn.EventsBackupFile, asynchronously - see flush) and metasync-ed to all
+// proxies. Smap and BMD events are recorded by the metasyncer itself when it
+// distributes new versions (see doSync);
+// targets report their (rebalance and mountpath) events to the primary via
+// POST /v1/cluster/events. Any proxy serves GET /v1/cluster?what=events.
+//
+
+const maxClusterEvents = 1000 // the oldest events get discarded
+
+// eventLog is immutable and versioned - a REVS (see metasync.go)
+type eventLog struct {
+	Version int64              `json:"version"`
+	Events  []cmn.ClusterEvent `json:"events"`
+}
+
+type eventsowner struct {
+	revsOwner
+	saveMtx sync.Mutex // serializes persisting (outside revsOwner lock)
+	saved   int64      // the last persisted version (under saveMtx)
+}
+
+//
+// revs interface
+//
+func (e *eventLog) tag() string    { return eventstag }
+func (e *eventLog) version() int64 { return e.Version }
+
+func (e *eventLog) marshal() ([]byte, error) {
+	return jsonCompat.Marshal(e)
+}
+
+// clone appends the given events to a copy of the log, and bumps up the version
+func (e *eventLog) clone(events ...cmn.ClusterEvent) *eventLog {
+	dst := &eventLog{Version: e.Version + 1}
+	all := append(e.Events[:len(e.Events):len(e.Events)], events...)
+	if len(all) > maxClusterEvents {
+		all = all[len(all)-maxClusterEvents:]
+	}
+	dst.Events = append(make([]cmn.ClusterEvent, 0, len(all)), all...)
+	return dst
+}
+
+// query returns the events of the given types (all, if not specified) that happened within
+// the [since, until) interval, where zero since or until means no limit
+func (e *eventLog) query(types []string, since, until time.Time) []cmn.ClusterEvent {
+	out := make([]cmn.ClusterEvent, 0, len(e.Events))
+	for _, ev := range e.Events {
+		if len(types) > 0 && !cmn.StringInSlice(ev.Type, types) {
+			continue
+		}
+		if !since.IsZero() && ev.Time.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !ev.Time.Before(until) {
+			continue
+		}
+		out = append(out, ev)
+	}
+	return out
+}
+
+//
+// eventsowner
+//
+
+// init loads the persisted event log, if any
+func (r *eventsowner) init(confdir string) {
+	r.revsOwner.init(filepath.Join(confdir, cmn.EventsBackupFile), "cluster event log", &eventLog{})
+	r.saved = r.curVersion()
+}
+
+// get never returns nil
+func (r *eventsowner) get() *eventLog {
+	if evlog := r.getRevs(); evlog != nil {
+		return evlog.(*eventLog)
+	}
+	return &eventLog{}
+}
+
+// add (primary only) records new events; it's up to the caller to metasync
+func (r *eventsowner) add(events ...cmn.ClusterEvent) *eventLog {
+	r.Lock()
+	evlog := r.get().clone(events...)
+	r.put(evlog)
+	r.Unlock()
+	go r.flush()
+	return evlog
+}
+
+// synchronize (non-primary) accepts the newer version received via metasync
+func (r *eventsowner) synchronize(evlog *eventLog) {
+	if r.revsOwner.synchronize(evlog, r.put) {
+		go r.flush()
+	}
+}
+
+// flush persists the current version unless already persisted - outside the
+// (metasync-ing) callers, and only once for a burst of new versions
+func (r *eventsowner) flush() {
+	r.saveMtx.Lock()
+	if evlog := r.get(); evlog.version() > r.saved {
+		r.save(evlog, evlog.version())
+		r.saved = evlog.version()
+	}
+	r.saveMtx.Unlock()
+}
+
+//
+// Smap and BMD diffs
+//
+
+func smapEvent(prev, smap *smapX, msgInt *actionMsgInternal) cmn.ClusterEvent {
+	parts := make([]string, 0, 6)
+	if prev == nil {
+		parts = append(parts, fmt.Sprintf("%d targets, %d proxies", smap.CountTargets(), smap.CountProxies()))
+	} else {
+		joined, left := diffNodes(prev.Tmap, smap.Tmap)
+		parts = appendNames(parts, "targets joined", joined)
+		parts = appendNames(parts, "targets left", left)
+		joined, left = diffNodes(prev.Pmap, smap.Pmap)
+		parts = appendNames(parts, "proxies joined", joined)
+		parts = appendNames(parts, "proxies left", left)
+	}
+	if prev == nil || prev.ProxySI == nil || prev.ProxySI.DaemonID != smap.ProxySI.DaemonID {
+		parts = append(parts, "primary "+smap.ProxySI.DaemonID)
+	}
+	return cmn.ClusterEvent{Type: cmn.EventSmap, Version: smap.version(), Msg: eventMsg(msgInt, parts)}
+}
+
+func bmdEvent(prev, bmd *bucketMD, msgInt *actionMsgInternal) cmn.ClusterEvent {
+	parts := make([]string, 0, 3)
+	if prev == nil {
+		parts = append(parts, fmt.Sprintf("%d local buckets, %d cloud buckets", len(bmd.LBmap), len(bmd.CBmap)))
+	} else {
+		var created, destroyed, changed []string
+		for _, mm := range [][2]map[string]*cmn.BucketProps{{prev.LBmap, bmd.LBmap}, {prev.CBmap, bmd.CBmap}} {
+			for name, props := range mm[1] {
+				if pprops, ok := mm[0][name]; !ok {
+					created = append(created, name)
+				} else if !reflect.DeepEqual(pprops, props) {
+					changed = append(changed, name)
+				}
+			}
+			for name := range mm[0] {
+				if _, ok := mm[1][name]; !ok {
+					destroyed = append(destroyed, name)
+				}
+			}
+		}
+		parts = appendNames(parts, "created", created)
+		parts = appendNames(parts, "destroyed", destroyed)
+		parts = appendNames(parts, "changed", changed)
+	}
+	return cmn.ClusterEvent{Type: cmn.EventBMD, Version: bmd.version(), Msg: eventMsg(msgInt, parts)}
+}
+
+func diffNodes(prev, cur cluster.NodeMap) (joined, left []string) {
+	for id := range cur {
+		if _, ok := prev[id]; !ok {
+			joined = append(joined, id)
+		}
+	}
+	for id := range prev {
+		if _, ok := cur[id]; !ok {
+			left = append(left, id)
+		}
+	}
+	return
+}
+
+func appendNames(parts []string, what string, names []string) []string {
+	if len(names) == 0 {
+		return parts
+	}
+	sort.Strings(names)
+	return append(parts, what+" ["+strings.Join(names, " ")+"]")
+}
+
+// eventMsg prefixes the diff with the action that caused it
+func eventMsg(msgInt *actionMsgInternal, parts []string) string {
+	action := msgInt.Action
+	if s, ok := msgInt.Value.(string); ok && action == "" {
+		action = s
+	}
+	msg := strings.Join(parts, ", ")
+	if action == "" {
+		return msg
+	}
+	if msg == "" {
+		return action
+	}
+	return action + ": " + msg
+}
+
+//
+// proxy
+//
+
+// recordEvent records the event if this proxy is primary, and reports it to the primary otherwise
+func (p *proxyrunner) recordEvent(typ, msg string) {
+	ev := cmn.ClusterEvent{Time: time.Now(), Type: typ, Node: p.si.DaemonID, Msg: msg}
+	if !p.smapowner.get().isPrimary(p.si) {
+		go p.postEvent(&ev)
+		return
+	}
+	evlog := p.events.add(ev)
+	p.metasyncer.sync(false, evlog, p.newActionMsgInternalStr(typ, nil, nil))
+}
+
+// POST /v1/cluster/events (the primary)
+func (p *proxyrunner) httpclupostEvent(w http.ResponseWriter, r *http.Request) {
+	var ev cmn.ClusterEvent
+	if cmn.ReadJSON(w, r, &ev) != nil {
+		return
+	}
+	body, err := jsoniter.Marshal(ev)
+	cmn.AssertNoErr(err)
+	if p.forwardCP(w, r, &cmn.ActionMsg{}, "record "+ev.Type+" event", body) {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	evlog := p.events.add(ev)
+	p.metasyncer.sync(false, evlog, p.newActionMsgInternalStr(ev.Type, nil, nil))
+}
+
+// GET /v1/cluster?what=events
+func (p *proxyrunner) httpGetClusterEvents(w http.ResponseWriter, r *http.Request) {
+	var (
+		since, until time.Time
+		types        []string
+		err          error
+		query        = r.URL.Query()
+	)
+	if s := query.Get(cmn.URLParamEventType); s != "" {
+		types = strings.Split(s, ",")
+	}
+	if s := query.Get(cmn.URLParamSince); s != "" {
+		if since, err = time.Parse(time.RFC3339Nano, s); err != nil {
+			p.invalmsghdlr(w, r, fmt.Sprintf("invalid %s=%s, err: %v", cmn.URLParamSince, s, err))
+			return
+		}
+	}
+	if s := query.Get(cmn.URLParamUntil); s != "" {
+		if until, err = time.Parse(time.RFC3339Nano, s); err != nil {
+			p.invalmsghdlr(w, r, fmt.Sprintf("invalid %s=%s, err: %v", cmn.URLParamUntil, s, err))
+			return
+		}
+	}
+	jsbytes, err := jsoniter.Marshal(p.events.get().query(types, since, until))
+	cmn.AssertNoErr(err)
+	p.writeJSON(w, r, jsbytes, "getClusterEvents")
+}
+
+func (h *httprunner) extractEventLog(payload cmn.SimpleKVs) (*eventLog, string) {
+	evlog := &eventLog{}
+	if ok, errstr := extractRevs(payload, eventstag, "cluster event log", evlog); !ok {
+		return nil, errstr
+	}
+	return evlog, ""
+}
+
+//
+// target
+//
+
+// recordEvent reports the event to the primary (asynchronously)
+func (t *targetrunner) recordEvent(typ, msg string) {
+	ev := cmn.ClusterEvent{Time: time.Now(), Type: typ, Node: t.si.DaemonID, Msg: msg}
+	go t.postEvent(&ev)
+}
+
+func (h *httprunner) postEvent(ev *cmn.ClusterEvent) {
+	smap := h.smapowner.get()
+	if smap == nil || !smap.isValid() {
+		glog.Warningf("%s: cannot report %s event %q - no primary", h.si, ev.Type, ev.Msg)
+		return
+	}
+	body, err := jsoniter.Marshal(ev)
+	cmn.AssertNoErr(err)
+	args := callArgs{
+		si: smap.ProxySI,
+		req: reqArgs{
+			method: http.MethodPost,
+			base:   smap.ProxySI.IntraControlNet.DirectURL,
+			path:   cmn.URLPath(cmn.Version, cmn.Cluster, cmn.Events),
+			body:   body,
+		},
+		timeout: cmn.GCO.Get().Timeout.CplaneOperation,
+	}
+	if res := h.call(args); res.err != nil {
+		glog.Warningf("%s: failed to report %s event %q to the primary %s, err: %v",
+			h.si, ev.Type, ev.Msg, smap.ProxySI, res.err)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+func TestEventLogBounded(t *testing.T) {
+	evlog := &eventLog{}
+	for i := 0; i < maxClusterEvents+10; i++ {
+		prev := evlog
+		evlog = evlog.clone(cmn.ClusterEvent{Type: cmn.EventConfig, Version: int64(i)})
+		if len(prev.Events) != i && len(prev.Events) != maxClusterEvents {
+			t.Fatalf("event log v%d has been modified in place", prev.Version)
+		}
+	}
+	if len(evlog.Events) != maxClusterEvents {
+		t.Fatalf("expecting %d events, got %d", maxClusterEvents, len(evlog.Events))
+	}
+	if evlog.Version != maxClusterEvents+10 || evlog.Events[0].Version != 10 {
+		t.Errorf("expecting v%d with the oldest events discarded, got v%d starting from %d",
+			maxClusterEvents+10, evlog.Version, evlog.Events[0].Version)
+	}
+}
+
+func TestEventLogPersisted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var (
+		owner = &eventsowner{}
+		wg    = &sync.WaitGroup{}
+	)
+	owner.init(dir)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			owner.add(cmn.ClusterEvent{Type: cmn.EventConfig})
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	owner.flush()
+
+	// restart
+	owner = &eventsowner{}
+	owner.init(dir)
+	if evlog := owner.get(); evlog.version() != 10 || len(evlog.Events) != 10 || owner.saved != 10 {
+		t.Errorf("expecting persisted v10 with 10 events, got v%d with %d events (saved v%d)",
+			evlog.version(), len(evlog.Events), owner.saved)
+	}
+}
+
+func TestEventLogQuery(t *testing.T) {
+	var (
+		now   = time.Now()
+		evlog = (&eventLog{}).clone(
+			cmn.ClusterEvent{Time: now.Add(-3 * time.Hour), Type: cmn.EventSmap},
+			cmn.ClusterEvent{Time: now.Add(-2 * time.Hour), Type: cmn.EventBMD},
+			cmn.ClusterEvent{Time: now.Add(-time.Hour), Type: cmn.EventSmap},
+			cmn.ClusterEvent{Time: now, Type: cmn.EventRebalance},
+		)
+	)
+	tcs := []struct {
+		types        []string
+		since, until time.Time
+		expected     int
+	}{
+		{nil, time.Time{}, time.Time{}, 4},
+		{[]string{cmn.EventSmap}, time.Time{}, time.Time{}, 2},
+		{[]string{cmn.EventSmap, cmn.EventRebalance}, now.Add(-time.Hour), time.Time{}, 2},
+		{nil, now.Add(-2 * time.Hour), now, 2},
+		{[]string{cmn.EventElection}, time.Time{}, time.Time{}, 0},
+	}
+	for i, tc := range tcs {
+		if events := evlog.query(tc.types, tc.since, tc.until); len(events) != tc.expected {
+			t.Errorf("test case #%d: expecting %d events, got %d", i, tc.expected, len(events))
+		}
+	}
+}
+
+func TestMetasyncRecordsEvents(t *testing.T) {
+	var (
+		p    = newPrimary()
+		y    = newmetasyncer(p)
+		smap = p.smapowner.get()
+	)
+	y.last[smaptag] = smap
+	clone := smap.clone()
+	clone.addTarget(newSnode("t1", httpProto, &net.TCPAddr{}, &net.TCPAddr{}, &net.TCPAddr{}))
+	clone.Version++
+	bmd := newBucketMD()
+	bmd.add("bucket", true, &cmn.BucketProps{})
+
+	msgInt := p.newActionMsgInternal(&cmn.ActionMsg{Action: cmn.ActRegTarget}, clone, bmd)
+	pairs := y.withEvents([]revspair{{clone, msgInt}, {bmd, msgInt}})
+	if len(pairs) != 3 || pairs[2].revs.tag() != eventstag {
+		t.Fatalf("expecting the event log to be sync-ed along with Smap and %s", bmdTermName)
+	}
+	evlog := p.events.get()
+	if len(evlog.Events) != 2 {
+		t.Fatalf("expecting 2 events, got %d", len(evlog.Events))
+	}
+	if ev := evlog.Events[0]; ev.Type != cmn.EventSmap || ev.Version != clone.version() ||
+		!strings.Contains(ev.Msg, "targets joined [t1]") || ev.Node != p.si.DaemonID {
+		t.Errorf("unexpected Smap event %+v", ev)
+	}
+	if ev := evlog.Events[1]; ev.Type != cmn.EventBMD || ev.Version != bmd.version() || ev.Msg != cmn.ActRegTarget+": 1 local buckets, 0 cloud buckets" {
+		t.Errorf("unexpected %s event %+v", bmdTermName, ev)
+	}
+
+	// the versions that were already sync-ed do not get recorded again
+	y.last[smaptag], y.last[bucketmdtag], y.last[eventstag] = clone, bmd, evlog
+	if pairs = y.withEvents([]revspair{{clone, msgInt}}); len(pairs) != 1 || len(p.events.get().Events) != 2 {
+		t.Errorf("expecting no new events, got %d", len(p.events.get().Events))
+	}
+}
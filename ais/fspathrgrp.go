@@ -61,6 +61,7 @@ func (g *fsprungroup) enableMountpath(mpath string) (enabled, exists bool) {
 		r.ReqEnableMountpath(mpath)
 	}
 	glog.Infof("Re-enabled mountpath %s", mpath)
+	g.t.recordEvent(cmn.EventMountpath, "enabled "+mpath)
 	go g.t.rebManager.runLocalReb()
 
 	availablePaths, _ := fs.Mountpaths.Get()
@@ -82,6 +83,7 @@ func (g *fsprungroup) disableMountpath(mpath string) (disabled, exists bool) {
 	for _, r := range g.runners {
 		r.ReqDisableMountpath(mpath)
 	}
+	g.t.recordEvent(cmn.EventMountpath, "disabled "+mpath)
 	g.checkNoMountpaths("Disabled")
 	return
 }
@@ -102,6 +104,7 @@ func (g *fsprungroup) addMountpath(mpath string) (err error) {
 	for _, r := range g.runners {
 		r.ReqAddMountpath(mpath)
 	}
+	g.t.recordEvent(cmn.EventMountpath, "added "+mpath)
 	go g.t.rebManager.runLocalReb()
 
 	availablePaths, _ := fs.Mountpaths.Get()
@@ -125,6 +128,7 @@ func (g *fsprungroup) removeMountpath(mpath string) (err error) {
 	for _, r := range g.runners {
 		r.ReqRemoveMountpath(mpath)
 	}
+	g.t.recordEvent(cmn.EventMountpath, "removed "+mpath)
 	g.checkNoMountpaths("Removed")
 	return
 }
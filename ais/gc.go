@@ -0,0 +1,136 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/governor"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+//
+// Garbage collection of orphaned workfiles and intermediate content
+//
+// Crashed PUTs, aborted dsort jobs, interrupted EC encodes, etc. leave behind
+// content of the types that permit garbage collection (fs.ContentResolver.PermToGC).
+// Such content is stale if it was created by a previous run of the target (as per
+// the pid in its name or its modification time), or if it is older than gc.max_age
+// (see fs.CSM.Stale) and not in use by a running job (fs.ContentInUse). The target
+// removes stale content upon startup and then every gc.interval until it stops;
+// the space reclaimed is reported via the xaction (kind "gc") and the target's
+// "gc.n" and "gc.size" stats.
+//
+
+var errGCAborted = errors.New("gc aborted") // stops traversal
+
+// runGCLoop runs the startup pass and then the periodic ones until the target stops
+func (t *targetrunner) runGCLoop(stopCh <-chan struct{}) {
+	t.runGC()
+	for {
+		interval := cmn.GCO.Get().GC.Interval
+		disabled := interval == 0
+		if disabled {
+			interval = time.Minute // periodic passes are disabled - check again later
+		}
+		select {
+		case <-stopCh:
+			return
+		case <-time.After(interval):
+		}
+		if !disabled {
+			t.runGC()
+		}
+	}
+}
+
+// runGC runs a single pass over all mountpaths
+func (t *targetrunner) runGC() {
+	xgc := t.xactions.renewGC()
+	if xgc == nil {
+		return
+	}
+	var (
+		availablePaths, _ = fs.Mountpaths.Get()
+		maxAge            = cmn.GCO.Get().GC.MaxAge
+		wg                = &sync.WaitGroup{}
+	)
+	for _, mpathInfo := range availablePaths {
+		wg.Add(1)
+		go func(mpathInfo *fs.MountpathInfo) {
+			t.gcMpath(xgc, mpathInfo, maxAge)
+			wg.Done()
+		}(mpathInfo)
+	}
+	wg.Wait()
+	if xgc.Aborted() {
+		return
+	}
+	xgc.EndTime(time.Now())
+	st := xgc.Snapshot()
+	if st.Objects > 0 {
+		glog.Infof("%s: removed %d stale file(s), reclaimed %s", xgc, st.Objects, cmn.B2S(st.Bytes, 2))
+	}
+}
+
+func (t *targetrunner) gcMpath(xgc *xactGC, mpathInfo *fs.MountpathInfo, maxAge time.Duration) {
+	for contentType, contentResolver := range fs.CSM.RegisteredContentTypes {
+		if !contentResolver.PermToGC() {
+			continue
+		}
+		for _, bckIsLocal := range []bool{true, false} {
+			root := mpathInfo.MakePath(contentType, bckIsLocal)
+			walk := func(fqn string, fi os.FileInfo, err error) error {
+				if err != nil {
+					if errstr := cmn.PathWalkErr(err); errstr != "" {
+						glog.Error(errstr)
+						return err
+					}
+					return nil
+				}
+				if fi.IsDir() {
+					return nil
+				}
+				select {
+				case <-xgc.ChanAbort():
+					return errGCAborted
+				default:
+				}
+				governor.Gov.Throttle(mpathInfo.Path, governor.PrioLow)
+				if !fs.CSM.Stale(fqn, fi, maxAge) {
+					return nil
+				}
+				if err := os.Remove(fqn); err != nil {
+					if !os.IsNotExist(err) {
+						xgc.AddErr(err)
+					}
+					return nil
+				}
+				if glog.V(4) {
+					glog.Infof("%s: removed %s", xgc, fqn)
+				}
+				xgc.ObjectsAdd(1)
+				xgc.BytesAdd(fi.Size())
+				t.statsif.Add(stats.GCCount, 1)
+				t.statsif.Add(stats.GCSize, fi.Size())
+				return nil
+			}
+			if err := filepath.Walk(root, walk); err != nil {
+				if err == errGCAborted {
+					return
+				}
+				glog.Errorf("%s: failed to traverse %s, err: %v", xgc, root, err)
+				xgc.AddErr(err)
+			}
+		}
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"cloud.google.com/go/storage"
@@ -56,10 +57,17 @@ type (
 	gcpimpl struct {
 		t *targetrunner
 	}
+	// clients are safe for concurrent use and are cached per credential set
+	gcpClients struct {
+		sync.Mutex
+		m map[string]*storage.Client
+	}
 )
 
 var (
 	_ cloudif = &gcpimpl{}
+
+	gcpClientPool = gcpClients{m: make(map[string]*storage.Client, 4)}
 )
 
 //======
@@ -73,8 +81,9 @@ func getProjID() string {
 	return os.Getenv("GOOGLE_CLOUD_PROJECT")
 }
 
-func gcpErrorToHTTP(gcpError error) int {
+func gcpErrorToHTTP(ct context.Context, gcpError error) int {
 	if gcperror, ok := gcpError.(*googleapi.Error); ok {
+		setRetryAfter(ct, gcperror.Header)
 		return gcperror.Code
 	}
 
@@ -101,19 +110,35 @@ func extractGCPCreds(credsList map[string]string) (*gcpCreds, error) {
 }
 
 func defaultClient(gctx context.Context) (*storage.Client, context.Context, string, string) {
-	if glog.V(5) {
-		glog.Info("Creating default google cloud session")
-	}
 	if getProjID() == "" {
 		return nil, nil, "", "Failed to get ProjectID from GCP"
 	}
-	client, err := storage.NewClient(gctx)
+	client, err := gcpClientPool.get("", func() (*storage.Client, error) {
+		if glog.V(5) {
+			glog.Info("Creating default google cloud session")
+		}
+		return storage.NewClient(gctx)
+	})
 	if err != nil {
 		return nil, nil, "", fmt.Sprintf("Failed to create client, err: %v", err)
 	}
 	return client, gctx, getProjID(), ""
 }
 
+func (cs *gcpClients) get(key string, create func() (*storage.Client, error)) (*storage.Client, error) {
+	cs.Lock()
+	defer cs.Unlock()
+	if client, ok := cs.m[key]; ok {
+		return client, nil
+	}
+	client, err := create()
+	if err != nil {
+		return nil, err
+	}
+	cs.m[key] = client
+	return client, nil
+}
+
 func saveCredentialsToFile(baseDir, userID, userCreds string) (string, error) {
 	dir := filepath.Join(baseDir, cmn.ProviderGoogle)
 	filePath := filepath.Join(dir, userID+".json")
@@ -166,13 +191,13 @@ func createClient(ct context.Context) (*storage.Client, context.Context, string,
 		return defaultClient(gctx)
 	}
 
-	filePath, err := saveCredentialsToFile(credsDir, userID, creds.creds)
-	if err != nil {
-		glog.Errorf("Failed to save credentials: %v", err)
-		return defaultClient(gctx)
-	}
-
-	client, err := storage.NewClient(gctx, option.WithCredentialsFile(filePath))
+	client, err := gcpClientPool.get(userID+"/"+creds.creds, func() (*storage.Client, error) {
+		filePath, err := saveCredentialsToFile(credsDir, userID, creds.creds)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save credentials: %v", err)
+		}
+		return storage.NewClient(gctx, option.WithCredentialsFile(filePath))
+	})
 	if err != nil {
 		glog.Errorf("Failed to create storage client for %s: %v", userID, err)
 		return defaultClient(gctx)
@@ -213,7 +238,7 @@ func (gcpimpl *gcpimpl) listbucket(ct context.Context, bucket string, msg *cmn.G
 	objs := make([]*storage.ObjectAttrs, 0)
 	nextPageToken, err := pager.NextPage(&objs)
 	if err != nil {
-		errcode = gcpErrorToHTTP(err)
+		errcode = gcpErrorToHTTP(ct, err)
 		errstr = fmt.Sprintf("Failed to list objects of bucket %s, err: %v", bucket, err)
 	}
 
@@ -274,7 +299,7 @@ func (gcpimpl *gcpimpl) headbucket(ct context.Context, bucket string) (bucketpro
 	}
 	_, err := gcpclient.Bucket(bucket).Attrs(gctx)
 	if err != nil {
-		errcode = gcpErrorToHTTP(err)
+		errcode = gcpErrorToHTTP(ct, err)
 		errstr = fmt.Sprintf("Failed to get attributes (bucket %s), err: %v", bucket, err)
 		return
 	}
@@ -298,7 +323,7 @@ func (gcpimpl *gcpimpl) getbucketnames(ct context.Context) (buckets []string, er
 			break
 		}
 		if err != nil {
-			errcode = gcpErrorToHTTP(err)
+			errcode = gcpErrorToHTTP(ct, err)
 			errstr = fmt.Sprintf("Failed to list all buckets, err: %v", err)
 			return
 		}
@@ -327,7 +352,7 @@ func (gcpimpl *gcpimpl) headobject(ct context.Context, bucket string, objname st
 	}
 	attrs, err := gcpclient.Bucket(bucket).Object(objname).Attrs(gctx)
 	if err != nil {
-		errcode = gcpErrorToHTTP(err)
+		errcode = gcpErrorToHTTP(ct, err)
 		errstr = fmt.Sprintf("Failed to retrieve %s/%s metadata, err: %v", bucket, objname, err)
 		return
 	}
@@ -349,7 +374,7 @@ func (gcpimpl *gcpimpl) getobj(ct context.Context, workFQN string, bucket string
 	o := gcpclient.Bucket(bucket).Object(objname)
 	attrs, err := o.Attrs(gctx)
 	if err != nil {
-		errcode = gcpErrorToHTTP(err)
+		errcode = gcpErrorToHTTP(ct, err)
 		errstr = fmt.Sprintf("Failed to retrieve %s/%s metadata, err: %v", bucket, objname, err)
 		return
 	}
@@ -429,7 +454,7 @@ func (gcpimpl *gcpimpl) deleteobj(ct context.Context, bucket, objname string) (e
 	o := gcpclient.Bucket(bucket).Object(objname)
 	err := o.Delete(gctx)
 	if err != nil {
-		errcode = gcpErrorToHTTP(err)
+		errcode = gcpErrorToHTTP(ct, err)
 		errstr = fmt.Sprintf("Failed to DELETE %s/%s, err: %v", bucket, objname, err)
 		return
 	}
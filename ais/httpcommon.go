@@ -250,6 +250,7 @@ type httprunner struct {
 	smapowner             *smapowner
 	smaplisteners         *smaplisteners
 	bmdowner              *bmdowner
+	confowner             *confowner
 	xactions              *xactions
 	statsif               stats.Tracker
 	statsdC               statsd.Client
@@ -389,6 +390,7 @@ func (h *httprunner) init(s stats.Tracker, isproxy bool) {
 	h.smaplisteners = newSmapListeners()
 	h.smapowner = &smapowner{listeners: h.smaplisteners}
 	h.bmdowner = &bmdowner{}
+	h.confowner = &confowner{}
 	h.xactions = newXs() // extended actions
 }
 
@@ -0,0 +1,527 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+//
+// Scheduled (cron-style) jobs
+//
+// Job definitions (cmn.Job) are versioned cluster metadata: the primary proxy
+// persists them (cmn.JobsBackupFile) and metasync-s to all proxies, so that the
+// schedule survives primary failover. Every proxy runs the jobScheduler, but
+// only the primary starts jobs: once a minute it checks the schedule and starts
+// the due jobs on all targets via its own API handlers (/v1/xactions or, for prefetch
+// and erasecopies, /v1/buckets) - the same way an administrator would. It then
+// waits for the respective xactions to finish and records the outcome in the
+// cluster event log (cmn.EventJob). A job that is due while its previous run is
+// still in progress is skipped or queued according to its cmn.Job.Overlap policy;
+// the previous run is the one this proxy started or, after primary failover, the
+// job's xactions still running on the targets.
+//
+
+const (
+	jobTickInterval = 10 * time.Second // (the schedule has a 1-minute resolution)
+	jobPollInterval = 10 * time.Second // to check the job's xactions on the targets
+)
+
+// the kinds of scheduled jobs: kind => bucket required
+var jobKinds = map[string]bool{
+	cmn.ActGlobalReb:   false,
+	cmn.ActLocalReb:    false,
+	cmn.ActLRU:         false,
+	cmn.ActRechecksum:  true,
+	cmn.ActPrefetch:    true,
+	cmn.ActEraseCopies: true,
+}
+
+type (
+	// jobsMD is immutable and versioned - a REVS (see metasync.go)
+	jobsMD struct {
+		Version int64     `json:"version"`
+		Jobs    []cmn.Job `json:"jobs"`
+	}
+	jobsowner struct {
+		revsOwner
+	}
+	jobScheduler struct {
+		cmn.Named
+		p       *proxyrunner
+		mtx     sync.Mutex
+		running map[string]*jobRun // by job name
+		stopCh  chan struct{}
+	}
+	jobRun struct {
+		queued bool
+	}
+	// aggregated outcome of a job run
+	jobOutcome struct {
+		xacts, targets, aborted int
+		objects, bytes, errcnt  int64
+		lasterr                 string
+	}
+)
+
+func validateJob(job *cmn.Job) string {
+	if job.Name == "" {
+		return "job name is required"
+	}
+	if _, err := cmn.ParseCron(job.Schedule); err != nil {
+		return fmt.Sprintf("job %q: %v", job.Name, err)
+	}
+	bucketRequired, ok := jobKinds[job.Kind]
+	if !ok {
+		return fmt.Sprintf("job %q: %q xaction cannot be scheduled", job.Name, job.Kind)
+	}
+	if bucketRequired && job.Bucket == "" {
+		return fmt.Sprintf("job %q: %q xaction requires bucket", job.Name, job.Kind)
+	}
+	switch job.Overlap {
+	case "":
+		job.Overlap = cmn.JobOverlapSkip
+	case cmn.JobOverlapSkip, cmn.JobOverlapQueue:
+	default:
+		return fmt.Sprintf("job %q: invalid overlap policy %q (expecting %q or %q)",
+			job.Name, job.Overlap, cmn.JobOverlapSkip, cmn.JobOverlapQueue)
+	}
+	return ""
+}
+
+func jobTitle(job *cmn.Job) string {
+	if job.Bucket == "" {
+		return fmt.Sprintf("%q (%s)", job.Name, job.Kind)
+	}
+	return fmt.Sprintf("%q (%s %s)", job.Name, job.Kind, job.Bucket)
+}
+
+// revs interface
+func (m *jobsMD) tag() string    { return jobstag }
+func (m *jobsMD) version() int64 { return m.Version }
+
+func (m *jobsMD) marshal() ([]byte, error) {
+	return jsonCompat.Marshal(m)
+}
+
+func (m *jobsMD) clone() *jobsMD {
+	dst := &jobsMD{Version: m.Version + 1}
+	dst.Jobs = append(make([]cmn.Job, 0, len(m.Jobs)+1), m.Jobs...)
+	return dst
+}
+
+func (m *jobsMD) find(name string) (job cmn.Job, ok bool) {
+	for _, job = range m.Jobs {
+		if job.Name == name {
+			return job, true
+		}
+	}
+	return
+}
+
+// add adds a new job or replaces the existing one with the same name
+func (m *jobsMD) add(job cmn.Job) {
+	for i := range m.Jobs {
+		if m.Jobs[i].Name == job.Name {
+			m.Jobs[i] = job
+			return
+		}
+	}
+	m.Jobs = append(m.Jobs, job)
+}
+
+func (m *jobsMD) remove(name string) bool {
+	for i := range m.Jobs {
+		if m.Jobs[i].Name == name {
+			m.Jobs = append(m.Jobs[:i], m.Jobs[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+//
+// jobsowner
+//
+
+// init loads the persisted job definitions, if any
+func (r *jobsowner) init(confdir string) {
+	r.revsOwner.init(filepath.Join(confdir, cmn.JobsBackupFile), "scheduled jobs", &jobsMD{})
+}
+
+// get never returns nil
+func (r *jobsowner) get() *jobsMD {
+	if jobs := r.getRevs(); jobs != nil {
+		return jobs.(*jobsMD)
+	}
+	return &jobsMD{}
+}
+
+func (h *httprunner) extractJobs(payload cmn.SimpleKVs) (*jobsMD, string) {
+	jobs := &jobsMD{}
+	if ok, errstr := extractRevs(payload, jobstag, "scheduled jobs", jobs); !ok {
+		return nil, errstr
+	}
+	return jobs, ""
+}
+
+//
+// proxy: API
+//
+
+// GET /v1/cluster?what=jobs
+func (p *proxyrunner) httpGetJobs(w http.ResponseWriter, r *http.Request) {
+	jsbytes, err := jsoniter.Marshal(p.jobs.get().Jobs)
+	cmn.AssertNoErr(err)
+	p.writeJSON(w, r, jsbytes, "getJobs")
+}
+
+// PUT /v1/cluster {"action": "addjob", "value": cmn.Job} or {"action": "removejob", "name": ...}
+func (p *proxyrunner) updateJobs(w http.ResponseWriter, r *http.Request, msg *cmn.ActionMsg) {
+	var (
+		job  cmn.Job
+		name = msg.Name
+	)
+	if msg.Action == cmn.ActAddJob {
+		b, err := jsoniter.Marshal(msg.Value)
+		if err == nil {
+			err = jsoniter.Unmarshal(b, &job)
+		}
+		if err != nil {
+			p.invalmsghdlr(w, r, fmt.Sprintf("%s: invalid value (%+v, %T), err: %v", msg.Action, msg.Value, msg.Value, err))
+			return
+		}
+		if errstr := validateJob(&job); errstr != "" {
+			p.invalmsghdlr(w, r, errstr)
+			return
+		}
+		name = job.Name
+	} else if name == "" {
+		p.invalmsghdlr(w, r, fmt.Sprintf("%s: job name is required", msg.Action))
+		return
+	}
+	p.jobs.Lock()
+	clone := p.jobs.get().clone()
+	if msg.Action == cmn.ActAddJob {
+		clone.add(job)
+	} else if !clone.remove(name) {
+		p.jobs.Unlock()
+		p.invalmsghdlr(w, r, fmt.Sprintf("%s: job %q does not exist", msg.Action, name), http.StatusNotFound)
+		return
+	}
+	p.jobs.persist(clone)
+	p.jobs.put(clone)
+	p.jobs.Unlock()
+
+	msgInt := p.newActionMsgInternal(msg, nil, nil)
+	p.metasyncer.sync(true, clone, msgInt)
+	glog.Infof("%s: %s %q, jobs v%d", p.si, msg.Action, name, clone.version())
+}
+
+//
+// proxy: scheduler
+//
+
+func newJobScheduler(p *proxyrunner) *jobScheduler {
+	return &jobScheduler{p: p, running: make(map[string]*jobRun), stopCh: make(chan struct{})}
+}
+
+func (s *jobScheduler) Run() error {
+	glog.Infof("Starting %s", s.Getname())
+	var (
+		ticker = time.NewTicker(jobTickInterval)
+		last   = time.Now().Truncate(time.Minute)
+	)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			if minute := now.Truncate(time.Minute); minute.After(last) {
+				last = minute
+				s.tick(minute)
+			}
+		case <-s.stopCh:
+			return nil
+		}
+	}
+}
+
+func (s *jobScheduler) Stop(err error) {
+	glog.Infof("Stopping %s, err: %v", s.Getname(), err)
+	close(s.stopCh)
+}
+
+func (s *jobScheduler) isPrimary() bool {
+	smap := s.p.smapowner.get()
+	return smap != nil && smap.isPrimary(s.p.si)
+}
+
+func (s *jobScheduler) tick(minute time.Time) {
+	if !s.isPrimary() {
+		return
+	}
+	for _, job := range s.p.jobs.get().Jobs {
+		sched, err := cmn.ParseCron(job.Schedule)
+		if err != nil { // validated when added
+			glog.Errorf("%s: job %q: %v", s.p.si, job.Name, err)
+			continue
+		}
+		if !sched.Match(minute) {
+			continue
+		}
+		if start, note := s.admit(&job); start {
+			go s.run(job)
+		} else {
+			s.p.recordEvent(cmn.EventJob, fmt.Sprintf("%s %s: previous run in progress", jobTitle(&job), note))
+		}
+	}
+}
+
+// admit implements the overlap policy: returns true if the job is to be started now,
+// and otherwise whether it got skipped or queued
+func (s *jobScheduler) admit(job *cmn.Job) (start bool, note string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	run, ok := s.running[job.Name]
+	if !ok {
+		s.running[job.Name] = &jobRun{}
+		return true, ""
+	}
+	if job.Overlap == cmn.JobOverlapQueue {
+		run.queued = true
+		return false, "queued"
+	}
+	return false, "skipped"
+}
+
+// done returns true if the job has been queued in the meantime and must run again
+func (s *jobScheduler) done(name string) (again bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	run := s.running[name]
+	if run.queued {
+		run.queued = false
+		return true
+	}
+	delete(s.running, name)
+	return false
+}
+
+func (s *jobScheduler) run(job cmn.Job) {
+	name := job.Name
+	for {
+		title := jobTitle(&job)
+		if note := s.checkRunning(&job); note != "" {
+			s.p.recordEvent(cmn.EventJob, title+" "+note)
+		} else {
+			s.p.recordEvent(cmn.EventJob, title+" started")
+			s.p.recordEvent(cmn.EventJob, title+" "+s.execute(&job))
+		}
+		if !s.done(name) {
+			return
+		}
+		// the job could have been updated or removed in the meantime
+		var ok bool
+		if job, ok = s.p.jobs.get().find(name); !ok || !s.isPrimary() {
+			s.done(name)
+			return
+		}
+	}
+}
+
+// checkRunning applies the overlap policy to the job's xactions that are already
+// running on the targets - e.g., the run started by the previous primary prior to
+// failover (the scheduler keeps track only of the runs it starts itself);
+// returns non-empty note if the job is not to be started
+func (s *jobScheduler) checkRunning(job *cmn.Job) (note string) {
+	query := jobXactQuery(job, time.Time{})
+	if _, running := s.poll(query); running == 0 {
+		return
+	}
+	if job.Overlap != cmn.JobOverlapQueue {
+		return "skipped: previous run in progress"
+	}
+	s.p.recordEvent(cmn.EventJob, jobTitle(job)+" queued: previous run in progress")
+	_, note = s.wait(query)
+	return
+}
+
+// execute starts the job and waits for its xactions to finish; returns the outcome
+func (s *jobScheduler) execute(job *cmn.Job) string {
+	var (
+		handler      func(http.ResponseWriter, *http.Request)
+		method, path string
+		query        = url.Values{}
+		msg          cmn.ActionMsg
+		started      = time.Now()
+	)
+	// call the primary's own handlers directly rather than its public API
+	// (that may require authentication)
+	switch job.Kind {
+	case cmn.ActPrefetch, cmn.ActEraseCopies:
+		handler = s.p.httpbckpost
+		method, path = http.MethodPost, cmn.URLPath(cmn.Version, cmn.Buckets, job.Bucket)
+		msg = cmn.ActionMsg{Action: job.Kind, Value: job.Value}
+		if job.BckProvider != "" {
+			query.Set(cmn.URLParamBckProvider, job.BckProvider)
+		}
+	default:
+		handler = s.p.httpxactput
+		method, path = http.MethodPut, cmn.URLPath(cmn.Version, cmn.Xactions)
+		msg = cmn.ActionMsg{Action: cmn.ActXactStart, Value: &cmn.XactMsg{Kind: job.Kind, Bucket: job.Bucket}}
+	}
+	body, err := jsoniter.Marshal(&msg)
+	cmn.AssertNoErr(err)
+	u := url.URL{Path: path, RawQuery: query.Encode()}
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Sprintf("failed to start: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp := newJobResponse()
+	handler(resp, req)
+	if resp.status >= http.StatusBadRequest {
+		return fmt.Sprintf("failed to start: %s", strings.TrimSpace(resp.body.String()))
+	}
+
+	// wait for the job's xactions to finish
+	outcome, note := s.wait(jobXactQuery(job, started))
+	if note != "" {
+		return note
+	}
+	return outcome.String(time.Since(started))
+}
+
+// jobXactQuery selects the job's xactions: running ones or, if since is specified,
+// all those started since then
+func jobXactQuery(job *cmn.Job, since time.Time) url.Values {
+	query := url.Values{cmn.URLParamXactKind: []string{job.Kind}}
+	if jobKinds[job.Kind] && job.Kind != cmn.ActPrefetch { // bucket-specific xactions
+		query.Set(cmn.URLParamXactBucket, job.Bucket)
+	}
+	if !since.IsZero() {
+		query.Set(cmn.URLParamXactAll, "true")
+		query.Set(cmn.URLParamSince, since.Format(time.RFC3339Nano))
+	}
+	return query
+}
+
+// wait polls the targets until the xactions that match the query finish; returns
+// their outcome or, if interrupted, the reason
+func (s *jobScheduler) wait(query url.Values) (outcome jobOutcome, interrupted string) {
+	for {
+		select {
+		case <-time.After(jobPollInterval):
+		case <-s.stopCh:
+			return outcome, "interrupted: shutting down"
+		}
+		if !s.isPrimary() {
+			return outcome, "interrupted: no longer primary"
+		}
+		var running int
+		if outcome, running = s.poll(query); running == 0 {
+			return
+		}
+	}
+}
+
+// poll returns the aggregated outcome of the xactions that match the query, and
+// the number of those that are still running
+func (s *jobScheduler) poll(query url.Values) (outcome jobOutcome, running int) {
+	results := s.p.broadcastTo(
+		cmn.URLPath(cmn.Version, cmn.Xactions),
+		query,
+		http.MethodGet,
+		nil, // message
+		s.p.smapowner.get(),
+		cmn.GCO.Get().Timeout.Default,
+		cmn.NetworkIntraControl,
+		cluster.Targets,
+	)
+	for result := range results {
+		var xacts []cmn.XactStats
+		if result.err != nil {
+			glog.Warningf("%s: failed to get xactions from %s, err: %s", s.p.si, result.si, result.errstr)
+			continue
+		}
+		if err := jsoniter.Unmarshal(result.outjson, &xacts); err != nil {
+			glog.Warningf("%s: invalid xactions from %s, err: %v", s.p.si, result.si, err)
+			continue
+		}
+		if len(xacts) > 0 {
+			outcome.targets++
+		}
+		for _, st := range xacts {
+			switch st.Status {
+			case cmn.XactionStatusInProgress:
+				running++
+			case cmn.XactionStatusAborted:
+				outcome.aborted++
+			}
+			outcome.xacts++
+			outcome.objects += st.Objects
+			outcome.bytes += st.Bytes
+			outcome.errcnt += st.ErrCnt
+			if st.LastErr != "" {
+				outcome.lasterr = st.LastErr
+			}
+		}
+	}
+	return
+}
+
+//
+// jobResponse: the response of the handler that starts the job (see execute)
+//
+
+type jobResponse struct {
+	header http.Header
+	body   bytes.Buffer
+	status int
+}
+
+func newJobResponse() *jobResponse { return &jobResponse{header: make(http.Header)} }
+
+func (r *jobResponse) Header() http.Header { return r.header }
+
+func (r *jobResponse) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+func (r *jobResponse) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (o *jobOutcome) String(elapsed time.Duration) string {
+	if o.xacts == 0 {
+		return "finished: nothing to do"
+	}
+	s := fmt.Sprintf("finished in %v: %d xaction(s) on %d target(s), %d object(s), %s",
+		elapsed.Round(time.Second), o.xacts, o.targets, o.objects, cmn.B2S(o.bytes, 2))
+	if o.aborted > 0 {
+		s += fmt.Sprintf(", %d aborted", o.aborted)
+	}
+	if o.errcnt > 0 {
+		s += fmt.Sprintf(", %d error(s), last: %s", o.errcnt, o.lasterr)
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+func TestJobsValidateAndUpdate(t *testing.T) {
+	invalid := []cmn.Job{
+		{Schedule: "@daily", Kind: cmn.ActLRU},                                      // no name
+		{Name: "j", Schedule: "0 25 * * *", Kind: cmn.ActLRU},                       // hour
+		{Name: "j", Schedule: "@daily", Kind: cmn.ActPutCopies},                     // kind
+		{Name: "j", Schedule: "@daily", Kind: cmn.ActRechecksum},                    // bucket
+		{Name: "j", Schedule: "@daily", Kind: cmn.ActLRU, Overlap: "wait-for-mine"}, // overlap
+	}
+	for _, job := range invalid {
+		if errstr := validateJob(&job); errstr == "" {
+			t.Errorf("expecting %+v to be invalid", job)
+		}
+	}
+	job := cmn.Job{Name: "nightly-lru", Schedule: "0 2 * * *", Kind: cmn.ActLRU}
+	if errstr := validateJob(&job); errstr != "" || job.Overlap != cmn.JobOverlapSkip {
+		t.Fatalf("expecting valid job with default overlap policy, got %q, %+v", errstr, job)
+	}
+
+	v1 := (&jobsMD{}).clone()
+	v1.add(job)
+	v2 := v1.clone()
+	v2.add(cmn.Job{Name: "rechecksum", Schedule: "@weekly", Kind: cmn.ActRechecksum, Bucket: "b"})
+	v2.add(cmn.Job{Name: job.Name, Schedule: "0 3 * * *", Kind: cmn.ActLRU}) // replace
+	if v2.version() != 2 || len(v2.Jobs) != 2 || len(v1.Jobs) != 1 {
+		t.Fatalf("expecting v2 with 2 jobs and unmodified v1, got %+v and %+v", v2, v1)
+	}
+	if j, ok := v2.find(job.Name); !ok || j.Schedule != "0 3 * * *" {
+		t.Fatalf("expecting updated %q, got %+v", job.Name, j)
+	}
+	v3 := v2.clone()
+	if !v3.remove(job.Name) || v3.remove(job.Name) || len(v3.Jobs) != 1 || len(v2.Jobs) != 2 {
+		t.Fatalf("expecting v3 without %q and unmodified v2, got %+v and %+v", job.Name, v3, v2)
+	}
+}
+
+func TestJobsOverlap(t *testing.T) {
+	var (
+		s     = newJobScheduler(nil)
+		skip  = cmn.Job{Name: "skip", Overlap: cmn.JobOverlapSkip}
+		queue = cmn.Job{Name: "queue", Overlap: cmn.JobOverlapQueue}
+	)
+	for _, job := range []*cmn.Job{&skip, &queue} {
+		if start, _ := s.admit(job); !start {
+			t.Fatalf("%s: expecting to start", job.Name)
+		}
+	}
+	if start, note := s.admit(&skip); start || note != "skipped" {
+		t.Fatalf("expecting overlapping run to be skipped, got %t, %q", start, note)
+	}
+	for i := 0; i < 2; i++ { // at most one run gets queued
+		if start, note := s.admit(&queue); start || note != "queued" {
+			t.Fatalf("expecting overlapping run to be queued, got %t, %q", start, note)
+		}
+	}
+	if s.done(skip.Name) {
+		t.Fatalf("%s: not expecting to run again", skip.Name)
+	}
+	if !s.done(queue.Name) || s.done(queue.Name) {
+		t.Fatalf("%s: expecting to run again exactly once", queue.Name)
+	}
+	if start, _ := s.admit(&skip); !start {
+		t.Fatalf("%s: expecting to start once the previous run is done", skip.Name)
+	}
+}
+
+func TestJobsXactQuery(t *testing.T) {
+	job := cmn.Job{Name: "j", Kind: cmn.ActRechecksum, Bucket: "abc"}
+	query := jobXactQuery(&job, time.Time{})
+	if query.Get(cmn.URLParamXactBucket) != "abc" || query.Get(cmn.URLParamXactAll) != "" {
+		t.Errorf("expecting running %s xactions of the bucket, got %v", job.Kind, query)
+	}
+	job = cmn.Job{Name: "j", Kind: cmn.ActPrefetch, Bucket: "abc"}
+	query = jobXactQuery(&job, time.Now())
+	if query.Get(cmn.URLParamXactBucket) != "" || query.Get(cmn.URLParamXactAll) != "true" ||
+		query.Get(cmn.URLParamSince) == "" {
+		t.Errorf("expecting all %s xactions since the start, got %v", job.Kind, query)
+	}
+
+	// the handler's error is the job's outcome
+	resp := newJobResponse()
+	cmn.InvalidHandlerWithMsg(resp, &http.Request{Method: http.MethodPut, URL: &url.URL{Path: "/v1/xactions"}}, "bad request")
+	if resp.status != http.StatusBadRequest || !strings.Contains(resp.body.String(), "bad request") {
+		t.Errorf("expecting status %d and the error message, got %d %q", http.StatusBadRequest, resp.status, resp.body.String())
+	}
+}
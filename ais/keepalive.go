@@ -59,6 +59,10 @@ type targetKeepaliveRunner struct {
 type proxyKeepaliveRunner struct {
 	p *proxyrunner
 	keepalive
+	// non-responding nodes that are yet to be removed from the Smap, along with
+	// the time they were first suspected; updated by the primary's pingAllOthers
+	suspects map[string]time.Time
+	mu       sync.Mutex // protects suspects
 }
 
 type keepalive struct {
@@ -137,7 +141,7 @@ func newTargetKeepaliveRunner(t *targetrunner) *targetKeepaliveRunner {
 func newProxyKeepaliveRunner(p *proxyrunner) *proxyKeepaliveRunner {
 	config := cmn.GCO.Get()
 
-	pkr := &proxyKeepaliveRunner{p: p}
+	pkr := &proxyKeepaliveRunner{p: p, suspects: make(map[string]time.Time)}
 	pkr.keepalive.k = pkr
 	pkr.kt = newKeepaliveTracker(config.KeepaliveTracker.Proxy, &p.statsdC)
 	pkr.tt = &timeoutTracker{timeoutStatsMap: make(map[string]*timeoutStats)}
@@ -221,6 +225,10 @@ func (pkr *proxyKeepaliveRunner) pingAllOthers() (stopped bool) {
 			if !pkr.isTimeToPing(sid) {
 				continue
 			}
+			// Targets in maintenance for reboot are expected to be (briefly) down.
+			if smap.MaintenanceMode(sid) == cmn.MaintenanceReboot {
+				continue
+			}
 			wg.Add(1)
 			go func(si *cluster.Snode) {
 				if len(stoppedCh) > 0 {
@@ -248,6 +256,19 @@ func (pkr *proxyKeepaliveRunner) pingAllOthers() (stopped bool) {
 
 	pkr.statsMinMaxLat(latencyCh)
 
+	toRemove := make([]string, 0, len(toRemoveCh))
+	alive := smap.CountProxies()
+	for sid := range toRemoveCh {
+		toRemove = append(toRemove, sid)
+		if smap.GetProxy(sid) != nil {
+			alive--
+		}
+	}
+	if len(stoppedCh) == 0 && pkr.p.updateQuorum(alive) {
+		return false // no quorum: not removing non-responding daemons (see quorum.go)
+	}
+	toRemove = pkr.suspect(smap, toRemove)
+
 	pkr.p.smapowner.Lock()
 	newSmap := pkr.p.smapowner.get()
 	if !newSmap.isPrimary(pkr.p.si) {
@@ -260,13 +281,13 @@ func (pkr *proxyKeepaliveRunner) pingAllOthers() (stopped bool) {
 		pkr.p.smapowner.Unlock()
 		return true
 	}
-	if len(toRemoveCh) == 0 {
+	if len(toRemove) == 0 {
 		pkr.p.smapowner.Unlock()
 		return false
 	}
 	clone := newSmap.clone()
 	metaction := "keepalive: removing ["
-	for sid := range toRemoveCh {
+	for _, sid := range toRemove {
 		if clone.GetProxy(sid) != nil {
 			clone.delProxy(sid)
 			metaction += " proxy " + sid
@@ -288,6 +309,61 @@ func (pkr *proxyKeepaliveRunner) pingAllOthers() (stopped bool) {
 	return
 }
 
+// suspect puts the daemons that failed to respond into the "suspect" state and
+// returns those that have stayed there for at least the configured suspect time
+// (all of them, if the latter is zero); daemons that responded are cleared
+func (pkr *proxyKeepaliveRunner) suspect(smap *smapX, failed []string) (toRemove []string) {
+	var (
+		now         = time.Now()
+		suspectTime = cmn.GCO.Get().KeepaliveTracker.SuspectTime
+		failing     = make(map[string]struct{}, len(failed))
+	)
+	pkr.mu.Lock()
+	defer pkr.mu.Unlock()
+	for _, sid := range failed {
+		failing[sid] = struct{}{}
+		since, ok := pkr.suspects[sid]
+		if !ok {
+			since = now
+			pkr.suspects[sid] = now
+			pkr.p.statsif.Add(stats.KeepAliveSuspectCount, 1)
+		}
+		if now.Sub(since) >= suspectTime {
+			toRemove = append(toRemove, sid)
+		} else if !ok {
+			glog.Warningf("keepalive: %s is suspect, removing it from the smap in %v unless it responds",
+				sid, suspectTime)
+		}
+	}
+	for sid := range pkr.suspects {
+		if _, ok := failing[sid]; ok {
+			continue
+		}
+		if smap.GetTarget(sid) != nil || smap.GetProxy(sid) != nil {
+			glog.Infof("keepalive: %s is no longer suspect", sid)
+		}
+		delete(pkr.suspects, sid)
+	}
+	return
+}
+
+// keepaliveStats returns the current suspicion levels (phi tracker only) and suspects
+func (pkr *proxyKeepaliveRunner) keepaliveStats() *cmn.KeepaliveStats {
+	st := &cmn.KeepaliveStats{Tracker: cmn.GCO.Get().KeepaliveTracker.Proxy.Name}
+	if pt, ok := pkr.kt.(*PhiAccrualTracker); ok {
+		st.Phi = pt.phis()
+	}
+	pkr.mu.Lock()
+	if len(pkr.suspects) > 0 {
+		st.Suspects = make(map[string]time.Time, len(pkr.suspects))
+		for sid, since := range pkr.suspects {
+			st.Suspects[sid] = since
+		}
+	}
+	pkr.mu.Unlock()
+	return st
+}
+
 // min & max keepalive stats
 func (pkr *proxyKeepaliveRunner) statsMinMaxLat(latencyCh chan time.Duration) {
 	min, max := time.Duration(time.Hour), time.Duration(0)
@@ -516,6 +592,7 @@ func (k *keepalive) Stop(err error) {
 var (
 	_ KeepaliveTracker = &HeartBeatTracker{}
 	_ KeepaliveTracker = &AverageTracker{}
+	_ KeepaliveTracker = &PhiAccrualTracker{}
 )
 
 // HeartBeatTracker tracks the timestamp of the last time a message is received from a server.
@@ -534,6 +611,8 @@ func newKeepaliveTracker(c cmn.KeepaliveTrackerConf, statsdC *statsd.Client) Kee
 		return newHeartBeatTracker(c.Interval, statsdC)
 	case cmn.KeepaliveAverageType:
 		return newAverageTracker(c.Factor, statsdC)
+	case cmn.KeepalivePhiType:
+		return newPhiAccrualTracker(c.Interval, c.Threshold, statsdC)
 	}
 	return nil
 }
@@ -665,3 +744,141 @@ func (a *AverageTracker) TimedOut(id string) bool {
 
 	return int64(time.Since(rec.last)/time.Millisecond) > int64(a.factor)*rec.avg()
 }
+
+// PhiAccrualTracker implements the phi accrual failure detector (Hayashibara et al.):
+// instead of the binary up/down it computes the level of suspicion (phi) given
+// the time since the last message and the distribution of the recent inter-arrival
+// times. Timeout: phi exceeds the threshold - e.g., phi = 8 corresponds to the
+// probability of 1e-8 to receive the message later than now.
+type PhiAccrualTracker struct {
+	ch        chan struct{}
+	rec       map[string]*phiTrackerRecord
+	interval  time.Duration // initial estimate of the inter-arrival time
+	threshold float64
+	statsdC   *statsd.Client
+}
+
+type phiTrackerRecord struct {
+	last      time.Time
+	intervals []float64 // the last phiWindow inter-arrival times, in ms
+	next      int
+}
+
+const (
+	phiWindow = 100
+	phiMax    = 1000 // reported suspicion level of the long gone servers
+)
+
+func (rec *phiTrackerRecord) add(ms float64) {
+	if len(rec.intervals) < phiWindow {
+		rec.intervals = append(rec.intervals, ms)
+		return
+	}
+	rec.intervals[rec.next] = ms
+	rec.next = (rec.next + 1) % phiWindow
+}
+
+// phi returns the suspicion level given the time elapsed since the last message;
+// the inter-arrival times are assumed to be normally distributed, with the standard
+// deviation of at least a quarter of the mean to tolerate (short) GC pauses and load spikes
+func (rec *phiTrackerRecord) phi(elapsed time.Duration) float64 {
+	var mean, variance float64
+	for _, ms := range rec.intervals {
+		mean += ms
+	}
+	mean /= float64(len(rec.intervals))
+	for _, ms := range rec.intervals {
+		variance += (ms - mean) * (ms - mean)
+	}
+	variance /= float64(len(rec.intervals))
+	stddev := math.Max(math.Sqrt(variance), mean/4)
+	if stddev == 0 {
+		return 0
+	}
+	// logistic approximation of the normal CDF
+	y := (float64(elapsed/time.Millisecond) - mean) / stddev
+	e := math.Exp(-y * (1.5976 + 0.070566*y*y))
+	if y > 0 {
+		return -math.Log10(e / (1 + e))
+	}
+	return -math.Log10(1 - 1/(1+e))
+}
+
+// newPhiAccrualTracker returns a PhiAccrualTracker.
+func newPhiAccrualTracker(interval time.Duration, threshold float64, statsdC *statsd.Client) *PhiAccrualTracker {
+	pt := &PhiAccrualTracker{
+		rec:       make(map[string]*phiTrackerRecord),
+		ch:        make(chan struct{}, 1),
+		statsdC:   statsdC,
+		interval:  interval,
+		threshold: threshold,
+	}
+
+	pt.unlock()
+	return pt
+}
+
+func (pt *PhiAccrualTracker) lock() {
+	<-pt.ch
+}
+
+func (pt *PhiAccrualTracker) unlock() {
+	pt.ch <- struct{}{}
+}
+
+// HeardFrom is called to indicate a keepalive message (or equivalent) has been received from a server.
+func (pt *PhiAccrualTracker) HeardFrom(id string, reset bool) {
+	pt.lock()
+	t := time.Now()
+	rec, ok := pt.rec[id]
+	if reset || !ok {
+		// bootstrap with the expected interval
+		rec = &phiTrackerRecord{intervals: []float64{float64(pt.interval / time.Millisecond)}}
+		rec.last = t
+		pt.rec[id] = rec
+		pt.unlock()
+		pt.statsdC.Send("keepalive.phi."+id, metric{Type: statsd.Counter, Name: "reset", Value: 1})
+		return
+	}
+	delta := t.Sub(rec.last)
+	rec.last = t
+	rec.add(float64(delta / time.Millisecond))
+	pt.unlock()
+
+	pt.statsdC.Send("keepalive.phi."+id,
+		metric{Type: statsd.Gauge, Name: "delta", Value: int64(delta / time.Millisecond)},
+		metric{Type: statsd.Counter, Name: "count", Value: 1})
+}
+
+// Phi returns the current suspicion level for the server; +Inf if never heard from.
+func (pt *PhiAccrualTracker) Phi(id string) float64 {
+	pt.lock()
+	defer pt.unlock()
+	rec, ok := pt.rec[id]
+	if !ok {
+		return math.Inf(1)
+	}
+	return rec.phi(time.Since(rec.last))
+}
+
+// phis returns the current suspicion levels of all servers heard from, capped
+// at phiMax (for large enough elapsed times phi overflows to +Inf)
+func (pt *PhiAccrualTracker) phis() map[string]float64 {
+	pt.lock()
+	defer pt.unlock()
+	phis := make(map[string]float64, len(pt.rec))
+	for id, rec := range pt.rec {
+		phis[id] = math.Min(rec.phi(time.Since(rec.last)), phiMax)
+	}
+	return phis
+}
+
+// TimedOut returns true if the suspicion level has reached the threshold.
+func (pt *PhiAccrualTracker) TimedOut(id string) bool {
+	phi := pt.Phi(id)
+	if math.IsInf(phi, 1) {
+		return true
+	}
+	pt.statsdC.Send("keepalive.phi."+id, metric{Type: statsd.Gauge, Name: "phi", Value: phi})
+	return phi > pt.threshold
+}
@@ -4,6 +4,7 @@
 package ais
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -124,3 +125,84 @@ func TestKeepaliveTrackerHeartBeat(t *testing.T) {
 		t.Fatal("Expecting time out")
 	}
 }
+
+func TestKeepaliveTrackerPhiAccrual(t *testing.T) {
+	pt := newPhiAccrualTracker(time.Millisecond*10, 8, &statsd.Client{})
+
+	if !pt.TimedOut("unknown server") {
+		t.Fatal("None existing server should return timed out")
+	}
+
+	id1 := "1"
+	pt.HeardFrom(id1, false)
+	for i := 0; i < 5; i++ {
+		time.Sleep(time.Millisecond * 10)
+		pt.HeardFrom(id1, false)
+	}
+	if pt.TimedOut(id1) {
+		t.Fatal("Expecting no time out")
+	}
+
+	// a pause that's somewhat longer than usual raises the suspicion but is tolerated
+	time.Sleep(time.Millisecond * 15)
+	if pt.TimedOut(id1) {
+		t.Fatalf("Expecting no time out, phi %.2f", pt.Phi(id1))
+	}
+
+	time.Sleep(time.Millisecond * 50)
+	if !pt.TimedOut(id1) {
+		t.Fatalf("Expecting time out, phi %.2f", pt.Phi(id1))
+	}
+
+	pt.HeardFrom(id1, true)
+	if pt.TimedOut(id1) {
+		t.Fatal("Expecting no time out after reset")
+	}
+}
+
+func TestPhiGrowsWithElapsedTime(t *testing.T) {
+	rec := &phiTrackerRecord{}
+	for i := 0; i < phiWindow*2; i++ {
+		rec.add(float64(1000 + i%3*100)) // ~1s, jittered
+	}
+	if len(rec.intervals) != phiWindow {
+		t.Fatalf("Expecting the window of %d intervals, got %d", phiWindow, len(rec.intervals))
+	}
+	prev := -1.0
+	for _, elapsed := range []time.Duration{0, time.Second, 2 * time.Second, 3 * time.Second} {
+		phi := rec.phi(elapsed)
+		if phi <= prev {
+			t.Errorf("Expecting phi to grow with the elapsed time, got %.2f at %v (previous %.2f)", phi, elapsed, prev)
+		}
+		prev = phi
+	}
+	if phi := rec.phi(time.Second); phi > 1 {
+		t.Errorf("Expecting low suspicion at the mean inter-arrival time, got %.2f", phi)
+	}
+	if phi := rec.phi(5 * time.Second); phi < 8 {
+		t.Errorf("Expecting high suspicion at 5x the mean inter-arrival time, got %.2f", phi)
+	}
+}
+
+func TestKeepaliveStats(t *testing.T) {
+	pt := newPhiAccrualTracker(time.Millisecond*10, 8, &statsd.Client{})
+	pkr := &proxyKeepaliveRunner{suspects: make(map[string]time.Time)}
+	pkr.kt = pt
+
+	pt.HeardFrom("1", false)
+	pt.HeardFrom("2", false)
+	pt.rec["2"].last = time.Now().Add(-time.Hour) // long gone
+	since := time.Now()
+	pkr.suspects["2"] = since
+
+	st := pkr.keepaliveStats()
+	if len(st.Phi) != 2 || st.Phi["1"] >= 8 || st.Phi["2"] != phiMax {
+		t.Errorf("Unexpected suspicion levels %v", st.Phi)
+	}
+	if len(st.Suspects) != 1 || !st.Suspects["2"].Equal(since) {
+		t.Errorf("Unexpected suspects %v", st.Suspects)
+	}
+	if _, err := json.Marshal(st); err != nil {
+		t.Error(err)
+	}
+}
@@ -114,6 +114,7 @@ func (t *targetrunner) doListEvictDelete(ct context.Context, evict bool, objs []
 		absdeadline = time.Now().Add(deadline)
 	}
 
+	xdel.SetTotal(int64(len(objs)))
 	for _, objname := range objs {
 		if xdel.Aborted() {
 			return nil
@@ -132,8 +133,11 @@ func (t *targetrunner) doListEvictDelete(ct context.Context, evict bool, objs []
 		}
 		err := t.objDelete(ct, lom, evict)
 		if err != nil {
+			xdel.AddErr(err)
 			return err
 		}
+		xdel.ObjectsAdd(1)
+		xdel.BytesAdd(lom.Size)
 	}
 
 	return nil
@@ -181,7 +185,7 @@ func (t *targetrunner) prefetchMissing(ct context.Context, objname, bucket, bckP
 	if !coldGet {
 		return
 	}
-	if errstr, _ = t.getCold(ct, lom, true); errstr != "" {
+	if errstr, _ = t.getCold(ct, lom, true, 0); errstr != "" {
 		if errstr != "skip" {
 			glog.Errorln(errstr)
 		}
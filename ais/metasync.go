@@ -7,6 +7,7 @@ package ais
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,6 +22,9 @@ const (
 	smaptag     = "smaptag"
 	bucketmdtag = "bucketmdtag" //
 	tokentag    = "tokentag"    //
+	eventstag   = "eventstag"   // cluster event log (see events.go) - proxies only
+	jobstag     = "jobstag"     // scheduled jobs (see jobs.go)
+	conftag     = "conftag"     // cluster-wide configuration (see clusterconf.go)
 	actiontag   = "-action"     // to make a pair (revs, action)
 )
 
@@ -217,6 +221,7 @@ func (y *metasyncer) becomeNonPrimary() {
 func (y *metasyncer) doSync(pairs []revspair) (cnt int) {
 	var (
 		jsbytes, jsmsg []byte
+		tbody          []byte
 		err            error
 		refused        cluster.NodeMap
 		payload        = make(cmn.SimpleKVs)
@@ -238,6 +243,7 @@ func (y *metasyncer) doSync(pairs []revspair) (cnt int) {
 		}
 	}
 
+	pairs = y.withEvents(pairs)
 	pairsToSend := pairs[:0] // share original slice
 OUTER:
 	for _, pair := range pairs {
@@ -292,21 +298,12 @@ OUTER:
 		payload[tag] = string(jsbytes)         // payload
 		payload[tag+actiontag] = string(jsmsg) // action message always on the wire even when empty
 	}
-	jsbytes, err = jsoniter.Marshal(payload)
-	cmn.AssertNoErr(err)
+	jsbytes, tbody = marshalPayload(payload)
 
 	// step 3: b-cast
 	urlPath := cmn.URLPath(cmn.Version, cmn.Metasync)
-	res := y.p.broadcastTo(
-		urlPath,
-		nil, // query
-		http.MethodPut,
-		jsbytes,
-		smap,
-		config.Timeout.CplaneOperation*2, // making exception for this critical op
-		cmn.NetworkIntraControl,
-		cluster.AllNodes,
-	)
+	res := y.bcast(urlPath, jsbytes, tbody, smap.Pmap, smap.Tmap,
+		config.Timeout.CplaneOperation*2) // making exception for this critical op
 
 	// step 4: count failures and fill-in refused
 	for r := range res {
@@ -336,7 +333,7 @@ OUTER:
 			y.becomeNonPrimary()
 			return
 		}
-		y.handleRefused(urlPath, jsbytes, refused, pairsToSend, config, smap)
+		y.handleRefused(urlPath, jsbytes, tbody, refused, pairsToSend, config, smap)
 	}
 	// step 6: housekeep and return new pending
 	smap = y.p.smapowner.get()
@@ -349,6 +346,48 @@ OUTER:
 	return
 }
 
+// withEvents records new Smap and BMD versions in the cluster event log, and makes
+// sure that the latest event log (if newer than the one last sync-ed) gets sync-ed
+// along with them (see events.go)
+func (y *metasyncer) withEvents(pairs []revspair) []revspair {
+	var (
+		events = make([]cmn.ClusterEvent, 0, 2)
+		out    = pairs[:0] // share original slice
+		now    = time.Now()
+	)
+	for _, pair := range pairs {
+		tag := pair.revs.tag()
+		if tag == eventstag {
+			continue // the latest is added below
+		}
+		out = append(out, pair)
+		if pair.revs.version() <= y.lversion(tag) {
+			continue
+		}
+		var ev cmn.ClusterEvent
+		switch tag {
+		case smaptag:
+			prev, _ := y.last[tag].(*smapX)
+			ev = smapEvent(prev, pair.revs.(*smapX), pair.msgInt)
+		case bucketmdtag:
+			prev, _ := y.last[tag].(*bucketMD)
+			ev = bmdEvent(prev, pair.revs.(*bucketMD), pair.msgInt)
+		default:
+			continue
+		}
+		ev.Time, ev.Node = now, y.p.si.DaemonID
+		events = append(events, ev)
+	}
+	evlog := y.p.events.get()
+	if len(events) > 0 {
+		evlog = y.p.events.add(events...)
+	}
+	if evlog.version() > y.lversion(eventstag) {
+		out = append(out, revspair{evlog, y.p.newActionMsgInternalStr(eventstag, nil, nil)})
+	}
+	return out
+}
+
 // keeping track of per-daemon versioning
 func (y *metasyncer) syncDone(sid string, pairs []revspair) {
 	revsdaemon := y.revsmap[sid]
@@ -356,25 +395,100 @@ func (y *metasyncer) syncDone(sid string, pairs []revspair) {
 		revsdaemon = make(map[string]int64)
 		y.revsmap[sid] = revsdaemon
 	}
+	isProxy := y.p.smapowner.get().GetProxy(sid) != nil
 	for _, revspair := range pairs {
 		revs := revspair.revs
+		if !isProxy && proxyOnly(revs.tag()) {
+			continue
+		}
 		revsdaemon[revs.tag()] = revs.version()
 	}
 }
 
-func (y *metasyncer) handleRefused(urlPath string, body []byte, refused cluster.NodeMap, pairs []revspair,
+// splitNodes separates proxies from targets (and those that are no longer in the Smap)
+func splitNodes(nodes cluster.NodeMap, smap *smapX) (pmap, tmap cluster.NodeMap) {
+	pmap, tmap = make(cluster.NodeMap, len(nodes)), make(cluster.NodeMap, len(nodes))
+	for id, si := range nodes {
+		if smap.GetProxy(id) != nil {
+			pmap[id] = si
+		} else {
+			tmap[id] = si
+		}
+	}
+	return
+}
+
+// the revs that are sync-ed to proxies only
+func proxyOnly(tag string) bool { return tag == eventstag }
+
+// marshalPayload returns the metasync payload for proxies and the one for targets
+// (nil if there's nothing to send them) - without the proxy-only revs
+func marshalPayload(payload cmn.SimpleKVs) (body, tbody []byte) {
+	var err error
+	body, err = jsoniter.Marshal(payload)
+	cmn.AssertNoErr(err)
+	tpayload := make(cmn.SimpleKVs, len(payload))
+	for key, val := range payload {
+		if !proxyOnly(strings.TrimSuffix(key, actiontag)) {
+			tpayload[key] = val
+		}
+	}
+	switch len(tpayload) {
+	case 0:
+	case len(payload):
+		tbody = body
+	default:
+		tbody, err = jsoniter.Marshal(tpayload)
+		cmn.AssertNoErr(err)
+	}
+	return
+}
+
+// bcast sends the respective payloads (see marshalPayload) to the given proxies
+// and targets in parallel
+func (y *metasyncer) bcast(urlPath string, body, tbody []byte, pmap, tmap cluster.NodeMap,
+	timeout time.Duration) chan callResult {
+	var (
+		res = make(chan callResult, len(pmap)+len(tmap))
+		wg  = &sync.WaitGroup{}
+	)
+	if tbody == nil {
+		tmap = nil
+	}
+	for _, b := range []struct {
+		body  []byte
+		nodes cluster.NodeMap
+	}{{body, pmap}, {tbody, tmap}} {
+		if len(b.nodes) == 0 {
+			continue
+		}
+		bcastArgs := bcastCallArgs{
+			req: reqArgs{
+				method: http.MethodPut,
+				path:   urlPath,
+				body:   b.body,
+			},
+			network: cmn.NetworkIntraControl,
+			timeout: timeout,
+			nodes:   []cluster.NodeMap{b.nodes},
+		}
+		wg.Add(1)
+		go func() {
+			for r := range y.p.broadcast(bcastArgs) {
+				res <- r
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	close(res)
+	return res
+}
+
+func (y *metasyncer) handleRefused(urlPath string, body, tbody []byte, refused cluster.NodeMap, pairs []revspair,
 	config *cmn.Config, smap *smapX) {
-	bcastArgs := bcastCallArgs{
-		req: reqArgs{
-			method: http.MethodPut,
-			path:   urlPath,
-			body:   body,
-		},
-		network: cmn.NetworkIntraControl,
-		timeout: config.Timeout.MaxKeepalive, // JSON config "max_keepalive"
-		nodes:   []cluster.NodeMap{refused},
-	}
-	res := y.p.broadcast(bcastArgs)
+	pmap, tmap := splitNodes(refused, smap)
+	res := y.bcast(urlPath, body, tbody, pmap, tmap, config.Timeout.MaxKeepalive) // JSON config "max_keepalive"
 
 	for r := range res {
 		if r.err == nil {
@@ -407,8 +521,11 @@ func (y *metasyncer) pending(needMap bool) (count int, pending cluster.NodeMap,
 					continue
 				}
 			} else {
-				inSync := true
+				inSync, isProxy := true, smap.GetProxy(id) != nil
 				for tag, revs := range y.last {
+					if !isProxy && proxyOnly(tag) {
+						continue
+					}
 					v, ok := revsdaemon[tag]
 					if !ok || v != revs.version() {
 						cmn.Assert(!ok || v < revs.version())
@@ -451,20 +568,10 @@ func (y *metasyncer) handlePending() (cnt int) {
 		pairs = append(pairs, revspair{revs, msgInt})
 	}
 
-	body, err := jsoniter.Marshal(payload)
-	cmn.AssertNoErr(err)
-
-	bcastArgs := bcastCallArgs{
-		req: reqArgs{
-			method: http.MethodPut,
-			path:   cmn.URLPath(cmn.Version, cmn.Metasync),
-			body:   body,
-		},
-		network: cmn.NetworkIntraControl,
-		timeout: cmn.GCO.Get().Timeout.CplaneOperation,
-		nodes:   []cluster.NodeMap{pending},
-	}
-	res := y.p.broadcast(bcastArgs)
+	body, tbody := marshalPayload(payload)
+	pmap, tmap := splitNodes(pending, smap)
+	res := y.bcast(cmn.URLPath(cmn.Version, cmn.Metasync), body, tbody, pmap, tmap,
+		cmn.GCO.Get().Timeout.CplaneOperation)
 	for r := range res {
 		if r.err == nil {
 			y.syncDone(r.si.DaemonID, pairs)
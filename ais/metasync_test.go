@@ -16,6 +16,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	jsoniter "github.com/json-iterator/go"
 )
@@ -814,3 +815,40 @@ func testSyncer(p *proxyrunner) (syncer *metasyncer) {
 	syncer = newmetasyncer(p)
 	return
 }
+
+// TestProxyOnlyRevs checks that targets are not expected to get the cluster event log
+func TestProxyOnlyRevs(t *testing.T) {
+	var (
+		primary = newPrimary()
+		syncer  = testSyncer(primary)
+		clone   = primary.smapowner.get().clone()
+		evlog   = &eventLog{Version: 1}
+	)
+	clone.addProxy(newSnode("p1", httpProto, &net.TCPAddr{}, &net.TCPAddr{}, &net.TCPAddr{}))
+	clone.addTarget(newSnode("t1", httpProto, &net.TCPAddr{}, &net.TCPAddr{}, &net.TCPAddr{}))
+	clone.Version++
+	primary.smapowner.put(clone)
+
+	pmap, tmap := splitNodes(cluster.NodeMap{"p1": clone.GetProxy("p1"), "t1": clone.GetTarget("t1")}, clone)
+	if len(pmap) != 1 || pmap["p1"] == nil || len(tmap) != 1 || tmap["t1"] == nil {
+		t.Fatalf("expecting p1 and t1 separated, got %v and %v", pmap, tmap)
+	}
+
+	msgInt := primary.newActionMsgInternalStr("", clone, nil)
+	pairs := []revspair{{clone, msgInt}, {evlog, msgInt}}
+	syncer.last[smaptag], syncer.last[eventstag] = clone, evlog
+	for _, sid := range []string{primary.si.DaemonID, "p1", "t1"} {
+		syncer.syncDone(sid, pairs)
+	}
+	if _, ok := syncer.revsmap["t1"][eventstag]; ok {
+		t.Error("target t1 is not expected to get the cluster event log")
+	}
+	if cnt, pending, _ := syncer.pending(true); cnt != 0 || len(pending) != 0 {
+		t.Errorf("expecting all in sync, got %d pending: %v", cnt, pending)
+	}
+
+	syncer.last[eventstag] = &eventLog{Version: 2}
+	if _, pending, _ := syncer.pending(true); len(pending) != 2 || pending["p1"] == nil || pending["t1"] != nil {
+		t.Errorf("expecting proxies (only) to get the newer event log, got %v", pending)
+	}
+}
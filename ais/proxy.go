@@ -81,7 +81,10 @@ type proxyrunner struct {
 	starttime  time.Time
 	authn      *authManager
 	startedUp  int64
+	minority   int32 // (atomic) 1: the primary cannot reach the majority of proxies (see quorum.go)
 	metasyncer *metasyncer
+	events     eventsowner // cluster event log (see events.go)
+	jobs       jobsowner   // scheduled jobs (see jobs.go)
 	rproxy     struct {
 		sync.Mutex
 		cloud *httputil.ReverseProxy            // unmodified GET requests => storage.googleapis.com
@@ -96,6 +99,7 @@ func (p *proxyrunner) Run() error {
 	config := cmn.GCO.Get()
 	p.httprunner.init(getproxystatsrunner(), true)
 	p.httprunner.keepalive = getproxykeepalive()
+	getproxystatsrunner().Keepalive = getproxykeepalive().keepaliveStats
 
 	bucketmdfull := filepath.Join(config.Confdir, cmn.BucketmdBackupFile)
 	bucketmd := newBucketMD()
@@ -107,6 +111,9 @@ func (p *proxyrunner) Run() error {
 		}
 	}
 	p.bmdowner.put(bucketmd)
+	p.events.init(config.Confdir)
+	p.jobs.init(config.Confdir)
+	p.confowner.init(config.Confdir, p.si.DaemonID, cmn.SetConfigMany)
 
 	p.metasyncer = getmetasyncer()
 
@@ -153,6 +160,7 @@ func (p *proxyrunner) Run() error {
 		networkHandler{r: cmn.Cluster, h: p.clusterHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl}},
 		networkHandler{r: cmn.Tokens, h: p.tokenHandler, net: []string{cmn.NetworkPublic}},
 		networkHandler{r: cmn.Sort, h: dsort.ProxySortHandler, net: []string{cmn.NetworkPublic}},
+		networkHandler{r: cmn.Xactions, h: p.xactHandler, net: []string{cmn.NetworkPublic}},
 
 		networkHandler{r: cmn.Metasync, h: p.metasyncHandler, net: []string{cmn.NetworkIntraControl}},
 		networkHandler{r: cmn.Health, h: p.healthHandler, net: []string{cmn.NetworkIntraControl}},
@@ -224,6 +232,7 @@ func (p *proxyrunner) Stop(err error) {
 	}
 	glog.Infof("Stopping %s (%s, primary=%t), err: %v", p.Getname(), pname(p.si), isPrimary, err)
 	p.xactions.abortAll()
+	p.events.flush()
 
 	if isPrimary {
 		// give targets and non primary proxies some time to unregister
@@ -480,6 +489,9 @@ func (p *proxyrunner) httpbckdelete(w http.ResponseWriter, r *http.Request) {
 		if p.forwardCP(w, r, &msg, bucket, nil) {
 			return
 		}
+		if !p.checkQuorum(w, r, msg.Action) {
+			return
+		}
 		bucketmd := p.bmdowner.get()
 		if !bucketmd.IsLocal(bucket) {
 			p.invalmsghdlr(w, r, fmt.Sprintf("Bucket %s does not appear to be local", bucket))
@@ -517,6 +529,9 @@ func (p *proxyrunner) httpbckdelete(w http.ResponseWriter, r *http.Request) {
 		if p.forwardCP(w, r, &msg, bucket, nil) {
 			return
 		}
+		if !p.checkQuorum(w, r, msg.Action) {
+			return
+		}
 		bucketmd := p.bmdowner.get()
 		// check for and delete cloud metadata
 		p.bmdowner.Lock()
@@ -622,19 +637,22 @@ func (p *proxyrunner) metasyncHandler(w http.ResponseWriter, r *http.Request) {
 
 // PUT /v1/metasync
 func (p *proxyrunner) metasyncHandlerPut(w http.ResponseWriter, r *http.Request) {
-	// FIXME: may not work if got disconnected for a while and have missed elections (#109)
+	var payload = make(cmn.SimpleKVs)
+	if err := cmn.ReadJSON(w, r, &payload); err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	// the primary that got disconnected and missed elections yields to the newer one
 	if p.smapowner.get().isPrimary(p.si) {
+		if p.yield(payload) {
+			return
+		}
 		_, xx := p.xactions.findL(cmn.ActElection)
 		vote := xx != nil
 		s := fmt.Sprintf("Primary %s cannot receive cluster meta (election=%t)", p.si, vote)
 		p.invalmsghdlr(w, r, s)
 		return
 	}
-	var payload = make(cmn.SimpleKVs)
-	if err := cmn.ReadJSON(w, r, &payload); err != nil {
-		p.invalmsghdlr(w, r, err.Error())
-		return
-	}
 
 	newsmap, _, errstr := p.extractSmap(payload)
 	if errstr != "" {
@@ -672,6 +690,33 @@ func (p *proxyrunner) metasyncHandlerPut(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	p.authn.updateRevokedList(revokedTokens)
+
+	evlog, errstr := p.extractEventLog(payload)
+	if errstr != "" {
+		p.invalmsghdlr(w, r, errstr)
+		return
+	}
+	if evlog != nil {
+		p.events.synchronize(evlog)
+	}
+
+	jobs, errstr := p.extractJobs(payload)
+	if errstr != "" {
+		p.invalmsghdlr(w, r, errstr)
+		return
+	}
+	if jobs != nil {
+		p.jobs.synchronize(jobs)
+	}
+
+	conf, errstr := p.extractClusterConf(payload)
+	if errstr != "" {
+		p.invalmsghdlr(w, r, errstr)
+		return
+	}
+	if conf != nil {
+		p.confowner.synchronize(conf)
+	}
 }
 
 // GET /v1/health
@@ -742,6 +787,9 @@ func (p *proxyrunner) httpbckpost(w http.ResponseWriter, r *http.Request) {
 		if p.forwardCP(w, r, &msg, bucket, nil) {
 			return
 		}
+		if !p.checkQuorum(w, r, msg.Action) {
+			return
+		}
 		if err := p.createLocalBucket(&msg, bucket); err != nil {
 			p.invalmsghdlr(w, r, err.Error())
 		}
@@ -749,6 +797,9 @@ func (p *proxyrunner) httpbckpost(w http.ResponseWriter, r *http.Request) {
 		if p.forwardCP(w, r, &msg, "", nil) {
 			return
 		}
+		if !p.checkQuorum(w, r, msg.Action) {
+			return
+		}
 		bucketFrom, bucketTo := bucket, msg.Name
 		if bucketFrom == "" || bucketTo == "" {
 			errstr := fmt.Sprintf("Invalid rename local bucket request: empty name %q or %q", bucketFrom, bucketTo)
@@ -795,6 +846,12 @@ func (p *proxyrunner) httpbckpost(w http.ResponseWriter, r *http.Request) {
 		p.listBucketAndCollectStats(w, r, bucket, bckProvider, msg, started)
 	case cmn.ActEraseCopies:
 		p.eraseCopies(w, r, bucket, &msg, config)
+	case cmn.ActResync:
+		if bckIsLocal {
+			p.invalmsghdlr(w, r, fmt.Sprintf("Bucket %s appears to be local (not cloud)", bucket))
+			return
+		}
+		p.resyncBucket(w, r, bucket, bckProvider, &msg)
 	default:
 		s := fmt.Sprintf("Unexpected cmn.ActionMsg <- JSON [%v]", msg)
 		p.invalmsghdlr(w, r, s)
@@ -991,6 +1048,9 @@ func (p *proxyrunner) httpbckput(w http.ResponseWriter, r *http.Request) {
 		p.invalmsghdlr(w, r, errstr)
 		return
 	}
+	if !p.checkQuorum(w, r, cmn.ActSetProps) {
+		return
+	}
 	b, _, err := cmn.ReadBytes(r)
 	if err != nil {
 		return
@@ -1243,6 +1303,47 @@ func (p *proxyrunner) eraseCopies(w http.ResponseWriter, r *http.Request, bucket
 	}
 }
 
+// resyncBucket starts the resync xaction on all targets and returns the xaction IDs
+// by target ID - to query (and abort) via /v1/xactions
+func (p *proxyrunner) resyncBucket(w http.ResponseWriter, r *http.Request, bucket, bckProvider string, actionMsg *cmn.ActionMsg) {
+	smap := p.smapowner.get()
+	msgInt := p.newActionMsgInternal(actionMsg, smap, nil)
+	jsbytes, err := jsoniter.Marshal(msgInt)
+	cmn.AssertNoErr(err)
+	query := url.Values{}
+	query.Add(cmn.URLParamBckProvider, bckProvider)
+	results := p.broadcastTo(
+		cmn.URLPath(cmn.Version, cmn.Buckets, bucket),
+		query,
+		http.MethodPost,
+		jsbytes,
+		smap,
+		cmn.GCO.Get().Timeout.Default,
+		cmn.NetworkIntraControl,
+		cluster.Targets,
+	)
+	ids := make(map[string]int64, smap.CountTargets())
+	for res := range results {
+		if res.err != nil {
+			s := fmt.Sprintf("Failed to resync bucket %s, %s, err: %v(%d)", bucket, tname(res.si), res.err, res.status)
+			if res.errstr != "" {
+				glog.Errorln(res.errstr)
+			}
+			p.invalmsghdlr(w, r, s)
+			return
+		}
+		var id int64
+		if err := jsoniter.Unmarshal(res.outjson, &id); err != nil {
+			p.invalmsghdlr(w, r, fmt.Sprintf("Failed to unmarshal resync xaction ID from %s, err: %v", tname(res.si), err))
+			return
+		}
+		ids[res.si.DaemonID] = id
+	}
+	jsbytes, err = jsoniter.Marshal(ids)
+	cmn.AssertNoErr(err)
+	p.writeJSON(w, r, jsbytes, "resync")
+}
+
 func (p *proxyrunner) getbucketnames(w http.ResponseWriter, r *http.Request, bucketspec, bckProvider string) {
 	bucketmd := p.bmdowner.get()
 	bckProviderStr := "?" + cmn.URLParamBckProvider + "=" + bckProvider
@@ -1945,7 +2046,13 @@ func (p *proxyrunner) httpdaeput(w http.ResponseWriter, r *http.Request) {
 			glog.Infof("%s: %s v%d done", pname(p.si), cmn.SyncSmap, newsmap.version())
 			return
 		case cmn.ActSetConfig: // setconfig #1 - via query parameters and "?n1=v1&n2=v2..."
-			kvs := cmn.NewSimpleKVsFromQuery(r.URL.Query())
+			kvs, dryRun := setConfigQuery(r)
+			if dryRun {
+				if errstr := cmn.ValidateConfigMany(kvs); errstr != "" {
+					p.invalmsghdlr(w, r, errstr)
+				}
+				return
+			}
 			if errstr := cmn.SetConfigMany(kvs); errstr != "" {
 				p.invalmsghdlr(w, r, errstr)
 				return
@@ -1976,6 +2083,8 @@ func (p *proxyrunner) httpdaeput(w http.ResponseWriter, r *http.Request) {
 			p.invalmsghdlr(w, r, errstr)
 			return
 		}
+	case cmn.ActReloadConfig:
+		p.httpReloadConfig(w, r)
 	case cmn.ActShutdown:
 		q := r.URL.Query()
 		force, _ := parsebool(q.Get(cmn.URLParamForce))
@@ -2142,6 +2251,7 @@ func (p *proxyrunner) becomeNewPrimary(proxyidToRemove string) (errstr string) {
 		cmn.AssertMsg(false, "This proxy '"+p.si.DaemonID+"' must always be present in the local "+smap.pp())
 	}
 	clone := smap.clone()
+	prevPrimary := smap.ProxySI.DaemonID
 	// FIXME: may be premature at this point
 	if proxyidToRemove != "" {
 		glog.Infof("Removing failed primary proxy %s", proxyidToRemove)
@@ -2163,8 +2273,17 @@ func (p *proxyrunner) becomeNewPrimary(proxyidToRemove string) (errstr string) {
 		glog.Infof("Distributing Smap v%d with the newly elected primary %s(self)", clone.version(), pname(p.si))
 		glog.Infof("Distributing %s v%d as well", bmdTermName, bucketmd.version())
 	}
+	p.events.add(cmn.ClusterEvent{Time: time.Now(), Type: cmn.EventElection, Node: p.si.DaemonID,
+		Version: clone.version(), Msg: fmt.Sprintf("%s became primary, previous %s", p.si.DaemonID, prevPrimary)})
 	msgInt := p.newActionMsgInternalStr(cmn.ActNewPrimary, clone, nil)
-	p.metasyncer.sync(true, clone, msgInt, bucketmd, msgInt)
+	params := []interface{}{clone, msgInt, bucketmd, msgInt}
+	if jobs := p.jobs.get(); jobs.version() > 0 {
+		params = append(params, jobs, msgInt)
+	}
+	if conf := p.confowner.get(); conf.version() > 0 {
+		params = append(params, conf, msgInt)
+	}
+	p.metasyncer.sync(true, params...)
 	return
 }
 
@@ -2355,6 +2474,14 @@ func (p *proxyrunner) httpcluget(w http.ResponseWriter, r *http.Request) {
 		p.invokeHTTPGetXaction(w, r)
 	case cmn.GetWhatMountpaths:
 		p.invokeHTTPGetClusterMountpaths(w, r)
+	case cmn.GetWhatRebPreview:
+		p.httpRebPreview(w, r)
+	case cmn.GetWhatEvents:
+		p.httpGetClusterEvents(w, r)
+	case cmn.GetWhatJobs:
+		p.httpGetJobs(w, r)
+	case cmn.GetWhatClusterConf, cmn.GetWhatConfHistory, cmn.GetWhatConfDiff:
+		p.httpGetClusterConf(w, r, getWhat)
 	default:
 		s := fmt.Sprintf("Unexpected GET request, invalid param 'what': [%s]", getWhat)
 		cmn.InvalidHandlerWithMsg(w, r, s)
@@ -2506,6 +2633,10 @@ func (p *proxyrunner) httpclupost(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		return
 	}
+	if len(apitems) > 0 && apitems[0] == cmn.Events {
+		p.httpclupostEvent(w, r)
+		return
+	}
 	if cmn.ReadJSON(w, r, &nsi) != nil {
 		return
 	}
@@ -2533,6 +2664,9 @@ func (p *proxyrunner) httpclupost(w http.ResponseWriter, r *http.Request) {
 	if p.forwardCP(w, r, msg, s, body) {
 		return
 	}
+	if !keepalive && !p.checkQuorum(w, r, msg.Action) {
+		return
+	}
 	if net.ParseIP(nsi.PublicNet.NodeIPAddr) == nil {
 		s := fmt.Sprintf("register %s: invalid IP address %v", tname(&nsi), nsi.PublicNet.NodeIPAddr)
 		p.invalmsghdlr(w, r, s)
@@ -2551,6 +2685,11 @@ func (p *proxyrunner) httpclupost(w http.ResponseWriter, r *http.Request) {
 		}
 	} else {
 		osi := smap.GetTarget(nsi.DaemonID)
+		if errstr := smap.checkWeight(&nsi); errstr != "" {
+			p.smapowner.Unlock()
+			p.invalmsghdlr(w, r, "register: "+errstr)
+			return
+		}
 
 		// FIXME: If not keepalive then update smap anyway - either: new target,
 		// updated target or target has powercycled. Except 'updated target' case,
@@ -2609,7 +2748,9 @@ func (p *proxyrunner) httpclupost(w http.ResponseWriter, r *http.Request) {
 		// storage targets make use of msgInt.NewDaemonID,
 		// to figure out whether to rebalance the cluster, and how to execute the rebalancing
 		msgInt := p.newActionMsgInternal(msg, smap, nil)
-		msgInt.NewDaemonID = nsi.DaemonID
+		if smap.MaintenanceMode(nsi.DaemonID) != cmn.MaintenanceReboot { // back from reboot: no rebalancing
+			msgInt.NewDaemonID = nsi.DaemonID
+		}
 		msgInt.SmapVersion = smap.Version
 
 		// metasync
@@ -2621,6 +2762,12 @@ func (p *proxyrunner) httpclupost(w http.ResponseWriter, r *http.Request) {
 		if len(tokens.Tokens) > 0 {
 			params = append(params, tokens, msgInt)
 		}
+		if jobs := p.jobs.get(); isProxy && jobs.version() > 0 {
+			params = append(params, jobs, msgInt)
+		}
+		if conf := p.confowner.get(); conf.version() > 0 {
+			params = append(params, conf, msgInt)
+		}
 		p.metasyncer.sync(false, params...)
 	}(&nsi, isProxy, nonElectable)
 }
@@ -2644,10 +2791,14 @@ func (p *proxyrunner) registerToSmap(nsi *cluster.Snode, isproxy, nonelectable b
 			glog.Infof("joined %s (num proxies %d)", pname(nsi), clone.CountProxies())
 		}
 	} else {
+		// NOTE: maintenance mode and weight set by the operator survive re-registration (e.g., after reboot)
+		mode, weight := clone.MaintenanceMode(id), clone.Weights[id]
 		if clone.GetTarget(id) != nil { // ditto
 			clone.delTarget(id)
 		}
 		clone.addTarget(nsi)
+		clone.setMaintenance(id, mode)
+		clone.setWeight(id, weight)
 		if glog.V(3) {
 			glog.Infof("joined %s (num targets %d)", tname(nsi), clone.CountTargets())
 		}
@@ -2709,6 +2860,9 @@ func (p *proxyrunner) httpcludel(w http.ResponseWriter, r *http.Request) {
 	if p.forwardCP(w, r, msg, sid, nil) {
 		return
 	}
+	if !p.checkUnregQuorum(w, r, msg.Action, sid) {
+		return
+	}
 
 	p.smapowner.Lock()
 
@@ -2790,29 +2944,17 @@ func (p *proxyrunner) httpcluput(w http.ResponseWriter, r *http.Request) {
 			p.httpclusetprimaryproxy(w, r)
 			return
 		case cmn.ActSetConfig: // setconfig #1 - via query parameters and "?n1=v1&n2=v2..."
-			query := r.URL.Query()
-			kvs := cmn.NewSimpleKVsFromQuery(query)
-			if errstr := cmn.SetConfigMany(kvs); errstr != "" {
-				p.invalmsghdlr(w, r, errstr)
+			msg = cmn.ActionMsg{Action: cmn.ActSetConfig}
+			if p.forwardCP(w, r, &msg, "", nil) {
 				return
 			}
-			results := p.broadcastTo(
-				cmn.URLPath(cmn.Version, cmn.Daemon, cmn.ActSetConfig),
-				query,
-				http.MethodPut,
-				nil,
-				p.smapowner.get(),
-				defaultTimeout,
-				cmn.NetworkIntraControl,
-				cluster.AllNodes,
-			)
-			for result := range results {
-				if result.err != nil {
-					p.invalmsghdlr(w, r, result.err.Error())
-					p.keepalive.onerr(err, result.status)
-					return
-				}
+			query := r.URL.Query()
+			node := query.Get(cmn.URLParamNode)
+			query.Del(cmn.URLParamNode)
+			if !p.validateConfNode(w, r, node) {
+				return
 			}
+			p.setClusterConf(w, r, &msg, node, cmn.NewSimpleKVsFromQuery(query))
 			return
 		}
 	}
@@ -2833,36 +2975,15 @@ func (p *proxyrunner) httpcluput(w http.ResponseWriter, r *http.Request) {
 			p.invalmsghdlr(w, r, fmt.Sprintf("%s: invalid value format (%+v, %T)", cmn.ActSetConfig, msg.Value, msg.Value))
 			return
 		}
-		kvs := cmn.NewSimpleKVs(cmn.SimpleKVsEntry{Key: msg.Name, Value: value})
-		if errstr := cmn.SetConfigMany(kvs); errstr != "" {
-			p.invalmsghdlr(w, r, errstr)
+		node := r.URL.Query().Get(cmn.URLParamNode)
+		if !p.validateConfNode(w, r, node) {
 			return
 		}
-
-		msgbytes, err := jsoniter.Marshal(msg) // same message -> all targets and proxies
-		cmn.AssertNoErr(err)
-
-		results := p.broadcastTo(
-			cmn.URLPath(cmn.Version, cmn.Daemon),
-			nil, // query
-			http.MethodPut,
-			msgbytes,
-			p.smapowner.get(),
-			defaultTimeout,
-			cmn.NetworkIntraControl,
-			cluster.AllNodes,
-		)
-		for result := range results {
-			if result.err != nil {
-				p.invalmsghdlr(
-					w,
-					r,
-					fmt.Sprintf("%s: (%s = %s) failed, err: %s", msg.Action, msg.Name, value, result.errstr),
-				)
-				p.keepalive.onerr(err, result.status)
-				return
-			}
-		}
+		p.setClusterConf(w, r, &msg, node, cmn.NewSimpleKVs(cmn.SimpleKVsEntry{Key: msg.Name, Value: value}))
+	case cmn.ActUnsetConfig, cmn.ActRollbackConfig:
+		p.changeClusterConf(w, r, &msg, r.URL.Query().Get(cmn.URLParamNode))
+	case cmn.ActReloadConfig:
+		p.reloadClusterConfig(w, r, &msg)
 
 	case cmn.ActShutdown:
 		glog.Infoln("Proxy-controlled cluster shutdown...")
@@ -2888,12 +3009,140 @@ func (p *proxyrunner) httpcluput(w http.ResponseWriter, r *http.Request) {
 		msgInt := p.newActionMsgInternal(&msg, smap, nil)
 		p.metasyncer.sync(false, smap, msgInt)
 
+	case cmn.ActStartMaint, cmn.ActStopMaint, cmn.ActDecommission:
+		if p.checkQuorum(w, r, msg.Action) {
+			p.targetMaintenance(w, r, &msg)
+		}
+
+	case cmn.ActSetWeight:
+		if p.checkQuorum(w, r, msg.Action) {
+			p.targetWeight(w, r, &msg)
+		}
+
+	case cmn.ActAddJob, cmn.ActRemoveJob:
+		if p.checkQuorum(w, r, msg.Action) {
+			p.updateJobs(w, r, &msg)
+		}
+
 	default:
 		s := fmt.Sprintf("Unexpected cmn.ActionMsg <- JSON [%v]", msg)
 		p.invalmsghdlr(w, r, s)
 	}
 }
 
+// targetMaintenance puts the target (msg.Name) in maintenance or takes it out of it:
+// - ActStartMaint: maintenance for reboot - the target stays in the cluster map
+//   (and keeps its HRW placement) while being briefly down;
+// - ActDecommission: the target accepts no new placements but keeps serving reads while
+//   migrating its content to the new HRW owners; it leaves the cluster map upon completion;
+// - ActStopMaint: cancels either of the above.
+func (p *proxyrunner) targetMaintenance(w http.ResponseWriter, r *http.Request, msg *cmn.ActionMsg) {
+	var (
+		sid  = msg.Name
+		mode string
+	)
+	switch msg.Action {
+	case cmn.ActStartMaint:
+		mode = cmn.MaintenanceReboot
+	case cmn.ActDecommission:
+		mode = cmn.MaintenanceDecommission
+	}
+	p.smapowner.Lock()
+	smap := p.smapowner.get()
+	if smap.GetTarget(sid) == nil {
+		p.smapowner.Unlock()
+		p.invalmsghdlr(w, r, fmt.Sprintf("%s: unknown target %q", msg.Action, sid), http.StatusNotFound)
+		return
+	}
+	prev := smap.MaintenanceMode(sid)
+	if prev == mode {
+		p.smapowner.Unlock()
+		return
+	}
+	if mode == cmn.MaintenanceDecommission && smap.CountPlacementTargets() < 2 {
+		p.smapowner.Unlock()
+		p.invalmsghdlr(w, r, fmt.Sprintf("%s: cannot decommission the last target %s", msg.Action, sid))
+		return
+	}
+	clone := smap.clone()
+	clone.setMaintenance(sid, mode)
+	clone.Version++
+	if errstr := p.smapowner.persist(clone, true); errstr != "" {
+		p.smapowner.Unlock()
+		p.invalmsghdlr(w, r, errstr)
+		return
+	}
+	p.smapowner.put(clone)
+	p.smapowner.Unlock()
+	glog.Infof("%s %s: maintenance %q => %q, Smap v%d", msg.Action, tname(clone.GetTarget(sid)), prev, mode, clone.version())
+
+	// targets make use of msgInt.NewDaemonID to figure out whether to rebalance:
+	// decommission (or its cancellation) changes HRW placement, reboot does not
+	msgInt := p.newActionMsgInternal(msg, clone, nil)
+	if mode == cmn.MaintenanceDecommission || prev == cmn.MaintenanceDecommission {
+		msgInt.NewDaemonID = sid
+	}
+	msgInt.SmapVersion = clone.Version
+	p.metasyncer.sync(true, clone, msgInt)
+}
+
+// targetWeight sets the target's (msg.Name) placement weight (msg.Value), which
+// overrides the one declared by the target itself; zero weight removes the override.
+// With the targets declaring their capacities, the weight is in GiB as well.
+// The change of weights triggers (proportional) global rebalance.
+func (p *proxyrunner) targetWeight(w http.ResponseWriter, r *http.Request, msg *cmn.ActionMsg) {
+	var (
+		sid    = msg.Name
+		weight int64
+		err    error
+	)
+	switch v := msg.Value.(type) {
+	case string:
+		weight, err = strconv.ParseInt(v, 10, 64)
+	case float64:
+		weight = int64(v)
+	default:
+		err = fmt.Errorf("unexpected value type %T", v)
+	}
+	if err != nil || weight < 0 {
+		p.invalmsghdlr(w, r, fmt.Sprintf("%s: invalid weight %v, err: %v", msg.Action, msg.Value, err))
+		return
+	}
+	p.smapowner.Lock()
+	smap := p.smapowner.get()
+	if smap.GetTarget(sid) == nil {
+		p.smapowner.Unlock()
+		p.invalmsghdlr(w, r, fmt.Sprintf("%s: unknown target %q", msg.Action, sid), http.StatusNotFound)
+		return
+	}
+	prev := smap.Weight(sid)
+	clone := smap.clone()
+	clone.setWeight(sid, weight)
+	if clone.Weight(sid) == prev && clone.Weights[sid] == smap.Weights[sid] {
+		p.smapowner.Unlock()
+		return
+	}
+	clone.Version++
+	if errstr := p.smapowner.persist(clone, true); errstr != "" {
+		p.smapowner.Unlock()
+		p.invalmsghdlr(w, r, errstr)
+		return
+	}
+	p.smapowner.put(clone)
+	p.smapowner.Unlock()
+	glog.Infof("%s %s: weight %d => %d, Smap v%d", msg.Action, tname(clone.GetTarget(sid)), prev, clone.Weight(sid), clone.version())
+	if weight > 0 && clone.GetTarget(sid).WeightCap {
+		glog.Warningf("%s %s: the targets declare their capacities - taking %d as GiB", msg.Action, sid, weight)
+	}
+
+	msgInt := p.newActionMsgInternal(msg, clone, nil)
+	if clone.Weight(sid) != prev {
+		msgInt.NewDaemonID = sid // (rebalance)
+	}
+	msgInt.SmapVersion = clone.Version
+	p.metasyncer.sync(true, clone, msgInt)
+}
+
 //========================
 //
 // broadcasts: Rx and Tx
@@ -3025,7 +3274,7 @@ func (p *proxyrunner) validateBucketProps(props *cmn.BucketProps, isLocal bool)
 
 		// data slices + parity slices + original object
 		required := props.EC.DataSlices + props.EC.ParitySlices + 1
-		targetCnt := len(p.smapowner.get().Tmap)
+		targetCnt := p.smapowner.get().CountPlacementTargets()
 		if targetCnt < required {
 			return fmt.Errorf(
 				"it requires %d targets to use %d data and %d parity slices "+
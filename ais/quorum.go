@@ -0,0 +1,226 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+//
+// Split-brain protection
+//
+// 1) A candidate wins the election only if it collects YES votes from the
+//    majority of the last known set of proxies (the failed primary included),
+//    see countVotes.
+// 2) The primary must reach the majority of proxies to modify cluster-wide
+//    metadata: upon each keepalive round the primary that cannot reach the
+//    majority goes into the minority mode, in which it refuses Smap and BMD
+//    changes (and keeps serving data), see updateQuorum and checkQuorum.
+// 3) When the connectivity gets restored the primary that has been in the
+//    minority looks for a newer primary elected in the meantime and steps down
+//    if there's one, adopting its Smap and BMD (see resolveSplitBrain). The
+//    same applies to the primary that receives a newer Smap from another primary
+//    via metasync.
+//
+
+// quorum returns the minimum number of proxies that constitutes a majority
+func quorum(nproxies int) int { return nproxies/2 + 1 }
+
+func (p *proxyrunner) inMinority() bool { return atomic.LoadInt32(&p.minority) != 0 }
+
+// checkQuorum fails the request that modifies cluster-wide metadata when the
+// primary is in the minority; returns false if the request must not proceed
+func (p *proxyrunner) checkQuorum(w http.ResponseWriter, r *http.Request, action string) bool {
+	if !p.inMinority() {
+		return true
+	}
+	s := fmt.Sprintf("%s: cannot %s - no quorum (the primary is cut off from the majority of %d proxies)",
+		pname(p.si), action, p.smapowner.get().CountProxies())
+	p.invalmsghdlr(w, r, s, http.StatusServiceUnavailable)
+	return false
+}
+
+// checkUnregQuorum is checkQuorum for unregistering a node: in the minority, the
+// primary still removes the proxy that does not respond (confirmed by ping) if
+// the request is forced (frc=true) - otherwise, e.g., the primary of a two-proxy
+// cluster that has lost the other proxy would remain in the minority forever
+func (p *proxyrunner) checkUnregQuorum(w http.ResponseWriter, r *http.Request, action, sid string) bool {
+	if !p.inMinority() {
+		return true
+	}
+	force, _ := parsebool(r.URL.Query().Get(cmn.URLParamForce))
+	psi := p.smapowner.get().GetProxy(sid)
+	if !force || action != cmn.ActUnregProxy || psi == nil {
+		return p.checkQuorum(w, r, action)
+	}
+	if alive, _ := p.pingWithTimeout(psi, cmn.GCO.Get().Timeout.CplaneOperation); alive {
+		s := fmt.Sprintf("%s: cannot %s %s - no quorum and the proxy is alive", pname(p.si), action, pname(psi))
+		p.invalmsghdlr(w, r, s, http.StatusServiceUnavailable)
+		return false
+	}
+	glog.Warningf("%s: no quorum - forcefully removing %s that does not respond", pname(p.si), pname(psi))
+	return true
+}
+
+// updateQuorum is called by the primary upon each keepalive round with the
+// number of live proxies (self included); returns true if the primary must
+// not modify the Smap - e.g., remove non-responding nodes - this time
+func (p *proxyrunner) updateQuorum(alive int) (readonly bool) {
+	if p.inMinority() && p.resolveSplitBrain() {
+		return true // stepped down
+	}
+	nproxies := p.smapowner.get().CountProxies()
+	if alive < quorum(nproxies) {
+		if atomic.CompareAndSwapInt32(&p.minority, 0, 1) {
+			glog.Errorf("%s: lost quorum (%d/%d proxies alive): refusing metadata changes",
+				pname(p.si), alive, nproxies)
+		}
+		return true
+	}
+	if atomic.CompareAndSwapInt32(&p.minority, 1, 0) {
+		glog.Infof("%s: regained quorum (%d/%d proxies alive)", pname(p.si), alive, nproxies)
+	}
+	return false
+}
+
+// resolveSplitBrain looks for a primary elected while this one was cut off:
+// the newest Smap that names a different primary wins over the local one, and
+// so does the BMD of that primary. Returns true if this proxy has stepped down.
+func (p *proxyrunner) resolveSplitBrain() bool {
+	var (
+		newer *smapX
+		smap  = p.smapowner.get()
+		q     = url.Values{}
+	)
+	q.Set(cmn.URLParamWhat, cmn.GetWhatSmapVote)
+	res := p.broadcastTo(
+		cmn.URLPath(cmn.Version, cmn.Daemon),
+		q,
+		http.MethodGet,
+		nil, // body
+		smap,
+		cmn.GCO.Get().Timeout.CplaneOperation,
+		cmn.NetworkIntraControl,
+		cluster.AllNodes,
+	)
+	for re := range res {
+		if re.err != nil {
+			continue
+		}
+		svm := SmapVoteMsg{}
+		if err := jsoniter.Unmarshal(re.outjson, &svm); err != nil || svm.VoteInProgress {
+			continue
+		}
+		if svm.Smap == nil || !svm.Smap.isValid() || svm.Smap.isPrimary(p.si) {
+			continue
+		}
+		if svm.Smap.version() > smap.version() && (newer == nil || svm.Smap.version() > newer.version()) {
+			newer = svm.Smap
+		}
+	}
+	if newer == nil {
+		return false
+	}
+	// the new primary's (current) Smap and BMD
+	svm, err := p.smapVoteFrom(newer.ProxySI)
+	if err != nil {
+		glog.Errorln(err)
+		return false
+	}
+	if svm.Smap == nil || !svm.Smap.isValid() || svm.Smap.version() < newer.version() {
+		svm.Smap = newer
+	}
+	return p.stepDown(svm.Smap, svm.BucketMD)
+}
+
+func (p *proxyrunner) smapVoteFrom(si *cluster.Snode) (*SmapVoteMsg, error) {
+	q := url.Values{}
+	q.Set(cmn.URLParamWhat, cmn.GetWhatSmapVote)
+	args := callArgs{
+		si: si,
+		req: reqArgs{
+			method: http.MethodGet,
+			base:   si.IntraControlNet.DirectURL,
+			path:   cmn.URLPath(cmn.Version, cmn.Daemon),
+			query:  q,
+		},
+		timeout: cmn.GCO.Get().Timeout.CplaneOperation,
+	}
+	res := p.call(args)
+	if res.err != nil {
+		return nil, fmt.Errorf("%s: failed to get cluster metadata from %s, err: %v", pname(p.si), pname(si), res.err)
+	}
+	svm := &SmapVoteMsg{}
+	if err := jsoniter.Unmarshal(res.outjson, svm); err != nil {
+		return nil, fmt.Errorf("%s: failed to unmarshal cluster metadata from %s, err: %v", pname(p.si), pname(si), err)
+	}
+	return svm, nil
+}
+
+// stepDown makes the primary yield to the newer one: the latter's Smap and BMD
+// replace the local ones, the BMD - regardless of its version
+func (p *proxyrunner) stepDown(newsmap *smapX, newbmd *bucketMD) bool {
+	smap := p.smapowner.get()
+	glog.Errorf("%s: split-brain (local Smap v%d, primary=%s) vs (Smap v%d, primary=%s): stepping down",
+		pname(p.si), smap.version(), smap.ProxySI.DaemonID, newsmap.version(), newsmap.ProxySI.DaemonID)
+	p.smapowner.Lock()
+	if errstr := p.smapowner.persist(newsmap, true /*saveSmap*/); errstr != "" {
+		p.smapowner.Unlock()
+		glog.Errorln(errstr)
+		return false
+	}
+	p.smapowner.put(newsmap)
+	p.smapowner.Unlock()
+	atomic.StoreInt32(&p.minority, 0)
+
+	if newbmd == nil {
+		return true
+	}
+	p.bmdowner.Lock()
+	if bmd := p.bmdowner.get(); bmd.version() > newbmd.version() {
+		glog.Errorf("%s: discarding local %s v%d in favor of v%d from the primary %s",
+			pname(p.si), bmdTermName, bmd.version(), newbmd.version(), newsmap.ProxySI.DaemonID)
+	}
+	if errstr := p.savebmdconf(newbmd, cmn.GCO.Get()); errstr != "" {
+		glog.Errorln(errstr)
+	}
+	p.bmdowner.put(newbmd)
+	p.bmdowner.Unlock()
+	return true
+}
+
+// yield is called when the primary receives metasync: the Smap naming another
+// primary with a newer version means that the latter got elected while this
+// one was cut off
+func (p *proxyrunner) yield(payload cmn.SimpleKVs) bool {
+	newsmap, _, errstr := p.extractSmap(payload)
+	if errstr != "" || newsmap == nil || newsmap.isPrimary(p.si) {
+		return false
+	}
+	var newbmd *bucketMD
+	if bmdvalue, ok := payload[bucketmdtag]; ok {
+		newbmd = &bucketMD{}
+		if err := jsoniter.Unmarshal([]byte(bmdvalue), newbmd); err != nil {
+			glog.Errorf("Failed to unmarshal new %s, err: %v", bmdTermName, err)
+			return false
+		}
+	} else {
+		svm, err := p.smapVoteFrom(newsmap.ProxySI)
+		if err != nil {
+			glog.Errorln(err)
+			return false
+		}
+		newbmd = svm.BucketMD
+	}
+	return p.stepDown(newsmap, newbmd)
+}
@@ -0,0 +1,240 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+type (
+	// quorumPeer is an in-process proxy; the one that is cut off (partitioned)
+	// does not listen
+	quorumPeer struct {
+		id     string
+		cutOff bool
+		svm    *SmapVoteMsg // GET /v1/daemon?what=smapvote response (nil - the caller's own Smap)
+	}
+	statsQuorumMock struct{}
+)
+
+func (s *statsQuorumMock) Add(name string, val int64)             {}
+func (s *statsQuorumMock) AddErrorHTTP(method string, val int64)  {}
+func (s *statsQuorumMock) AddMany(namedVal64 ...stats.NamedVal64) {}
+func (s *statsQuorumMock) Register(name string, kind string)      {}
+
+// newPartitionedProxy returns the proxy (self) that has the peers in its Smap,
+// and the function to stop the peers
+func newPartitionedProxy(peers []*quorumPeer) (*proxyrunner, map[string]*cluster.Snode, func()) {
+	var (
+		p       = newPrimary()
+		smap    = p.smapowner.get()
+		servers = make([]*httptest.Server, 0, len(peers))
+		nodes   = make(map[string]*cluster.Snode, len(peers))
+	)
+	p.httpclient = &http.Client{}
+	p.statsif = &statsQuorumMock{}
+	config := cmn.GCO.BeginUpdate()
+	config.Timeout.CplaneOperation = time.Second
+	cmn.GCO.CommitUpdate(config)
+
+	for _, peer := range peers {
+		peer := peer
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			svm := peer.svm
+			if svm == nil {
+				svm = &SmapVoteMsg{Smap: smap, BucketMD: p.bmdowner.get()}
+			}
+			b, _ := json.Marshal(svm)
+			w.Write(b)
+		}))
+		si := newSnode(peer.id, httpProto, serverTCPAddr(ts.URL), &net.TCPAddr{}, &net.TCPAddr{})
+		if peer.cutOff {
+			ts.Close()
+		} else {
+			servers = append(servers, ts)
+		}
+		smap.addProxy(si)
+		nodes[peer.id] = si
+	}
+	return p, nodes, func() {
+		for _, ts := range servers {
+			ts.Close()
+		}
+	}
+}
+
+func TestElectionQuorum(t *testing.T) {
+	// the candidate and the (failed) primary p0, among 5 proxies
+	smap := newSmap()
+	for _, id := range []string{"candidate", "p0", "p1", "p2", "p3"} {
+		smap.addProxy(newSnode(id, httpProto, &net.TCPAddr{}, &net.TCPAddr{}, &net.TCPAddr{}))
+	}
+	smap.addTarget(newSnode("t1", httpProto, &net.TCPAddr{}, &net.TCPAddr{}, &net.TCPAddr{}))
+	smap.ProxySI = smap.GetProxy("p0")
+	vr := &VoteRecord{Candidate: "candidate", Primary: "p0"}
+
+	cutOff := errors.New("connection refused")
+	tcs := []struct {
+		name   string
+		votes  []voteResult
+		winner bool
+	}{
+		{
+			"majority",
+			[]voteResult{
+				{daemonID: "p0", err: cutOff},
+				{daemonID: "p1", yes: true},
+				{daemonID: "p2", yes: true},
+				{daemonID: "p3", err: cutOff},
+				{daemonID: "t1", yes: true},
+			},
+			true,
+		},
+		{
+			"minority partition",
+			[]voteResult{
+				{daemonID: "p0", err: cutOff},
+				{daemonID: "p1", yes: true},
+				{daemonID: "p2", err: cutOff},
+				{daemonID: "p3", err: cutOff},
+				{daemonID: "t1", yes: true},
+			},
+			false,
+		},
+		{
+			"targets do not make quorum",
+			[]voteResult{
+				{daemonID: "p0", err: cutOff},
+				{daemonID: "p1", yes: true},
+				{daemonID: "p2", yes: false},
+				{daemonID: "p3", err: cutOff},
+				{daemonID: "t1", yes: true},
+			},
+			false,
+		},
+		{
+			"no votes",
+			[]voteResult{},
+			false,
+		},
+	}
+	for _, tc := range tcs {
+		resch := make(chan voteResult, len(tc.votes))
+		for _, v := range tc.votes {
+			resch <- v
+		}
+		close(resch)
+		if winner, _ := countVotes(vr, smap, resch); winner != tc.winner {
+			t.Errorf("test case %q: expecting winner=%t, got %t", tc.name, tc.winner, winner)
+		}
+	}
+}
+
+func TestSplitBrainResolution(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quorum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	origConfigFile := cmn.GCO.GetConfigFile()
+	cmn.GCO.SetConfigFile(filepath.Join(dir, "ais.json"))
+	defer cmn.GCO.SetConfigFile(origConfigFile)
+	config := cmn.GCO.BeginUpdate()
+	config.Confdir = dir
+	cmn.GCO.CommitUpdate(config)
+
+	// the primary gets cut off from both of the other proxies
+	peers := []*quorumPeer{{id: "p1", cutOff: true}, {id: "p2", cutOff: true}}
+	p, _, stop := newPartitionedProxy(peers)
+	stop()
+	p.smapowner.get().Version = 10
+	p.bmdowner.get().Version = 5
+	if readonly := p.updateQuorum(1); !readonly || !p.inMinority() {
+		t.Fatalf("expecting the primary to go into the minority mode")
+	}
+
+	// meanwhile, the majority elects p2 (and removes the old primary), and the connectivity gets restored
+	peers = []*quorumPeer{{id: "p1"}, {id: "p2"}}
+	p, nodes, stop := newPartitionedProxy(peers)
+	defer stop()
+	p.smapowner.get().Version = 10
+	p.bmdowner.get().Version = 5
+	p.minority = 1
+	newsmap := newSmap()
+	newsmap.addProxy(nodes["p1"])
+	newsmap.addProxy(nodes["p2"])
+	newsmap.ProxySI = nodes["p2"]
+	newsmap.Version = 110
+	newbmd := newBucketMD()
+	newbmd.Version = 3
+	peers[1].svm = &SmapVoteMsg{Smap: newsmap, BucketMD: newbmd}
+
+	if readonly := p.updateQuorum(3); !readonly {
+		t.Fatalf("expecting the primary to step down")
+	}
+	if p.inMinority() {
+		t.Errorf("expecting the minority mode to be reset")
+	}
+	smap := p.smapowner.get()
+	if smap.isPrimary(p.si) || smap.ProxySI.DaemonID != "p2" || smap.version() != 110 {
+		t.Errorf("expecting Smap v110 with the primary p2, got v%d with the primary %s", smap.version(), smap.ProxySI.DaemonID)
+	}
+	if v := p.bmdowner.get().version(); v != 3 {
+		t.Errorf("expecting the new primary's %s v3 to win over the local one, got v%d", bmdTermName, v)
+	}
+}
+
+func TestForcedUnregQuorum(t *testing.T) {
+	// two-proxy cluster: the primary loses the other proxy for good
+	peers := []*quorumPeer{{id: "p1", cutOff: true}}
+	p, _, stop := newPartitionedProxy(peers)
+	defer stop()
+	if readonly := p.updateQuorum(1); !readonly || !p.inMinority() {
+		t.Fatalf("expecting the primary to go into the minority mode")
+	}
+	tcs := []struct {
+		name  string
+		query string
+		ok    bool
+	}{
+		{"not forced", "", false},
+		{"forced", "?" + cmn.URLParamForce + "=true", true},
+	}
+	for _, tc := range tcs {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodDelete, "/v1/cluster/daemon/proxy/p1"+tc.query, nil)
+		if ok := p.checkUnregQuorum(w, r, cmn.ActUnregProxy, "p1"); ok != tc.ok {
+			t.Errorf("test case %q: expecting %t, got %t (status %d)", tc.name, tc.ok, ok, w.Code)
+		}
+	}
+	// with the dead proxy removed the primary regains the quorum
+	p.smapowner.get().delProxy("p1")
+	if readonly := p.updateQuorum(1); readonly || p.inMinority() {
+		t.Errorf("expecting the primary to regain the quorum")
+	}
+
+	// the proxy that responds is not removed even if forced
+	peers = []*quorumPeer{{id: "p1"}, {id: "p2", cutOff: true}, {id: "p3", cutOff: true}}
+	p, _, stop = newPartitionedProxy(peers)
+	defer stop()
+	p.minority = 1
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodDelete, "/v1/cluster/daemon/proxy/p1?"+cmn.URLParamForce+"=true", nil)
+	if p.checkUnregQuorum(w, r, cmn.ActUnregProxy, "p1") || w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expecting the live proxy not to be removed, status %d", w.Code)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -22,7 +23,9 @@ import (
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/governor"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/transport"
@@ -44,6 +47,8 @@ type (
 		wg           *sync.WaitGroup
 		objectsMoved int64
 		bytesMoved   int64
+		cp           *rebJoggerCkpt
+		mpathInfo    *fs.MountpathInfo // (throttling)
 	}
 
 	globalRebJogger struct {
@@ -51,6 +56,11 @@ type (
 		smap *smapX // cluster.Smap?
 	}
 
+	ecRebJogger struct {
+		rebJoggerBase
+		smap *smapX
+	}
+
 	localRebJogger struct {
 		rebJoggerBase
 		slab *memsys.Slab2
@@ -60,6 +70,7 @@ type (
 	rebManager struct {
 		t       *targetrunner
 		streams *transport.StreamBundle
+		limiter *transport.Limiter // rebalance bandwidth (config.Rebalance.Bandwidth)
 	}
 )
 
@@ -105,6 +116,17 @@ func (reb *rebManager) recvRebalanceObj(w http.ResponseWriter, hdr transport.Hea
 		glog.Error(err)
 		return
 	}
+	// EC: regenerate slices and metadata in accordance with the new placement
+	if lom := roi.lom; lom.BckProps != nil && lom.BckProps.EC.Enabled {
+		if sliceFQN, errstr := cluster.FQN(ec.SliceType, lom.Bucket, lom.Objname, lom.BckIsLocal); errstr == "" {
+			if err := os.Remove(sliceFQN); err != nil && !os.IsNotExist(err) {
+				glog.Errorf("failed to remove stale slice %s: %v", sliceFQN, err)
+			}
+		}
+		if err := reb.t.ecmanager.EncodeObject(lom); err != nil && err != ec.ErrorECDisabled {
+			glog.Errorf("failed to re-encode %s: %v", lom, err)
+		}
+	}
 
 	reb.t.statsif.AddMany(stats.NamedVal64{stats.RxCount, 1}, stats.NamedVal64{stats.RxSize, hdr.ObjAttrs.Size})
 }
@@ -114,7 +136,7 @@ func (reb *rebManager) recvRebalanceObj(w http.ResponseWriter, hdr transport.Hea
 //
 
 func (rj *globalRebJogger) jog() {
-	if err := filepath.Walk(rj.mpath, rj.walk); err != nil {
+	if err := filepath.Walk(rj.mpath, rj.resumable(rj.walk)); err != nil {
 		s := err.Error()
 		if strings.Contains(s, "xaction") {
 			glog.Infof("Stopping %s traversal due to: %s", rj.mpath, s)
@@ -130,17 +152,66 @@ func (rj *globalRebJogger) jog() {
 func (rj *globalRebJogger) rebalanceObjCallback(hdr transport.Header, r io.ReadCloser, err error) {
 	uname := cluster.Uname(hdr.Bucket, hdr.Objname)
 	rj.t.rtnamemap.Unlock(uname, false)
+	rj.sent(hdr, err)
+}
 
+// ecMainCallback is rebalanceObjCallback for the EC-ed object whose main target
+// has changed: the new main target re-encodes the object upon receiving (see
+// recvRebalanceObj), and the old one drops the object's metafile - but only
+// once the object gets delivered
+func (rj *globalRebJogger) ecMainCallback(metaFQN string) transport.SendCallback {
+	return func(hdr transport.Header, r io.ReadCloser, err error) {
+		uname := cluster.Uname(hdr.Bucket, hdr.Objname)
+		rj.t.rtnamemap.Unlock(uname, false)
+		if err == nil {
+			rj.drop(hdr.Bucket, hdr.Objname, metaFQN, "")
+		}
+		rj.sent(hdr, err)
+	}
+}
+
+func (rj *globalRebJogger) sent(hdr transport.Header, err error) {
 	if err != nil {
 		glog.Errorf("failed to send obj rebalance: %s/%s, err: %v", hdr.Bucket, hdr.Objname, err)
+		rj.xreb.AddErr(err)
 	} else {
 		atomic.AddInt64(&rj.objectsMoved, 1)
 		atomic.AddInt64(&rj.bytesMoved, hdr.ObjAttrs.Size)
+		rj.xreb.ObjectsAdd(1)
+		rj.xreb.BytesAdd(hdr.ObjAttrs.Size)
 	}
 
+	rj.cp.pending.Done()
 	rj.wg.Done()
 }
 
+// throttle pauses the jogger for the time allotted to rebalance by the IO governor,
+// and then while its mountpath's disk utilization exceeds the configured maximum
+// (config.Rebalance.DiskUtilMax) - to give way to user GETs
+func (rj *rebJoggerBase) throttle() {
+	if rj.mpathInfo == nil {
+		if rj.mpathInfo, _ = fs.Mountpaths.Path2MpathInfo(rj.mpath); rj.mpathInfo == nil {
+			return
+		}
+	}
+	governor.Gov.Throttle(rj.mpathInfo.Path, governor.PrioHigh)
+	max := cmn.GCO.Get().Rebalance.DiskUtilMax
+	if max == 0 {
+		return
+	}
+	for !rj.xreb.Aborted() {
+		if _, curr := rj.mpathInfo.GetIOstats(fs.StatDiskUtil); int64(curr.Max) <= max {
+			return
+		}
+		rj.t.statsif.Add(stats.RebThrottleDiskCount, 1)
+		time.Sleep(cmn.ThrottleSleepAvg)
+		max = cmn.GCO.Get().Rebalance.DiskUtilMax // (adjustable at runtime)
+		if max == 0 {
+			return
+		}
+	}
+}
+
 // the walking callback is executed by the LRU xaction
 func (rj *globalRebJogger) walk(fqn string, fi os.FileInfo, err error) error {
 	var (
@@ -160,6 +231,7 @@ func (rj *globalRebJogger) walk(fqn string, fi os.FileInfo, err error) error {
 	if fi.Mode().IsDir() {
 		return nil
 	}
+	rj.throttle()
 	lom := &cluster.LOM{T: rj.t, FQN: fqn}
 	if errstr := lom.Fill("", 0); errstr != "" {
 		if glog.V(4) {
@@ -176,6 +248,10 @@ func (rj *globalRebJogger) walk(fqn string, fi os.FileInfo, err error) error {
 	if si.DaemonID == rj.t.si.DaemonID {
 		return nil
 	}
+	metaFQN, replica := rj.ecMeta(lom)
+	if replica {
+		return nil
+	}
 	// do rebalance
 	if glog.V(4) {
 		glog.Infof("%s %s => %s", lom, tname(rj.t.si), tname(si))
@@ -212,23 +288,286 @@ func (rj *globalRebJogger) walk(fqn string, fi os.FileInfo, err error) error {
 		},
 	}
 
-	rj.wg.Add(1) // NOTE: Done happens in case of SendV error or in rebalanceObjCallback.
-	if err := rj.t.rebManager.streams.SendV(hdr, file, rj.rebalanceObjCallback, si); err != nil {
+	cb := rj.rebalanceObjCallback
+	if metaFQN != "" {
+		cb = rj.ecMainCallback(metaFQN)
+	}
+	rj.wg.Add(1) // NOTE: Done happens in case of SendV error or in the callback.
+	rj.cp.pending.Add(1)
+	rj.xreb.TotalAdd(1)
+	if err := rj.t.rebManager.streams.SendV(hdr, file, cb, si); err != nil {
 		glog.Errorf("failed to rebalance: %s, err: %v", lom.FQN, err)
 		rj.t.rtnamemap.Unlock(lom.Uname, false)
+		rj.cp.pending.Done()
 		rj.wg.Done()
 		return err
 	}
 	return nil
 }
 
+// returns the metafile of the EC-ed object, if any, and whether the object is
+// an EC replica (as per the placement recorded in the metafile): replicas are
+// rebalanced by ecRebJogger; only the main object moves here
+func (rj *globalRebJogger) ecMeta(lom *cluster.LOM) (metaFQN string, replica bool) {
+	metaFQN = fs.CSM.GenContentFQN(lom.FQN, ec.MetaType, "")
+	meta, err := ec.LoadMetadata(metaFQN)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			glog.Error(err)
+		}
+		return "", false
+	}
+	if len(meta.Targets) > 0 && meta.Targets[0] != rj.t.si.DaemonID {
+		return "", true
+	}
+	return metaFQN, false
+}
+
+//
+// EC REBALANCE
+//
+// Walks metafiles - the metafile defines the local piece of an EC-ed object
+// (replica or slice) and records the object's placement: the HRW list of
+// targets the object was placed on (ec.Metadata.Targets). Given the recorded
+// list and the current one (rj.smap) - and regardless of how many cluster
+// changes there were in between:
+// - the piece stays if this target remains in the list;
+// - otherwise, if the main target has changed, the piece is dropped: global
+//   rebalance delivers the object to its new main target that, in turn,
+//   re-encodes it (see recvRebalanceObj), thus regenerating slices and
+//   metadata in accordance with the new placement; the old main target itself
+//   keeps the metafile until the object is delivered (see ecMainCallback);
+// - otherwise, the piece (along with its metadata) is moved to one of the
+//   targets that joined the list: the i-th target that left the list sends its
+//   piece to the i-th target that joined it.
+// Either way, the pieces that stay and the pieces that move get the current
+// list recorded, so that all the holders agree upon the next change.
+//
+
+const (
+	ecRebStay = iota
+	ecRebMain // the main object (see globalRebJogger)
+	ecRebDrop
+	ecRebMove
+	ecRebUnknown // placement not recorded (older metafile)
+)
+
+func newECRebJogger(base rebJoggerBase, smap *smapX) *ecRebJogger {
+	return &ecRebJogger{rebJoggerBase: base, smap: smap}
+}
+
+func (rj *ecRebJogger) jog() {
+	if err := filepath.Walk(rj.mpath, rj.resumable(rj.walk)); err != nil {
+		s := err.Error()
+		if strings.Contains(s, "xaction") {
+			glog.Infof("Stopping %s traversal due to: %s", rj.mpath, s)
+		} else {
+			glog.Errorf("Failed to traverse %s, err: %v", rj.mpath, err)
+		}
+	}
+
+	rj.xreb.confirmCh <- struct{}{}
+	rj.wg.Done()
+}
+
+func (rj *ecRebJogger) walk(fqn string, fi os.FileInfo, err error) error {
+	if rj.xreb.Aborted() {
+		return fmt.Errorf("%s: aborted, path %s", rj.xreb, rj.mpath)
+	}
+	if err != nil {
+		if errstr := cmn.PathWalkErr(err); errstr != "" {
+			glog.Error(errstr)
+			return err
+		}
+		return nil
+	}
+	if fi.Mode().IsDir() {
+		return nil
+	}
+	rj.throttle()
+	parsed, err := fs.Mountpaths.FQN2Info(fqn)
+	if err != nil {
+		if glog.V(4) {
+			glog.Infof("%s, err %v - skipping...", fqn, err)
+		}
+		return nil
+	}
+	meta, err := ec.LoadMetadata(fqn)
+	if err != nil {
+		glog.Error(err)
+		return nil
+	}
+	var (
+		bucket, objname = parsed.Bucket, parsed.Objname
+		contentType     = ec.SliceType
+		cnt             = meta.Parity + 1
+	)
+	if !meta.IsCopy {
+		cnt += meta.Data
+	}
+	if meta.SliceID == 0 {
+		contentType = fs.ObjectType
+	}
+	curr, errstr := cluster.HrwTargetList(bucket, objname, &rj.smap.Smap, cnt)
+	if errstr != "" {
+		glog.Errorf("%s/%s (slice #%d): %s", bucket, objname, meta.SliceID, errstr)
+		return nil
+	}
+	dataFQN := fs.CSM.FQN(parsed.MpathInfo, contentType, parsed.IsLocal, bucket, objname)
+	act, daemonID := rj.placement(meta, curr)
+	switch act {
+	case ecRebStay:
+		rj.relist(bucket, objname, fqn, meta, curr)
+		return nil
+	case ecRebMain:
+		// the main object is migrated by the global rebalance proper that drops
+		// the metafile once the object is delivered (see ecMainCallback)
+		return nil
+	case ecRebDrop:
+		rj.drop(bucket, objname, fqn, dataFQN) // to be regenerated by the new main target
+		return nil
+	case ecRebUnknown:
+		if glog.V(4) {
+			glog.Infof("%s/%s (slice #%d): unknown placement - skipping...", bucket, objname, meta.SliceID)
+		}
+		return nil
+	}
+	if daemonID == "" {
+		glog.Errorf("%s/%s (slice #%d): no destination", bucket, objname, meta.SliceID)
+		return nil
+	}
+	if glog.V(4) {
+		glog.Infof("%s/%s (slice #%d) %s => %s", bucket, objname, meta.SliceID, tname(rj.t.si), daemonID)
+	}
+	cb := func(hdr transport.Header, r io.ReadCloser, err error) {
+		if err != nil {
+			glog.Errorf("failed to rebalance EC slice/replica: %s/%s, err: %v", hdr.Bucket, hdr.Objname, err)
+			rj.xreb.AddErr(err)
+		} else {
+			rj.drop(bucket, objname, fqn, dataFQN)
+			atomic.AddInt64(&rj.objectsMoved, 1)
+			atomic.AddInt64(&rj.bytesMoved, hdr.ObjAttrs.Size)
+			rj.xreb.ObjectsAdd(1)
+			rj.xreb.BytesAdd(hdr.ObjAttrs.Size)
+		}
+		rj.cp.pending.Done()
+		rj.wg.Done()
+	}
+	moved := *meta
+	moved.Targets = hrwIDs(curr)
+	rj.wg.Add(1) // NOTE: Done happens in case of Migrate error or in the callback
+	rj.cp.pending.Add(1)
+	rj.xreb.TotalAdd(1)
+	if err := rj.t.ecmanager.Migrate(daemonID, dataFQN, &moved, cb); err != nil {
+		glog.Errorf("failed to rebalance %s: %v", dataFQN, err)
+		rj.cp.pending.Done()
+		rj.wg.Done()
+	}
+	return nil
+}
+
+// placement decides what to do with the local piece given the placement recorded
+// in its metafile and the current HRW list; returns the destination to move to
+func (rj *ecRebJogger) placement(meta *ec.Metadata, curr []*cluster.Snode) (act int, daemonID string) {
+	self := rj.t.si.DaemonID
+	if hrwContains(curr, self) {
+		return ecRebStay, ""
+	}
+	prev := meta.Targets
+	if !cmn.StringInSlice(self, prev) {
+		return ecRebUnknown, ""
+	}
+	if prev[0] == self {
+		return ecRebMain, ""
+	}
+	if prev[0] != curr[0].DaemonID && rj.smap.GetTarget(prev[0]) != nil {
+		return ecRebDrop, ""
+	}
+	return ecRebMove, rj.dest(prev, curr)
+}
+
+// pairs the targets that left the HRW list with the targets that joined it
+func (rj *ecRebJogger) dest(prev []string, curr []*cluster.Snode) string {
+	var (
+		left, joined []string
+		self         = rj.t.si.DaemonID
+	)
+	for _, sid := range prev {
+		if !hrwContains(curr, sid) {
+			left = append(left, sid)
+		}
+	}
+	for _, si := range curr {
+		if !cmn.StringInSlice(si.DaemonID, prev) {
+			joined = append(joined, si.DaemonID)
+		}
+	}
+	for i, sid := range left {
+		if sid == self && i < len(joined) {
+			return joined[i]
+		}
+	}
+	return ""
+}
+
+// relist records the current placement in the metafile of the piece that stays
+func (rj *ecRebJogger) relist(bucket, objname, metaFQN string, meta *ec.Metadata, curr []*cluster.Snode) {
+	ids := hrwIDs(curr)
+	if reflect.DeepEqual(ids, meta.Targets) {
+		return
+	}
+	uname := cluster.Uname(bucket, objname)
+	rj.t.rtnamemap.Lock(uname, true)
+	// (re)loading under the lock - the object may have been re-encoded in the meantime
+	if meta, err := ec.LoadMetadata(metaFQN); err == nil {
+		meta.Targets = ids
+		if err := ec.SaveMetadata(metaFQN, meta); err != nil {
+			glog.Errorf("failed to update %s: %v", metaFQN, err)
+		}
+	}
+	rj.t.rtnamemap.Unlock(uname, true)
+}
+
+// removes the metafile first and then the replica/slice (if specified) - same order as ec does
+func (rj *rebJoggerBase) drop(bucket, objname, metaFQN, dataFQN string) {
+	uname := cluster.Uname(bucket, objname)
+	rj.t.rtnamemap.Lock(uname, true)
+	for _, fqn := range []string{metaFQN, dataFQN} {
+		if fqn == "" {
+			continue
+		}
+		if err := os.Remove(fqn); err != nil && !os.IsNotExist(err) {
+			glog.Errorf("failed to remove %s: %v", fqn, err)
+			break
+		}
+	}
+	rj.t.rtnamemap.Unlock(uname, true)
+}
+
+func hrwContains(list []*cluster.Snode, sid string) bool {
+	for _, si := range list {
+		if si.DaemonID == sid {
+			return true
+		}
+	}
+	return false
+}
+
+func hrwIDs(list []*cluster.Snode) []string {
+	ids := make([]string, 0, len(list))
+	for _, si := range list {
+		ids = append(ids, si.DaemonID)
+	}
+	return ids
+}
+
 //
 // LOCAL REBALANCE
 //
 
 func (rj *localRebJogger) jog() {
 	rj.buf = rj.slab.Alloc()
-	if err := filepath.Walk(rj.mpath, rj.walk); err != nil {
+	if err := filepath.Walk(rj.mpath, rj.resumable(rj.walk)); err != nil {
 		s := err.Error()
 		if strings.Contains(s, "xaction") {
 			glog.Infof("Stopping %s traversal due to: %s", rj.mpath, s)
@@ -257,6 +596,7 @@ func (rj *localRebJogger) walk(fqn string, fileInfo os.FileInfo, err error) erro
 	if fileInfo.IsDir() {
 		return nil
 	}
+	rj.throttle()
 	lom := &cluster.LOM{T: rj.t, FQN: fqn}
 	if errstr := lom.Fill("", cluster.LomFstat|cluster.LomCopy); errstr != "" {
 		if glog.V(4) {
@@ -278,7 +618,7 @@ func (rj *localRebJogger) walk(fqn string, fileInfo os.FileInfo, err error) erro
 	dir := filepath.Dir(lom.HrwFQN)
 	if err := cmn.CreateDir(dir); err != nil {
 		glog.Errorf("Failed to create dir: %s", dir)
-		rj.xreb.Abort()
+		rj.xreb.AbortWith(fmt.Sprintf("failed to create %s: %v", dir, err))
 		rj.t.fshc(err, lom.HrwFQN)
 		return nil
 	}
@@ -287,24 +627,49 @@ func (rj *localRebJogger) walk(fqn string, fileInfo os.FileInfo, err error) erro
 	// Note that global rebalance can run at the same time and by copying we
 	// allow local and global rebalance to work in parallel - global rebalance
 	// can still access the old object.
+	// EC slices and metafiles, on the other hand, are not subject to LRU - they move.
 
 	if glog.V(4) {
 		glog.Infof("Copying %s => %s", fqn, lom.HrwFQN)
 	}
 
-	rj.t.rtnamemap.Lock(lom.Uname, false)
+	contentType := lom.ParsedFQN.ContentType
+	move := contentType == ec.SliceType || contentType == ec.MetaType
+	rj.t.rtnamemap.Lock(lom.Uname, move)
+	// both the object and its mirrored copy are misplaced: the first one to get here wins
+	if lom.CopyFQN != "" {
+		if _, err := os.Stat(lom.HrwFQN); err == nil {
+			rj.t.rtnamemap.Unlock(lom.Uname, move)
+			return nil
+		}
+	}
 	if err := lom.CopyObject(lom.HrwFQN, rj.buf); err != nil {
-		rj.t.rtnamemap.Unlock(lom.Uname, false)
+		rj.t.rtnamemap.Unlock(lom.Uname, move)
 		if !os.IsNotExist(err) {
-			rj.xreb.Abort()
+			rj.xreb.AbortWith(fmt.Sprintf("failed to copy %s: %v", lom, err))
 			rj.t.fshc(err, lom.HrwFQN)
 			return err
 		}
 		return nil
 	}
-	rj.t.rtnamemap.Unlock(lom.Uname, false)
+	if move {
+		if err := os.Remove(fqn); err != nil && !os.IsNotExist(err) {
+			glog.Errorf("Failed to remove %s: %v", fqn, err)
+		}
+	}
+	rj.t.rtnamemap.Unlock(lom.Uname, move)
 	rj.objectsMoved++
 	rj.bytesMoved += fileInfo.Size()
+	rj.xreb.ObjectsAdd(1)
+	rj.xreb.BytesAdd(fileInfo.Size())
+
+	// re-mirror at the new location
+	if !move && lom.MirrorConf.Enabled {
+		hlom := &cluster.LOM{T: rj.t, FQN: lom.HrwFQN}
+		if errstr := hlom.Fill("", cluster.LomFstat|cluster.LomCopy); errstr == "" && hlom.Exists() && !hlom.HasCopy() {
+			rj.t.localMirror(hlom)
+		}
+	}
 	return nil
 }
 
@@ -468,11 +833,13 @@ func (reb *rebManager) runGlobalReb(smap *smapX, newTargetID string) {
 	// abort in-progress xaction if exists and if its Smap version is lower
 	// start new xaction unless the one for the current version is already in progress
 	availablePaths, _ := fs.Mountpaths.Get()
-	runnerCnt := len(availablePaths) * 2
+	runnerCnt := len(availablePaths) * 4 // objects and EC metafiles, cloud and local
+	decommission := smap.Decommissioning(reb.t.si.DaemonID)
 	xreb := reb.t.xactions.renewGlobalReb(ver, runnerCnt)
 	if xreb == nil {
 		return
 	}
+	reb.t.recordEvent(cmn.EventRebalance, fmt.Sprintf("global rebalance started, Smap v%d", ver))
 
 	// establish connections with nodes, as they are not created on change of smap
 	reb.streams.Resync()
@@ -489,32 +856,53 @@ func (reb *rebManager) runGlobalReb(smap *smapX, newTargetID string) {
 
 	glog.Infoln(xreb.String())
 	wg = &sync.WaitGroup{}
+	ckpt := newRebCheckpoint(globalRebType)
+	key := smapPlacementKey(smap)
 
-	joggers := make([]*globalRebJogger, 0, runnerCnt)
-	// TODO: currently supporting a single content-type: Object
+	joggers := make([]*globalRebJogger, 0, runnerCnt/2)
+	// objects (and their mirrored copies - see recvRebalanceObj)
 	for _, mpathInfo := range availablePaths {
 		mpathC := mpathInfo.MakePath(fs.ObjectType, false /*cloud*/)
-		rc := &globalRebJogger{rebJoggerBase: rebJoggerBase{t: reb.t, mpath: mpathC, xreb: xreb, wg: wg}, smap: smap}
+		rc := &globalRebJogger{rebJoggerBase: rebJoggerBase{t: reb.t, mpath: mpathC, xreb: xreb, wg: wg,
+			cp: ckpt.jogger(mpathC, key)}, smap: smap}
 		wg.Add(1)
 		joggers = append(joggers, rc)
 		go rc.jog()
 
 		mpathL := mpathInfo.MakePath(fs.ObjectType, true /*is local*/)
-		rl := &globalRebJogger{rebJoggerBase: rebJoggerBase{t: reb.t, mpath: mpathL, xreb: xreb, wg: wg}, smap: smap}
+		rl := &globalRebJogger{rebJoggerBase: rebJoggerBase{t: reb.t, mpath: mpathL, xreb: xreb, wg: wg,
+			cp: ckpt.jogger(mpathL, key)}, smap: smap}
 		wg.Add(1)
 		joggers = append(joggers, rl)
 		go rl.jog()
 	}
+	// EC slices, replicas and metafiles
+	ecJoggers := make([]*ecRebJogger, 0, runnerCnt/2)
+	for _, mpathInfo := range availablePaths {
+		for _, isLocal := range []bool{false, true} {
+			mpath := mpathInfo.MakePath(ec.MetaType, isLocal)
+			rj := newECRebJogger(rebJoggerBase{t: reb.t, mpath: mpath, xreb: xreb, wg: wg,
+				cp: ckpt.jogger(mpath, key)}, smap)
+			wg.Add(1)
+			ecJoggers = append(ecJoggers, rj)
+			go rj.jog()
+		}
+	}
 	wg.Wait()
+	ckpt.stop(!xreb.Aborted())
 
+	var totalObjectsMoved, totalBytesMoved int64
+	for _, jogger := range joggers {
+		totalObjectsMoved += jogger.objectsMoved
+		totalBytesMoved += jogger.bytesMoved
+	}
+	for _, jogger := range ecJoggers {
+		totalObjectsMoved += jogger.objectsMoved
+		totalBytesMoved += jogger.bytesMoved
+	}
+	reb.t.recordEvent(cmn.EventRebalance, fmt.Sprintf("global rebalance finished (aborted=%t), Smap v%d: %d objects, %s moved",
+		xreb.Aborted(), ver, totalObjectsMoved, cmn.B2S(totalBytesMoved, 1)))
 	if pmarker != "" {
-		var (
-			totalObjectsMoved, totalBytesMoved int64
-		)
-		for _, jogger := range joggers {
-			totalObjectsMoved += jogger.objectsMoved
-			totalBytesMoved += jogger.bytesMoved
-		}
 		if !xreb.Aborted() {
 			if err := os.Remove(pmarker); err != nil {
 				glog.Errorf("Failed to remove rebalance-in-progress mark %s, err: %v", pmarker, err)
@@ -530,6 +918,9 @@ func (reb *rebManager) runGlobalReb(smap *smapX, newTargetID string) {
 		reb.pollRebalancingDone(smap) // until the cluster is fully rebalanced - see t.httpobjget
 	}
 	xreb.EndTime(time.Now())
+	if decommission && !xreb.Aborted() {
+		reb.leaveCluster()
+	}
 }
 
 func (reb *rebManager) pollRebalancingDone(newSmap *smapX) {
@@ -553,7 +944,7 @@ func (reb *rebManager) pollRebalancingDone(newSmap *smapX) {
 func (reb *rebManager) runLocalReb() {
 	var (
 		availablePaths, _ = fs.Mountpaths.Get()
-		runnerCnt         = len(availablePaths) * 2
+		runnerCnt         = len(availablePaths) * 6 // 3 content types, cloud and local
 		joggers           = make([]*localRebJogger, 0, runnerCnt)
 
 		xreb      = reb.t.xactions.renewLocalReb(runnerCnt)
@@ -568,30 +959,40 @@ func (reb *rebManager) runLocalReb() {
 	}
 	wg := &sync.WaitGroup{}
 	glog.Infof("starting local rebalance with %d runners\n", runnerCnt)
+	reb.t.recordEvent(cmn.EventRebalance, fmt.Sprintf("local rebalance started, %d mountpaths", len(availablePaths)))
 	slab := gmem2.SelectSlab2(cmn.MiB) // FIXME: estimate
+	ckpt := newRebCheckpoint(localRebType)
+	key := mpathPlacementKey(availablePaths)
 
-	// TODO: currently supporting a single content-type: Object
+	// objects, EC slices and metafiles
 	for _, mpathInfo := range availablePaths {
-		mpathC := mpathInfo.MakePath(fs.ObjectType, false /*cloud*/)
-		jogger := &localRebJogger{rebJoggerBase: rebJoggerBase{t: reb.t, mpath: mpathC, xreb: xreb, wg: wg}, slab: slab}
-		wg.Add(1)
-		joggers = append(joggers, jogger)
-		go jogger.jog()
-
-		mpathL := mpathInfo.MakePath(fs.ObjectType, true /*is local*/)
-		jogger = &localRebJogger{rebJoggerBase: rebJoggerBase{t: reb.t, mpath: mpathL, xreb: xreb, wg: wg}, slab: slab}
-		wg.Add(1)
-		joggers = append(joggers, jogger)
-		go jogger.jog()
+		for _, contentType := range []string{fs.ObjectType, ec.SliceType, ec.MetaType} {
+			mpathC := mpathInfo.MakePath(contentType, false /*cloud*/)
+			jogger := &localRebJogger{rebJoggerBase: rebJoggerBase{t: reb.t, mpath: mpathC, xreb: xreb, wg: wg,
+				cp: ckpt.jogger(mpathC, key)}, slab: slab}
+			wg.Add(1)
+			joggers = append(joggers, jogger)
+			go jogger.jog()
+
+			mpathL := mpathInfo.MakePath(contentType, true /*is local*/)
+			jogger = &localRebJogger{rebJoggerBase: rebJoggerBase{t: reb.t, mpath: mpathL, xreb: xreb, wg: wg,
+				cp: ckpt.jogger(mpathL, key)}, slab: slab}
+			wg.Add(1)
+			joggers = append(joggers, jogger)
+			go jogger.jog()
+		}
 	}
 	wg.Wait()
+	ckpt.stop(!xreb.Aborted())
 
+	totalObjectsMoved, totalBytesMoved := int64(0), int64(0)
+	for _, jogger := range joggers {
+		totalObjectsMoved += jogger.objectsMoved
+		totalBytesMoved += jogger.bytesMoved
+	}
+	reb.t.recordEvent(cmn.EventRebalance, fmt.Sprintf("local rebalance finished (aborted=%t): %d objects, %s moved",
+		xreb.Aborted(), totalObjectsMoved, cmn.B2S(totalBytesMoved, 1)))
 	if pmarker != "" {
-		totalObjectsMoved, totalBytesMoved := int64(0), int64(0)
-		for _, jogger := range joggers {
-			totalObjectsMoved += jogger.objectsMoved
-			totalBytesMoved += jogger.bytesMoved
-		}
 		if !xreb.Aborted() {
 			if err := os.Remove(pmarker); err != nil {
 				glog.Errorf("Failed to remove rebalance-in-progress mark %s, err: %v", pmarker, err)
@@ -0,0 +1,202 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/ec"
+	"github.com/NVIDIA/aistore/transport"
+)
+
+func TestECRebDest(t *testing.T) {
+	tests := []struct {
+		self string
+		prev []string
+		curr []*cluster.Snode
+		dest string
+	}{
+		// a single new target has taken over the self's position
+		{"t2", ids("t1", "t2", "t3"), nodes("t1", "t4", "t3"), "t4"},
+		// two targets left, two joined: i-th left => i-th joined
+		{"t2", ids("t1", "t2", "t3", "t5"), nodes("t1", "t6", "t4", "t5"), "t6"},
+		{"t3", ids("t1", "t2", "t3", "t5"), nodes("t1", "t6", "t4", "t5"), "t4"},
+		// self has not left the list
+		{"t2", ids("t1", "t2", "t3"), nodes("t1", "t4", "t2"), ""},
+	}
+	for _, test := range tests {
+		rj := &ecRebJogger{rebJoggerBase: rebJoggerBase{t: newFakeTargetRunner()}}
+		rj.t.si.DaemonID = test.self
+		if dest := rj.dest(test.prev, test.curr); dest != test.dest {
+			t.Errorf("%s: expected %q, got %q", test.self, test.dest, dest)
+		}
+	}
+}
+
+// the placement is derived from the metafile and the current Smap only - the
+// same Smap as before the rebalance (e.g., a manual or resumed global rebalance,
+// or the target has missed the Smap versions in between) does not matter
+func TestECRebPlacement(t *testing.T) {
+	smap := &smapX{}
+	smap.init(5, 0, 0)
+	for _, id := range []string{"t1", "t2", "t3", "t4", "t5"} {
+		smap.Tmap[id] = &cluster.Snode{DaemonID: id}
+	}
+	tests := []struct {
+		self    string
+		targets []string // as recorded in the metafile
+		curr    []*cluster.Snode
+		act     int
+		dest    string
+	}{
+		{"t2", ids("t1", "t2", "t3"), nodes("t1", "t3", "t2"), ecRebStay, ""},
+		{"t2", nil, nodes("t1", "t4", "t3"), ecRebUnknown, ""},
+		{"t1", ids("t1", "t2", "t3"), nodes("t4", "t2", "t3"), ecRebMain, ""},
+		// the new main target (t4) regenerates the slices
+		{"t2", ids("t1", "t2", "t3"), nodes("t4", "t1", "t3"), ecRebDrop, ""},
+		{"t2", ids("t1", "t2", "t3"), nodes("t1", "t4", "t3"), ecRebMove, "t4"},
+		{"t2", ids("t6", "t2", "t3"), nodes("t1", "t4", "t3"), ecRebMove, "t4"}, // (t6 gone)
+	}
+	for i, test := range tests {
+		rj := &ecRebJogger{rebJoggerBase: rebJoggerBase{t: newFakeTargetRunner()}, smap: smap}
+		rj.t.si.DaemonID = test.self
+		act, dest := rj.placement(&ec.Metadata{Targets: test.targets}, test.curr)
+		if act != test.act || dest != test.dest {
+			t.Errorf("test case %d: expected (%d, %q), got (%d, %q)", i, test.act, test.dest, act, dest)
+		}
+	}
+}
+
+func ids(ids ...string) []string { return ids }
+
+func nodes(ids ...string) []*cluster.Snode {
+	list := make([]*cluster.Snode, 0, len(ids))
+	for _, id := range ids {
+		list = append(list, &cluster.Snode{DaemonID: id})
+	}
+	return list
+}
+
+func TestECRebMainMetafile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reb-ec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	metaFQN := filepath.Join(dir, "obj")
+	if err := ioutil.WriteFile(metaFQN, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		xreb = makeXactRebBase(1, globalRebType, 1)
+		rj   = &globalRebJogger{rebJoggerBase: rebJoggerBase{t: newFakeTargetRunner(), xreb: &xreb,
+			wg: &sync.WaitGroup{}, cp: &rebJoggerCkpt{}}}
+		hdr = transport.Header{Bucket: "bucket", Objname: "obj"}
+	)
+	rj.t.rtnamemap = newrtnamemap()
+	uname := cluster.Uname(hdr.Bucket, hdr.Objname)
+	send := func(err error) {
+		rj.t.rtnamemap.Lock(uname, false)
+		rj.wg.Add(1)
+		rj.cp.pending.Add(1)
+		rj.ecMainCallback(metaFQN)(hdr, nil, err)
+		rj.wg.Wait()
+	}
+	// the metafile stays until the object is delivered
+	send(errors.New("failed to send"))
+	if _, err := os.Stat(metaFQN); err != nil {
+		t.Fatalf("expecting the metafile to stay, err: %v", err)
+	}
+	send(nil)
+	if _, err := os.Stat(metaFQN); !os.IsNotExist(err) {
+		t.Errorf("expecting the metafile to be dropped, err: %v", err)
+	}
+}
+
+func TestRebResumableWalk(t *testing.T) {
+	root, err := ioutil.TempDir("", "reb-ckpt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	files := []string{"a/x", "a/y", "a-b/z", "b/c/x", "b/d", "c"}
+	for _, name := range files {
+		fqn := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(fqn), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(fqn, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, resume := range append([]string{""}, files...) {
+		var (
+			walked []string
+			ckpt   = &rebCheckpoint{Paths: make(map[string]rebPosition)}
+			rj     = &rebJoggerBase{mpath: root, cp: &rebJoggerCkpt{ckpt: ckpt, resume: resume}}
+		)
+		walk := func(fqn string, fi os.FileInfo, err error) error {
+			if err == nil && !fi.IsDir() {
+				walked = append(walked, fqn[len(root)+1:])
+			}
+			return err
+		}
+		if err := filepath.Walk(root, rj.resumable(walk)); err != nil {
+			t.Fatal(err)
+		}
+		if expected := files[i:]; len(walked) != 0 || len(expected) != 0 {
+			if !reflect.DeepEqual(walked, expected) {
+				t.Errorf("resume at %q: expected %v, got %v", resume, expected, walked)
+			}
+		}
+		// (the zero-time jogger checkpoints the first walked file right away)
+		if len(walked) > 0 && ckpt.Paths[root].Pos != walked[0] {
+			t.Errorf("resume at %q: unexpected checkpoint %v", resume, ckpt.Paths[root])
+		}
+	}
+}
+
+func TestRebPreviewSmap(t *testing.T) {
+	smap := &smapX{}
+	smap.init(3, 0, 0)
+	for _, id := range []string{"t1", "t2", "t3"} {
+		smap.Tmap[id] = &cluster.Snode{DaemonID: id}
+	}
+	query := func(add, remove string) url.Values {
+		return url.Values{cmn.URLParamAddTargets: []string{add}, cmn.URLParamRemoveTargets: []string{remove}}
+	}
+	preview, errstr := previewSmap(smap, query("t4, t5", "t1"))
+	if errstr != "" {
+		t.Fatal(errstr)
+	}
+	if preview.CountTargets() != 4 || preview.GetTarget("t1") != nil || preview.GetTarget("t5") == nil {
+		t.Errorf("unexpected preview Smap %s", preview.pp())
+	}
+	if smap.CountTargets() != 3 {
+		t.Errorf("original Smap modified: %s", smap.pp())
+	}
+	for _, q := range []url.Values{query("", ""), query("t2", ""), query("", "t4"), query("", "t1,t2,t3")} {
+		if _, errstr := previewSmap(smap, q); errstr == "" {
+			t.Errorf("%v: expected error", q)
+		}
+	}
+
+	// aggregation
+	p := cmn.NewRebPreview()
+	p.Add("t1", map[string]cmn.RebPreviewMove{"t4": {Objects: 2, Size: 20}, "t2": {Objects: 1, Size: 10}})
+	p.Add("t3", map[string]cmn.RebPreviewMove{"t4": {Objects: 3, Size: 30}})
+	if p.To["t4"].Objects != 5 || p.From["t1"].Size != 30 || p.Matrix["t3"]["t4"].Size != 30 ||
+		p.Total.Objects != 6 || p.Total.Size != 60 {
+		t.Errorf("unexpected preview %+v", p)
+	}
+}
@@ -0,0 +1,259 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+//
+// Rebalance checkpointing: every rebalance jogger periodically records its
+// position - the last walked file such that all the files prior to it (in the
+// filepath.Walk order) have been fully processed, in-flight transmissions
+// included. The positions are persisted next to the rebalance-in-progress
+// marker, so that the interrupted rebalance resumes from where it left off.
+// Each position is valid only for the placement (the key) it was recorded with:
+// the jogger walks from the beginning if its key has changed in the meantime.
+// The same checkpointing makes the scrubber resumable as well (see scrub.go).
+//
+
+const rebCheckpointInterval = 10 * time.Second
+
+type (
+	rebCheckpoint struct {
+		mu       sync.Mutex
+		pathname string
+		dirty    bool
+		stopCh   chan struct{}
+		doneCh   chan struct{}
+		Paths    map[string]rebPosition `json:"paths"` // jogger's root (mpath/content-type/...) => position
+	}
+	rebPosition struct {
+		Key string `json:"key"`
+		Pos string `json:"pos"` // relative to the root
+	}
+	// per-jogger state
+	rebJoggerCkpt struct {
+		ckpt    *rebCheckpoint
+		key     string
+		resume  string         // position to resume from ("" - from the beginning)
+		pending sync.WaitGroup // in-flight transmissions
+		last    time.Time
+	}
+)
+
+func checkpointPath(rebType int) string {
+	switch rebType {
+	case localRebType:
+		return filepath.Join(cmn.GCO.Get().Confdir, cmn.LocalRebCheckpoint)
+	case globalRebType:
+		return filepath.Join(cmn.GCO.Get().Confdir, cmn.GlobalRebCheckpoint)
+	}
+
+	cmn.Assert(false)
+	return ""
+}
+
+// loads the checkpoint of the interrupted rebalance, if any, and starts persisting the new one
+func newRebCheckpoint(rebType int) *rebCheckpoint {
+	return loadCheckpoint(checkpointPath(rebType))
+}
+
+// loadCheckpoint is also used by the other resumable walkers (e.g., the scrubber)
+func loadCheckpoint(pathname string) *rebCheckpoint {
+	ckpt := &rebCheckpoint{
+		pathname: pathname,
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+	if err := cmn.LocalLoad(ckpt.pathname, ckpt); err != nil && !os.IsNotExist(err) {
+		glog.Errorf("Failed to load checkpoint %s, err: %v", ckpt.pathname, err)
+	}
+	if ckpt.Paths == nil {
+		ckpt.Paths = make(map[string]rebPosition)
+	}
+	go ckpt.run()
+	return ckpt
+}
+
+func (ckpt *rebCheckpoint) run() {
+	ticker := time.NewTicker(rebCheckpointInterval)
+	for {
+		select {
+		case <-ticker.C:
+			ckpt.save()
+		case <-ckpt.stopCh:
+			ticker.Stop()
+			ckpt.save()
+			close(ckpt.doneCh)
+			return
+		}
+	}
+}
+
+// stops persisting; removes the checkpoint if the walk has completed
+func (ckpt *rebCheckpoint) stop(completed bool) {
+	close(ckpt.stopCh)
+	<-ckpt.doneCh
+	if !completed {
+		return
+	}
+	if err := os.Remove(ckpt.pathname); err != nil && !os.IsNotExist(err) {
+		glog.Errorf("Failed to remove checkpoint %s, err: %v", ckpt.pathname, err)
+	}
+}
+
+func (ckpt *rebCheckpoint) save() {
+	ckpt.mu.Lock()
+	defer ckpt.mu.Unlock()
+	if !ckpt.dirty {
+		return
+	}
+	if err := cmn.LocalSave(ckpt.pathname, ckpt); err != nil {
+		glog.Errorf("Failed to save checkpoint %s, err: %v", ckpt.pathname, err)
+		return
+	}
+	ckpt.dirty = false
+}
+
+func (ckpt *rebCheckpoint) set(root, key, pos string) {
+	ckpt.mu.Lock()
+	ckpt.Paths[root] = rebPosition{Key: key, Pos: pos}
+	ckpt.dirty = true
+	ckpt.mu.Unlock()
+}
+
+func (ckpt *rebCheckpoint) get(root string) (p rebPosition, ok bool) {
+	ckpt.mu.Lock()
+	p, ok = ckpt.Paths[root]
+	ckpt.mu.Unlock()
+	return
+}
+
+// returns the per-jogger state that resumes the walk at the recorded position
+// unless the key has changed
+func (ckpt *rebCheckpoint) jogger(root, key string) *rebJoggerCkpt {
+	cp := &rebJoggerCkpt{ckpt: ckpt, key: key, last: time.Now()}
+	ckpt.mu.Lock()
+	if p, ok := ckpt.Paths[root]; ok && p.Key == key {
+		cp.resume = p.Pos
+	}
+	ckpt.mu.Unlock()
+	if cp.resume != "" {
+		glog.Infof("%s: resuming at %s", root, cp.resume)
+	}
+	ckpt.set(root, key, cp.resume) // (overwriting stale position)
+	return cp
+}
+
+// placement keys
+
+func smapPlacementKey(smap *smapX) string {
+	ids := make([]string, 0, len(smap.Tmap))
+	for id, si := range smap.Tmap {
+		if smap.Decommissioning(id) {
+			continue
+		}
+		key := id
+		if w := smap.Weight(id); w != 1 {
+			key += "*" + strconv.FormatInt(w, 10)
+		}
+		if l := si.Labels; !l.IsEmpty() {
+			key += "@" + l.Zone + "/" + l.Rack + "/" + l.Host
+		}
+		ids = append(ids, key)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+func mpathPlacementKey(mpaths map[string]*fs.MountpathInfo) string {
+	paths := make([]string, 0, len(mpaths))
+	for mpath := range mpaths {
+		paths = append(paths, mpath)
+	}
+	sort.Strings(paths)
+	return strings.Join(paths, ",")
+}
+
+//
+// resumable walk
+//
+
+// walkCmp compares slash-separated relative paths in the filepath.Walk order
+// (lexical, depth-first) - an ancestor directory precedes its content
+func walkCmp(a, b string) int {
+	for {
+		ia, ib := strings.IndexByte(a, '/'), strings.IndexByte(b, '/')
+		ca, cb := a, b
+		if ia >= 0 {
+			ca = a[:ia]
+		}
+		if ib >= 0 {
+			cb = b[:ib]
+		}
+		if ca != cb {
+			if ca < cb {
+				return -1
+			}
+			return 1
+		}
+		switch {
+		case ia < 0 && ib < 0:
+			return 0
+		case ia < 0:
+			return -1
+		case ib < 0:
+			return 1
+		}
+		a, b = a[ia+1:], b[ib+1:]
+	}
+}
+
+// resumable wraps the jogger's walk function to skip what's been done and
+// to checkpoint progress
+func (rj *rebJoggerBase) resumable(walk filepath.WalkFunc) filepath.WalkFunc {
+	return resumableWalk(rj.mpath, rj.cp, walk)
+}
+
+func resumableWalk(root string, cp *rebJoggerCkpt, walk filepath.WalkFunc) filepath.WalkFunc {
+	return func(fqn string, fi os.FileInfo, err error) error {
+		if err != nil || len(fqn) <= len(root) {
+			return walk(fqn, fi, err)
+		}
+		rel := fqn[len(root)+1:]
+		if cp.resume != "" {
+			cmp := walkCmp(rel, cp.resume)
+			if fi.IsDir() {
+				if cmp < 0 && !strings.HasPrefix(cp.resume, rel+"/") {
+					return filepath.SkipDir
+				}
+			} else if cmp <= 0 {
+				return nil
+			} else {
+				cp.resume = "" // past the resume point
+			}
+		}
+		if err := walk(fqn, fi, err); err != nil {
+			return err
+		}
+		if !fi.IsDir() && time.Since(cp.last) >= rebCheckpointInterval {
+			cp.pending.Wait()
+			cp.ckpt.set(root, cp.key, rel)
+			cp.last = time.Now()
+		}
+		return nil
+	}
+}
@@ -0,0 +1,183 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	jsoniter "github.com/json-iterator/go"
+)
+
+//
+// Rebalance preview (GET /v1/cluster?what=rebpreview): given a hypothetical
+// cluster change - targets added and/or removed - each target walks its
+// objects and computes, via cluster.HrwTarget, how many objects and bytes would
+// leave it and for where. Nothing gets moved. The primary (in fact, any) proxy
+// aggregates the results into the source => destination matrix.
+//
+
+// previewSmap returns the clone of the Smap with the targets added and removed
+// as per cmn.URLParamAddTargets and cmn.URLParamRemoveTargets
+func previewSmap(smap *smapX, query url.Values) (*smapX, string) {
+	split := func(s string) (ids []string) {
+		for _, id := range strings.Split(s, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		return
+	}
+	var (
+		added   = split(query.Get(cmn.URLParamAddTargets))
+		removed = split(query.Get(cmn.URLParamRemoveTargets))
+	)
+	if len(added) == 0 && len(removed) == 0 {
+		return nil, "rebalance preview: no targets to add or remove"
+	}
+	preview := smap.clone()
+	for _, id := range added {
+		if preview.containsID(id) {
+			return nil, fmt.Sprintf("rebalance preview: duplicate daemon ID %s", id)
+		}
+		si := &cluster.Snode{DaemonID: id}
+		si.Digest()
+		preview.Tmap[id] = si
+	}
+	for _, id := range removed {
+		if preview.GetTarget(id) == nil {
+			return nil, fmt.Sprintf("rebalance preview: target %s is not present in the Smap v%d", id, smap.version())
+		}
+		delete(preview.Tmap, id)
+		delete(preview.Maintenance, id)
+	}
+	if preview.CountPlacementTargets() == 0 {
+		return nil, "rebalance preview: no targets left"
+	}
+	return preview, ""
+}
+
+//
+// target
+//
+
+// rebPreview walks all the (non-copy) objects and returns the ones that would
+// move upon the change, by destination
+func (t *targetrunner) rebPreview(smap *smapX) map[string]cmn.RebPreviewMove {
+	var (
+		wg                = &sync.WaitGroup{}
+		mu                = &sync.Mutex{}
+		self              = t.si.DaemonID
+		availablePaths, _ = fs.Mountpaths.Get()
+		moves             = make(map[string]cmn.RebPreviewMove, len(smap.Tmap))
+	)
+	for _, mpathInfo := range availablePaths {
+		for _, isLocal := range []bool{false, true} {
+			mpath := mpathInfo.MakePath(fs.ObjectType, isLocal)
+			wg.Add(1)
+			go func(mpath string) {
+				local := make(map[string]cmn.RebPreviewMove)
+				walk := func(fqn string, fi os.FileInfo, err error) error {
+					if err != nil {
+						if errstr := cmn.PathWalkErr(err); errstr != "" {
+							glog.Error(errstr)
+							return err
+						}
+						return nil
+					}
+					if fi.IsDir() {
+						return nil
+					}
+					lom := &cluster.LOM{T: t, FQN: fqn}
+					if errstr := lom.Fill("", cluster.LomFstat|cluster.LomCopy); errstr != "" || !lom.Exists() || lom.IsCopy() {
+						return nil
+					}
+					si, errstr := cluster.HrwTarget(lom.Bucket, lom.Objname, &smap.Smap)
+					if errstr != "" || si.DaemonID == self {
+						return nil
+					}
+					move := local[si.DaemonID]
+					move.Objects++
+					move.Size += lom.Size
+					local[si.DaemonID] = move
+					return nil
+				}
+				if err := filepath.Walk(mpath, walk); err != nil {
+					glog.Errorf("rebalance preview: failed to traverse %s, err: %v", mpath, err)
+				}
+				mu.Lock()
+				for id, move := range local {
+					m := moves[id]
+					m.Objects += move.Objects
+					m.Size += move.Size
+					moves[id] = m
+				}
+				mu.Unlock()
+				wg.Done()
+			}(mpath)
+		}
+	}
+	wg.Wait()
+	return moves
+}
+
+func (t *targetrunner) httpRebPreview(w http.ResponseWriter, r *http.Request) {
+	smap, errstr := previewSmap(t.smapowner.get(), r.URL.Query())
+	if errstr != "" {
+		t.invalmsghdlr(w, r, errstr)
+		return
+	}
+	jsbytes, err := jsoniter.Marshal(t.rebPreview(smap))
+	cmn.AssertNoErr(err)
+	t.writeJSON(w, r, jsbytes, "rebpreview")
+}
+
+//
+// proxy
+//
+
+func (p *proxyrunner) httpRebPreview(w http.ResponseWriter, r *http.Request) {
+	smap := p.smapowner.get()
+	if _, errstr := previewSmap(smap, r.URL.Query()); errstr != "" {
+		p.invalmsghdlr(w, r, errstr)
+		return
+	}
+	results := p.broadcastTo(
+		cmn.URLPath(cmn.Version, cmn.Daemon),
+		r.URL.Query(),
+		r.Method,
+		nil, // message
+		smap,
+		cmn.GCO.Get().Timeout.DefaultLong, // (traversing all objects)
+		cmn.NetworkIntraControl,
+		cluster.Targets,
+	)
+	preview := cmn.NewRebPreview()
+	for result := range results {
+		if result.err != nil {
+			p.invalmsghdlr(w, r, result.errstr)
+			return
+		}
+		moves := make(map[string]cmn.RebPreviewMove)
+		if err := jsoniter.Unmarshal(result.outjson, &moves); err != nil {
+			p.invalmsghdlr(w, r, fmt.Sprintf("rebalance preview: failed to unmarshal %s response, err: %v",
+				tname(result.si), err))
+			return
+		}
+		preview.Add(result.si.DaemonID, moves)
+	}
+	jsbytes, err := jsoniter.Marshal(preview)
+	cmn.AssertNoErr(err)
+	p.writeJSON(w, r, jsbytes, "rebpreview")
+}
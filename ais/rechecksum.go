@@ -14,12 +14,14 @@ import (
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/governor"
 )
 
 type recksumctx struct {
 	xrcksum *xactRechecksum
 	t       *targetrunner
 	fs      string
+	mpath   string
 }
 
 // TODO: Smap and mpath changes, fspath runner, progress bar, and more...
@@ -70,6 +72,7 @@ func (t *targetrunner) oneRechecksumBucket(mpathInfo *fs.MountpathInfo, bucketDi
 		xrcksum: xrcksum,
 		t:       t,
 		fs:      mpathInfo.FileSystem,
+		mpath:   mpathInfo.Path,
 	}
 
 	if err := filepath.Walk(bucketDir, rcksctx.walk); err != nil {
@@ -92,7 +95,7 @@ func (rcksctx *recksumctx) walk(fqn string, osfi os.FileInfo, err error) error {
 		return nil
 	}
 
-	// FIXME: throttle self - see lru
+	governor.Gov.Throttle(rcksctx.mpath, governor.PrioLow)
 
 	// stop traversing if xaction is aborted
 	select {
@@ -102,8 +105,13 @@ func (rcksctx *recksumctx) walk(fqn string, osfi os.FileInfo, err error) error {
 		break
 	}
 
-	// TODO: future scrubber to |cluster.LomCksumPresentRecomp, and scrub
+	// NOTE: verifying the present checksums is the scrubber's job (see scrub.go)
 	lom := &cluster.LOM{FQN: fqn, Size: osfi.Size()}
-	_ = lom.Fill("", cluster.LomCksum|cluster.LomCksumMissingRecomp)
+	if errstr := lom.Fill("", cluster.LomCksum|cluster.LomCksumMissingRecomp); errstr != "" {
+		rcksctx.xrcksum.AddErr(errors.New(errstr))
+		return nil
+	}
+	rcksctx.xrcksum.ObjectsAdd(1)
+	rcksctx.xrcksum.BytesAdd(osfi.Size())
 	return nil
 }
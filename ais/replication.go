@@ -320,7 +320,7 @@ func (r *mpathReplicator) send(req *replRequest) error {
 	r.t.rtnamemap.Lock(lom.Uname, req.deleteObject)
 	defer r.t.rtnamemap.Unlock(lom.Uname, req.deleteObject)
 
-	if errstr = lom.Fill(bckProvider, cluster.LomCksum|cluster.LomCksumMissingRecomp); errstr != "" {
+	if errstr = lom.Fill(bckProvider, cluster.LomVersion|cluster.LomCksum|cluster.LomCksumMissingRecomp); errstr != "" {
 		return errors.New(errstr)
 	}
 
@@ -341,11 +341,8 @@ func (r *mpathReplicator) send(req *replRequest) error {
 
 	atimestr, _, _ := getatimerunner().FormatAtime(req.fqn, lom.ParsedFQN.MpathInfo.Path, r.atimeRespCh, lom.LRUenabled())
 
-	// obtain the version of the file
-	if version, errstr := fs.GetXattr(req.fqn, cmn.XattrVersion); errstr != "" {
-		glog.Errorf("Failed to read %q xattr %s, err %s", req.fqn, cmn.XattrVersion, errstr)
-	} else if len(version) != 0 {
-		httpReq.Header.Add(cmn.HeaderObjVersion, string(version))
+	if lom.Version != "" {
+		httpReq.Header.Add(cmn.HeaderObjVersion, lom.Version)
 	}
 
 	// specify source direct URL in request header
@@ -0,0 +1,288 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+//
+// Cloud bucket resync: detect objects that were added, changed or deleted in the
+// Cloud bypassing AIStore, and (optionally) evict stale cached copies, refresh
+// them, and/or prefetch new objects. Each target runs the resync xaction for the
+// objects it owns (as per HRW); the xaction's report (see cmn.XactStats.Resync)
+// gets updated as it goes and can be queried (and the xaction aborted) via
+// /v1/xactions - the proxy only starts it.
+//
+
+type resyncctx struct {
+	t       *targetrunner
+	xresync *xactResync
+	ct      context.Context
+	bucket  string
+	msg     *cmn.ResyncMsg
+	smap    *smapX
+	cached  map[string]struct{} // names of the locally cached objects
+}
+
+//
+// xactResync
+//
+
+func (xresync *xactResync) Snapshot() cmn.XactStats {
+	st := xresync.XactBase.Snapshot()
+	xresync.mu.Lock()
+	report := xresync.report
+	report.Added = append([]string(nil), xresync.report.Added...)
+	report.Changed = append([]string(nil), xresync.report.Changed...)
+	report.Deleted = append([]string(nil), xresync.report.Deleted...)
+	report.Errors = append([]string(nil), xresync.report.Errors...)
+	xresync.mu.Unlock()
+	st.Resync = &report
+	return st
+}
+
+// update modifies the report under lock
+func (xresync *xactResync) update(f func(report *cmn.ResyncReport)) {
+	xresync.mu.Lock()
+	f(&xresync.report)
+	xresync.mu.Unlock()
+}
+
+func (xresync *xactResync) addErr(errstr string) {
+	xresync.AddErr(errors.New(errstr))
+	xresync.update(func(report *cmn.ResyncReport) { report.AddError(errstr) })
+}
+
+// startResync starts resyncing the bucket in the background and returns the xaction
+func (t *targetrunner) startResync(ct context.Context, bucket string, msg *cmn.ResyncMsg) (*xactResync, error) {
+	xresync := t.xactions.renewResync(bucket)
+	if xresync == nil {
+		return nil, fmt.Errorf("resync of bucket %s is already in progress", bucket)
+	}
+	rctx := &resyncctx{
+		t:       t,
+		xresync: xresync,
+		ct:      ct,
+		bucket:  bucket,
+		msg:     msg,
+		smap:    t.smapowner.get(),
+		cached:  make(map[string]struct{}, 1024),
+	}
+	go rctx.run()
+	return xresync, nil
+}
+
+// run compares the Cloud bucket with its cached objects
+func (rctx *resyncctx) run() {
+	xresync := rctx.xresync
+	defer xresync.EndTime(time.Now())
+
+	glog.Infof("Resync: %s started: bucket: %s", xresync, rctx.bucket)
+	if err := rctx.listCached(); err != nil {
+		rctx.fail(err)
+		return
+	}
+	if err := rctx.listCloud(); err != nil {
+		rctx.fail(err)
+		return
+	}
+	// whatever remains cached but is not listed by the Cloud anymore
+	for objname := range rctx.cached {
+		if xresync.Aborted() {
+			break
+		}
+		xresync.update(func(report *cmn.ResyncReport) { report.AddDeleted(objname) })
+		if rctx.msg.Evict || rctx.msg.Refresh {
+			rctx.evict(objname)
+		}
+	}
+	if xresync.Aborted() {
+		glog.Infof("Resync: %s aborted", xresync)
+		return
+	}
+	st := xresync.Snapshot()
+	glog.Infof("Resync: %s done: added %d, changed %d, deleted %d", xresync,
+		st.Resync.AddedCount, st.Resync.ChangedCount, st.Resync.DeletedCount)
+}
+
+func (rctx *resyncctx) fail(err error) {
+	glog.Errorf("Resync: %s failed, err: %v", rctx.xresync, err)
+	rctx.xresync.addErr(err.Error())
+}
+
+// owned returns true if the object belongs to this target (as per HRW)
+func (rctx *resyncctx) owned(objname string) (bool, error) {
+	si, errstr := hrwTarget(rctx.bucket, objname, rctx.smap)
+	if errstr != "" {
+		return false, errors.New(errstr)
+	}
+	return si.DaemonID == rctx.t.si.DaemonID, nil
+}
+
+// listCached lists the cached objects this target owns - the misplaced ones
+// are accounted for by their respective owners
+func (rctx *resyncctx) listCached() error {
+	getMsg := &cmn.GetMsg{GetPrefix: rctx.msg.Prefix}
+	for {
+		bucketList, err := rctx.t.prepareLocalObjectList(rctx.bucket, getMsg)
+		if err != nil {
+			return err
+		}
+		for _, entry := range bucketList.Entries {
+			owned, err := rctx.owned(entry.Name)
+			if err != nil {
+				return err
+			}
+			if owned {
+				rctx.cached[entry.Name] = struct{}{}
+			}
+		}
+		if bucketList.PageMarker == "" {
+			return nil
+		}
+		getMsg.GetPageMarker = bucketList.PageMarker
+	}
+}
+
+func (rctx *resyncctx) listCloud() error {
+	getMsg := &cmn.GetMsg{
+		GetPrefix: rctx.msg.Prefix,
+		GetProps:  strings.Join([]string{cmn.GetPropsSize, cmn.GetPropsVersion, cmn.GetPropsChecksum}, ","),
+	}
+	for {
+		if rctx.xresync.Aborted() {
+			return nil
+		}
+		bucketList, err := getCloudBucketPage(rctx.ct, rctx.bucket, getMsg)
+		if err != nil {
+			return err
+		}
+		for _, entry := range bucketList.Entries {
+			owned, err := rctx.owned(entry.Name)
+			if err != nil {
+				return err
+			}
+			if !owned {
+				continue
+			}
+			delete(rctx.cached, entry.Name)
+			rctx.compare(entry)
+			rctx.xresync.ObjectsAdd(1)
+		}
+		if bucketList.PageMarker == "" {
+			return nil
+		}
+		getMsg.GetPageMarker = bucketList.PageMarker
+	}
+}
+
+func (rctx *resyncctx) compare(entry *cmn.BucketEntry) {
+	lom := &cluster.LOM{T: rctx.t, Bucket: rctx.bucket, Objname: entry.Name}
+	if errstr := lom.Fill("", cluster.LomFstat|cluster.LomVersion); errstr != "" {
+		rctx.xresync.addErr(errstr)
+		return
+	}
+	if !lom.Exists() {
+		rctx.xresync.update(func(report *cmn.ResyncReport) { report.AddAdded(entry.Name) })
+		if rctx.msg.Prefetch {
+			rctx.coldGet(lom, func(report *cmn.ResyncReport) { report.Prefetched++ })
+		}
+		return
+	}
+	if !rctx.changed(lom, entry) {
+		return
+	}
+	rctx.xresync.update(func(report *cmn.ResyncReport) { report.AddChanged(entry.Name) })
+	if rctx.msg.Evict || rctx.msg.Refresh {
+		if !rctx.evict(entry.Name) {
+			return
+		}
+	}
+	if rctx.msg.Refresh {
+		lom = &cluster.LOM{T: rctx.t, Bucket: rctx.bucket, Objname: entry.Name}
+		if errstr := lom.Fill("", 0); errstr != "" {
+			rctx.xresync.addErr(errstr)
+			return
+		}
+		rctx.coldGet(lom, func(report *cmn.ResyncReport) { report.Refreshed++ })
+	}
+}
+
+// an object is considered changed when its version, size, or (optionally)
+// MD5 differ from those reported by the Cloud
+func (rctx *resyncctx) changed(lom *cluster.LOM, entry *cmn.BucketEntry) bool {
+	if lom.Version != "" && entry.Version != "" && lom.Version != entry.Version {
+		return true
+	}
+	if lom.Size != entry.Size {
+		return true
+	}
+	// NOTE: multipart uploads are reported with ETags (e.g. "<hex>-<parts>") that are not MD5
+	if rctx.msg.Cksum && entry.Checksum != "" && !strings.Contains(entry.Checksum, "-") {
+		md5hex, err := computeMD5(lom.FQN)
+		if err != nil {
+			rctx.xresync.addErr(err.Error())
+			return false
+		}
+		return md5hex != strings.Trim(entry.Checksum, "\"")
+	}
+	return false
+}
+
+func (rctx *resyncctx) evict(objname string) bool {
+	lom := &cluster.LOM{T: rctx.t, Bucket: rctx.bucket, Objname: objname}
+	if errstr := lom.Fill("", cluster.LomFstat|cluster.LomCopy); errstr != "" {
+		rctx.xresync.addErr(errstr)
+		return false
+	}
+	if !lom.Exists() {
+		return true
+	}
+	if err := rctx.t.objDelete(rctx.ct, lom, true /*evict*/); err != nil {
+		rctx.xresync.addErr(err.Error())
+		return false
+	}
+	rctx.xresync.update(func(report *cmn.ResyncReport) { report.Evicted++ })
+	return true
+}
+
+// coldGet fetches the object from the Cloud and, if successful, counts it
+func (rctx *resyncctx) coldGet(lom *cluster.LOM, count func(report *cmn.ResyncReport)) {
+	if errstr, _ := rctx.t.getCold(rctx.ct, lom, true /*prefetch*/, 0); errstr != "" {
+		if errstr != "skip" {
+			rctx.xresync.addErr(errstr)
+		}
+		return
+	}
+	rctx.xresync.update(count)
+	rctx.xresync.BytesAdd(lom.Size)
+}
+
+func computeMD5(fqn string) (string, error) {
+	file, err := os.Open(fqn)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s, err: %v", fqn, err)
+	}
+	defer file.Close()
+	buf, slab := gmem2.AllocFromSlab2(cmn.MiB)
+	defer slab.Free(buf)
+	h := md5.New()
+	if _, err = cmn.ReceiveAndChecksum(ioutil.Discard, file, buf, h); err != nil {
+		return "", fmt.Errorf("failed to compute md5 of %s, err: %v", fqn, err)
+	}
+	return cmn.HashToStr(h), nil
+}
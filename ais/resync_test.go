@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+func TestResyncChanged(t *testing.T) {
+	rctx := &resyncctx{msg: &cmn.ResyncMsg{}}
+	lom := &cluster.LOM{Version: "2", Size: cmn.KiB}
+	tests := []struct {
+		entry   cmn.BucketEntry
+		changed bool
+	}{
+		{cmn.BucketEntry{Version: "2", Size: cmn.KiB}, false},
+		{cmn.BucketEntry{Version: "3", Size: cmn.KiB}, true},
+		{cmn.BucketEntry{Size: cmn.KiB}, false}, // versioning disabled in the Cloud
+		{cmn.BucketEntry{Version: "2", Size: cmn.MiB}, true},
+		{cmn.BucketEntry{Size: cmn.MiB}, true},
+	}
+	for _, test := range tests {
+		if changed := rctx.changed(lom, &test.entry); changed != test.changed {
+			t.Errorf("%+v: expecting changed=%t", test.entry, test.changed)
+		}
+	}
+}
+
+func TestResyncOwned(t *testing.T) {
+	smap := newSmap()
+	for _, id := range []string{"t1", "t2", "t3"} {
+		smap.addTarget(newSnode(id, httpProto, &net.TCPAddr{}, &net.TCPAddr{}, &net.TCPAddr{}))
+	}
+	// every object is owned by exactly one target
+	for i := 0; i < 100; i++ {
+		objname := fmt.Sprintf("obj-%d", i)
+		owners := 0
+		for id, si := range smap.Tmap {
+			rctx := &resyncctx{t: &targetrunner{}, bucket: "cloud", smap: smap}
+			rctx.t.si = si
+			owned, err := rctx.owned(objname)
+			if err != nil {
+				t.Fatalf("%s: %v", id, err)
+			}
+			if owned {
+				owners++
+			}
+		}
+		if owners != 1 {
+			t.Fatalf("%s: expecting a single owner, got %d", objname, owners)
+		}
+	}
+}
+
+func TestResyncReport(t *testing.T) {
+	xs := newXs()
+	xresync := xs.renewResync("cloud")
+	if xresync == nil || xs.renewResync("cloud") != nil {
+		t.Fatalf("expecting a single %s xaction per bucket", cmn.ActResync)
+	}
+	for i := 0; i < cmn.ResyncMaxNames+10; i++ {
+		objname := fmt.Sprintf("obj-%d", i)
+		xresync.update(func(report *cmn.ResyncReport) { report.AddAdded(objname) })
+	}
+	for i := 0; i < cmn.ResyncMaxErrors+10; i++ {
+		xresync.addErr(fmt.Sprintf("error %d", i))
+	}
+	l := xs.list(&cmn.XactMsg{Kind: cmn.ActResync, Bucket: "cloud"}, false)
+	if len(l) != 1 || l[0].Resync == nil {
+		t.Fatalf("expecting %s report, got %+v", cmn.ActResync, l)
+	}
+	report := l[0].Resync
+	if report.AddedCount != cmn.ResyncMaxNames+10 || len(report.Added) != cmn.ResyncMaxNames {
+		t.Errorf("expecting %d added objects with %d names, got %d and %d",
+			cmn.ResyncMaxNames+10, cmn.ResyncMaxNames, report.AddedCount, len(report.Added))
+	}
+	if report.ErrorsCount != cmn.ResyncMaxErrors+10 || len(report.Errors) != cmn.ResyncMaxErrors ||
+		l[0].ErrCnt != cmn.ResyncMaxErrors+10 {
+		t.Errorf("expecting %d errors with %d messages, got %d and %d (xaction: %d)",
+			cmn.ResyncMaxErrors+10, cmn.ResyncMaxErrors, report.ErrorsCount, len(report.Errors), l[0].ErrCnt)
+	}
+
+	// aggregated over targets, the names and errors remain capped
+	total := &cmn.ResyncReport{}
+	total.Merge(report)
+	total.Merge(report)
+	if total.AddedCount != 2*report.AddedCount || len(total.Added) != cmn.ResyncMaxNames ||
+		total.ErrorsCount != 2*report.ErrorsCount || len(total.Errors) != cmn.ResyncMaxErrors {
+		t.Errorf("unexpected aggregated report: %d/%d added, %d/%d errors",
+			total.AddedCount, len(total.Added), total.ErrorsCount, len(total.Errors))
+	}
+
+	// abortable by kind and bucket
+	if cnt := xs.abortL(&cmn.XactMsg{Kind: cmn.ActResync, Bucket: "cloud"}, "test"); cnt != 1 {
+		t.Fatalf("expecting 1 aborted xaction, got %d", cnt)
+	}
+}
@@ -0,0 +1,99 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+//
+// revsOwner is the common part of the owners of the (immutable and versioned)
+// cluster metadata that the primary proxy distributes via metasync - besides
+// Smap and BMD: the cluster event log, scheduled jobs, and cluster-wide config.
+// It holds the current version, persists it (unless pathname is empty), and
+// accepts the newer versions received from the primary.
+//
+// The owners embed revsOwner and provide typed get() that never returns nil.
+//
+
+type revsOwner struct {
+	sync.Mutex
+	cur      atomic.Value // current version: revs
+	pathname string       // where to persist; empty - not to persist (tests)
+	what     string       // e.g., "scheduled jobs" (to log)
+}
+
+// init loads the persisted version, if any, into the given zero version
+func (r *revsOwner) init(pathname, what string, zero revs) {
+	r.pathname, r.what = pathname, what
+	if err := cmn.LocalLoad(r.pathname, zero); err == nil {
+		r.put(zero)
+	}
+}
+
+func (r *revsOwner) put(v revs) { r.cur.Store(v) }
+
+// getRevs returns the current version or nil
+func (r *revsOwner) getRevs() revs {
+	if v := r.cur.Load(); v != nil {
+		return v.(revs)
+	}
+	return nil
+}
+
+func (r *revsOwner) curVersion() int64 {
+	if v := r.getRevs(); v != nil {
+		return v.version()
+	}
+	return 0
+}
+
+func (r *revsOwner) persist(v revs) { r.save(v, v.version()) }
+
+// save stores a given version or the owner-specific state that includes it
+func (r *revsOwner) save(v interface{}, version int64) {
+	if r.pathname == "" {
+		return
+	}
+	if err := cmn.LocalSave(r.pathname, v); err != nil {
+		glog.Errorf("Failed to store %s v%d, err: %v", r.what, version, err)
+	}
+}
+
+// synchronize (non-primary) accepts the newer version: persists and makes it current
+// or, if specified, calls apply (under lock) to do that; returns false if not newer
+func (r *revsOwner) synchronize(newv revs, apply ...func(revs)) bool {
+	r.Lock()
+	defer r.Unlock()
+	if newv.version() <= r.curVersion() {
+		return false
+	}
+	if len(apply) > 0 {
+		apply[0](newv)
+	} else {
+		r.persist(newv)
+		r.put(newv)
+	}
+	return true
+}
+
+// extractRevs unmarshals the metasync-ed version (payload[tag]) into v;
+// returns false if the payload does not have it
+func extractRevs(payload cmn.SimpleKVs, tag, what string, v revs) (ok bool, errstr string) {
+	var bytes string
+	if bytes, ok = payload[tag]; !ok {
+		return
+	}
+	if err := jsoniter.Unmarshal([]byte(bytes), v); err != nil {
+		ok, errstr = false, fmt.Sprintf("Failed to unmarshal %s, value (%+v, %T), err: %v", what, bytes, bytes, err)
+	}
+	return
+}
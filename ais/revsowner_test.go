@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+func TestRevsOwner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "revsowner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	r := &jobsowner{}
+	r.init(dir)
+	if jobs := r.get(); jobs.version() != 0 {
+		t.Fatalf("expecting zero version, got v%d", jobs.version())
+	}
+	v2 := (&jobsMD{Version: 1}).clone()
+	v2.add(cmn.Job{Name: "j1"})
+	if !r.synchronize(v2) || r.get() != v2 {
+		t.Fatal("expecting the newer version accepted")
+	}
+	if r.synchronize(&jobsMD{Version: 2}) || r.get() != v2 {
+		t.Fatal("expecting the same version rejected")
+	}
+
+	// persisted
+	loaded := &jobsowner{}
+	loaded.init(dir)
+	if jobs := loaded.get(); jobs.version() != 2 || len(jobs.Jobs) != 1 {
+		t.Fatalf("expecting v2 with 1 job loaded, got %+v", jobs)
+	}
+	if _, err := os.Stat(filepath.Join(dir, cmn.JobsBackupFile)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -5,6 +5,8 @@
 package ais
 
 import (
+	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -13,27 +15,93 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 )
 
-const (
-	pollInterval = time.Millisecond * 100
-	initCapacity = 128
-)
+const initCapacity = 128
 
 // Implements cluster.NameLocker interface.
 // Locks objects stored in the cluster when there's a pending GET, PUT, etc. transaction
 // and we don't want the object in question to be updated or evicted concurrently.
 // Objects in the cluster are locked by their unique names aka unames.
 // The lock can be exclusive (write) or shared (read).
+//
+// Those who cannot get the lock right away wait in line: each name has its own FIFO queue
+// of waiters that get woken up when the lock is released - no polling. To keep both
+// readers and writers from starving, a shared lock is not granted while there are
+// waiters in line (e.g., a writer waiting for the current readers to finish); when
+// the lock gets released, it goes to the first waiting writer or to all the readers
+// at the head of the queue.
 
 var _ cluster.NameLocker = &rtnamemap{}
 
-type rtnamemap struct {
-	mu sync.Mutex
-	m  map[string]*lockInfo
+type (
+	rtnamemap struct {
+		mu sync.Mutex
+		m  map[string]*lockInfo
+	}
+	lockInfo struct {
+		rc        int // number of shared holders
+		exclusive bool
+		since     time.Time // when acquired by the first of the current holders
+		waiters   []*lockWaiter
+	}
+	lockWaiter struct {
+		exclusive bool
+		granted   bool
+		since     time.Time
+		ch        chan struct{}
+	}
+)
+
+//
+// lockInfo
+//
+func (info *lockInfo) held() bool { return info.exclusive || info.rc > 0 }
+
+func (info *lockInfo) canLock(exclusive bool) bool {
+	if len(info.waiters) > 0 {
+		return false
+	}
+	if exclusive {
+		return !info.held()
+	}
+	return !info.exclusive
+}
+
+func (info *lockInfo) acquire(exclusive bool, now time.Time) {
+	if !info.held() {
+		info.since = now
+	}
+	if exclusive {
+		info.exclusive = true
+	} else {
+		info.rc++
+	}
+}
+
+// grant hands the lock over to the waiter(s) at the head of the queue, if possible
+func (info *lockInfo) grant() {
+	now := time.Now()
+	for len(info.waiters) > 0 {
+		w := info.waiters[0]
+		if info.exclusive || (w.exclusive && info.rc > 0) {
+			return
+		}
+		info.waiters[0] = nil
+		info.waiters = info.waiters[1:]
+		info.acquire(w.exclusive, now)
+		w.granted = true
+		close(w.ch)
+	}
 }
 
-type lockInfo struct {
-	rc        int
-	exclusive bool
+func (info *lockInfo) dequeue(w *lockWaiter) {
+	for i, other := range info.waiters {
+		if other == w {
+			copy(info.waiters[i:], info.waiters[i+1:])
+			info.waiters[len(info.waiters)-1] = nil
+			info.waiters = info.waiters[:len(info.waiters)-1]
+			return
+		}
+	}
 }
 
 //
@@ -45,63 +113,63 @@ func newrtnamemap() *rtnamemap {
 	}
 }
 
-func (rtnamemap *rtnamemap) TryLock(uname string, exclusive bool) bool {
+func (rtnamemap *rtnamemap) TryLock(uname string, exclusive bool) (ok bool) {
 	rtnamemap.mu.Lock()
-
-	realInfo, found := rtnamemap.m[uname]
-	info := &lockInfo{}
-	if exclusive {
-		if found {
-			rtnamemap.mu.Unlock()
-			return false
-		}
-
+	info, found := rtnamemap.m[uname]
+	if !found {
+		info = &lockInfo{}
 		rtnamemap.m[uname] = info
-		info.exclusive = true
-		rtnamemap.mu.Unlock()
-		return true
 	}
-
-	// rlock
-	if found {
-		if realInfo.exclusive {
-			rtnamemap.mu.Unlock()
-			return false
-		}
-	} else {
-		rtnamemap.m[uname] = info // the 1st rlock
-		realInfo = info
+	if ok = info.canLock(exclusive); ok {
+		info.acquire(exclusive, time.Now())
 	}
-	realInfo.rc++
 	rtnamemap.mu.Unlock()
-	return true
+	return
 }
 
-// NOTE that Lock() stays in the loop for as long as needed to
-// acquire the lock.
-// The implementation is intentionally simple as we currently
-// don't need cancelation (via context.Context), timeout,
-// increasing/random polling intervals, sync.Cond -based wait
-// for Unlock - none of the above.
-// Use TryLock() to support any such semantics that makes sense.
-
+// Lock waits for as long as needed to acquire the lock
 func (rtnamemap *rtnamemap) Lock(uname string, exclusive bool) {
-	if rtnamemap.TryLock(uname, exclusive) {
-		return
-	}
-	for {
-		time.Sleep(pollInterval)
-		if rtnamemap.TryLock(uname, exclusive) {
-			if glog.V(4) {
-				glog.Infof("Lock %s(%t) - success", uname, exclusive)
-			}
-			return
-		}
+	_ = rtnamemap.LockCtx(context.Background(), uname, exclusive)
+}
 
-		if glog.V(4) {
-			glog.Infof("Lock %s(%t) - retrying...", uname, exclusive)
+// LockCtx waits in line until either the lock is acquired or the context is done
+// (canceled or past its deadline) - whichever comes first. In the latter case,
+// the lock is not held and the context's error is returned.
+func (rtnamemap *rtnamemap) LockCtx(ctx context.Context, uname string, exclusive bool) error {
+	now := time.Now()
+	rtnamemap.mu.Lock()
+	info, found := rtnamemap.m[uname]
+	if !found {
+		info = &lockInfo{}
+		rtnamemap.m[uname] = info
+	}
+	if info.canLock(exclusive) {
+		info.acquire(exclusive, now)
+		rtnamemap.mu.Unlock()
+		return nil
+	}
+	w := &lockWaiter{exclusive: exclusive, since: now, ch: make(chan struct{})}
+	info.waiters = append(info.waiters, w)
+	rtnamemap.mu.Unlock()
+
+	select {
+	case <-w.ch:
+		if glog.FastV(4, glog.SmoduleAIS) {
+			glog.Infof("Lock %s(%t) - success after %v", uname, exclusive, time.Since(now))
 		}
+		return nil
+	case <-ctx.Done():
+	}
+	rtnamemap.mu.Lock()
+	if w.granted { // got it anyway
+		rtnamemap.mu.Unlock()
+		return nil
 	}
+	info.dequeue(w)
+	info.grant() // those behind may now proceed
+	rtnamemap.mu.Unlock()
+	glog.Warningf("Lock %s(%t) - giving up after %v: %v", uname, exclusive, time.Since(now), ctx.Err())
+	return ctx.Err()
 }
 
 func (rtnamemap *rtnamemap) DowngradeLock(uname string) {
@@ -111,6 +179,7 @@ func (rtnamemap *rtnamemap) DowngradeLock(uname string) {
 	info.exclusive = false
 	info.rc++
 	cmn.Assert(info.rc == 1)
+	info.grant() // readers at the head of the queue
 	rtnamemap.mu.Unlock()
 }
 
@@ -120,13 +189,35 @@ func (rtnamemap *rtnamemap) Unlock(uname string, exclusive bool) {
 	cmn.Assert(found)
 	if exclusive {
 		cmn.Assert(info.exclusive)
-		delete(rtnamemap.m, uname)
-		rtnamemap.mu.Unlock()
-		return
+		info.exclusive = false
+	} else {
+		cmn.Assert(info.rc > 0)
+		info.rc--
 	}
-	info.rc--
-	if info.rc == 0 {
-		delete(rtnamemap.m, uname)
+	if !info.held() {
+		if len(info.waiters) == 0 {
+			delete(rtnamemap.m, uname)
+		} else {
+			info.grant()
+		}
+	}
+	rtnamemap.mu.Unlock()
+}
+
+// Locks returns all currently held and waited-for locks, the longest held first
+// (see GetWhatLocks)
+func (rtnamemap *rtnamemap) Locks() []cmn.NameLockInfo {
+	now := time.Now()
+	rtnamemap.mu.Lock()
+	locks := make([]cmn.NameLockInfo, 0, len(rtnamemap.m))
+	for uname, info := range rtnamemap.m {
+		li := cmn.NameLockInfo{Uname: uname, Exclusive: info.exclusive, Readers: info.rc, Age: now.Sub(info.since)}
+		for _, w := range info.waiters {
+			li.Waiters = append(li.Waiters, cmn.NameLockWaiter{Exclusive: w.exclusive, Waiting: now.Sub(w.since)})
+		}
+		locks = append(locks, li)
 	}
 	rtnamemap.mu.Unlock()
+	sort.Slice(locks, func(i, j int) bool { return locks[i].Age > locks[j].Age })
+	return locks
 }
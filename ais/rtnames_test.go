@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+const testUname = "bucket/object"
+
+func lockAsync(m *rtnamemap, exclusive bool) chan error {
+	ch := make(chan error, 1)
+	go func() { ch <- m.LockCtx(context.Background(), testUname, exclusive) }()
+	return ch
+}
+
+// waitQueued waits for the given number of waiters to get in line
+func waitQueued(t *testing.T, m *rtnamemap, n int) {
+	for i := 0; i < 100; i++ {
+		m.mu.Lock()
+		info, ok := m.m[testUname]
+		queued := ok && len(info.waiters) == n
+		m.mu.Unlock()
+		if queued {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expecting %d waiters", n)
+}
+
+func isLocked(ch chan error) bool {
+	select {
+	case err := <-ch:
+		return err == nil
+	case <-time.After(time.Second):
+		return false
+	}
+}
+
+func TestNameLockTimeout(t *testing.T) {
+	m := newrtnamemap()
+	m.Lock(testUname, true)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := m.LockCtx(ctx, testUname, false); err != context.DeadlineExceeded {
+		t.Fatalf("expecting deadline exceeded, got %v", err)
+	}
+	m.Unlock(testUname, true)
+	if len(m.m) != 0 {
+		t.Fatalf("expecting no locks, got %d", len(m.m))
+	}
+}
+
+func TestNameLockFairness(t *testing.T) {
+	m := newrtnamemap()
+	m.Lock(testUname, false)
+
+	// a writer waits for the reader; the readers that come after it wait for the writer
+	w := lockAsync(m, true)
+	waitQueued(t, m, 1)
+	if m.TryLock(testUname, false) {
+		t.Fatal("shared lock must not be granted ahead of the waiting writer")
+	}
+	r1, r2 := lockAsync(m, false), lockAsync(m, false)
+	waitQueued(t, m, 3)
+
+	locks := m.Locks()
+	if len(locks) != 1 || locks[0].Readers != 1 || locks[0].Exclusive || len(locks[0].Waiters) != 3 ||
+		!locks[0].Waiters[0].Exclusive {
+		t.Fatalf("unexpected locks: %+v", locks)
+	}
+
+	m.Unlock(testUname, false)
+	if !isLocked(w) {
+		t.Fatal("expecting the writer to get the lock")
+	}
+	// downgrading lets in all the readers waiting behind
+	m.DowngradeLock(testUname)
+	if !isLocked(r1) || !isLocked(r2) {
+		t.Fatal("expecting the readers to get the lock")
+	}
+	for i := 0; i < 3; i++ {
+		m.Unlock(testUname, false)
+	}
+	if len(m.m) != 0 {
+		t.Fatalf("expecting no locks, got %d", len(m.m))
+	}
+}
+
+func TestNameLockCancelUnblocks(t *testing.T) {
+	m := newrtnamemap()
+	m.Lock(testUname, false)
+
+	// a canceled writer must not keep the readers behind it waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	w := make(chan error, 1)
+	go func() { w <- m.LockCtx(ctx, testUname, true) }()
+	waitQueued(t, m, 1)
+	r := lockAsync(m, false)
+	waitQueued(t, m, 2)
+	cancel()
+	if err := <-w; err != context.Canceled {
+		t.Fatalf("expecting canceled, got %v", err)
+	}
+	if !isLocked(r) {
+		t.Fatal("expecting the reader to get the lock")
+	}
+	if locks := m.Locks(); len(locks) != 1 || locks[0].Readers != 2 || len(locks[0].Waiters) != 0 {
+		t.Fatalf("unexpected locks: %+v", locks)
+	}
+}
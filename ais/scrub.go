@@ -0,0 +1,568 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/ec"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/governor"
+)
+
+//
+// Scrubber
+//
+// A low-priority xaction that walks all mountpaths, pass after pass, and looks for:
+// - objects (and mirror copies) that do not match their stored checksums;
+// - objects stored on the wrong mountpath or target (reported only - rebalance moves them);
+// - workfiles left behind by a previous run of the target;
+// - EC slices without their metafiles;
+// - mirror copies (and objects) cross-referencing copies that do not exist.
+//
+// Depending on scrub.policy the scrubber only reports the findings, moves the bad
+// content aside (see fs.QuarantineType), or repairs: corrupted objects get restored
+// from the mirror, EC, or the Cloud (in that order), the rest gets cleaned up.
+//
+// Each pass is checkpointed the same way rebalance is (see rebcheckpoint.go),
+// so that the restarted target resumes the interrupted pass.
+//
+
+const (
+	maxScrubFindings = 100              // findings reported via xaction stats (the most recent)
+	scrubGrace       = 10 * time.Minute // EC slices younger than that may still be waiting for their metafiles
+	scrubKey         = "scrub"          // checkpoint key: the walk in progress
+	scrubDone        = "done"           // checkpoint key: the root has been scrubbed in the current pass
+)
+
+var errScrubAborted = errors.New("scrubbing aborted") // stops traversal
+
+type (
+	xactScrub struct {
+		cmn.XactBase
+		mu    sync.Mutex
+		stats cmn.ScrubStats
+	}
+	scrubJogger struct {
+		t         *targetrunner
+		xscrub    *xactScrub
+		mpathInfo *fs.MountpathInfo
+		policy    string
+		rebalance bool // rebalance is running: misplaced objects are expected
+	}
+)
+
+//
+// xactScrub
+//
+
+func (xscrub *xactScrub) Snapshot() cmn.XactStats {
+	st := xscrub.XactBase.Snapshot()
+	xscrub.mu.Lock()
+	stats := xscrub.stats
+	stats.Findings = append([]cmn.ScrubFinding(nil), xscrub.stats.Findings...)
+	xscrub.mu.Unlock()
+	st.Scrub = &stats
+	return st
+}
+
+func (xscrub *xactScrub) newPass(pass int64, policy string, resumed bool) {
+	xscrub.mu.Lock()
+	xscrub.stats.Pass, xscrub.stats.Policy, xscrub.stats.Resumed = pass, policy, resumed
+	xscrub.mu.Unlock()
+}
+
+func (xscrub *xactScrub) checked(size int64) {
+	xscrub.mu.Lock()
+	xscrub.stats.Checked++
+	xscrub.mu.Unlock()
+	xscrub.ObjectsAdd(1)
+	xscrub.BytesAdd(size)
+}
+
+func (xscrub *xactScrub) found(typ, fqn, action, detail string) {
+	finding := cmn.ScrubFinding{Time: time.Now(), Type: typ, FQN: fqn, Action: action, Detail: detail}
+	xscrub.mu.Lock()
+	switch typ {
+	case cmn.ScrubCorrupted:
+		xscrub.stats.Corrupted++
+	case cmn.ScrubMisplaced:
+		xscrub.stats.Misplaced++
+	case cmn.ScrubWorkfile:
+		xscrub.stats.Workfiles++
+	case cmn.ScrubECSlice:
+		xscrub.stats.ECSlices++
+	case cmn.ScrubDanglingCopy:
+		xscrub.stats.Copies++
+	}
+	switch action {
+	case cmn.ScrubActRepaired:
+		xscrub.stats.Repaired++
+	case cmn.ScrubActQuarantined:
+		xscrub.stats.Quarantined++
+	case cmn.ScrubActRemoved:
+		xscrub.stats.Removed++
+	}
+	if len(xscrub.stats.Findings) >= maxScrubFindings {
+		copy(xscrub.stats.Findings, xscrub.stats.Findings[1:])
+		xscrub.stats.Findings = xscrub.stats.Findings[:maxScrubFindings-1]
+	}
+	xscrub.stats.Findings = append(xscrub.stats.Findings, finding)
+	xscrub.mu.Unlock()
+
+	if detail != "" {
+		glog.Warningf("%s: %s %s - %s (%s)", xscrub, typ, fqn, action, detail)
+	} else {
+		glog.Warningf("%s: %s %s - %s", xscrub, typ, fqn, action)
+	}
+}
+
+//
+// target
+//
+
+// runScrub scrubs pass after pass while scrubbing is enabled; when started
+// via the xaction API with scrubbing disabled, runs a single pass
+func (t *targetrunner) runScrub() {
+	xscrub := t.xactions.renewScrub()
+	if xscrub == nil {
+		return
+	}
+	glog.Infof("%s started", xscrub)
+	for pass := int64(1); ; pass++ {
+		if !t.scrubPass(xscrub, pass) {
+			glog.Infof("%s aborted (pass %d)", xscrub, pass)
+			return
+		}
+		config := cmn.GCO.Get()
+		if !config.Scrub.Enabled {
+			break
+		}
+		select {
+		case <-xscrub.ChanAbort():
+			return
+		case <-time.After(config.Scrub.Interval):
+		}
+	}
+	xscrub.EndTime(time.Now())
+}
+
+func (t *targetrunner) scrubPass(xscrub *xactScrub, pass int64) (completed bool) {
+	var (
+		ckpt              = loadCheckpoint(filepath.Join(cmn.GCO.Get().Confdir, cmn.ScrubCheckpoint))
+		availablePaths, _ = fs.Mountpaths.Get()
+		_, globRunning    = t.xactions.globalRebStatus()
+		_, localRunning   = t.xactions.localRebStatus()
+		policy            = cmn.GCO.Get().Scrub.Policy
+		started           = time.Now()
+		wg                = &sync.WaitGroup{}
+	)
+	xscrub.newPass(pass, policy, len(ckpt.Paths) > 0)
+	for _, mpathInfo := range availablePaths {
+		wg.Add(1)
+		go func(mpathInfo *fs.MountpathInfo) {
+			j := &scrubJogger{
+				t:         t,
+				xscrub:    xscrub,
+				mpathInfo: mpathInfo,
+				policy:    policy,
+				rebalance: globRunning || localRunning,
+			}
+			j.run(ckpt)
+			wg.Done()
+		}(mpathInfo)
+	}
+	wg.Wait()
+	completed = !xscrub.Aborted()
+	ckpt.stop(completed)
+	if completed {
+		glog.Infof("%s: pass %d completed in %v", xscrub, pass, time.Since(started))
+	}
+	return
+}
+
+//
+// scrubJogger: one per mountpath
+//
+
+func (j *scrubJogger) run(ckpt *rebCheckpoint) {
+	checks := []struct {
+		contentType string
+		check       func(fqn string, fi os.FileInfo)
+	}{
+		{fs.ObjectType, j.checkObject},
+		{fs.WorkfileType, j.checkWorkfile},
+		{ec.SliceType, j.checkSlice},
+	}
+	for _, c := range checks {
+		if _, ok := fs.CSM.RegisteredContentTypes[c.contentType]; !ok {
+			continue // e.g., EC is not initialized
+		}
+		for _, bckIsLocal := range []bool{true, false} {
+			root := j.mpathInfo.MakePath(c.contentType, bckIsLocal)
+			if p, ok := ckpt.get(root); ok && p.Key == scrubDone {
+				continue
+			}
+			cp := ckpt.jogger(root, scrubKey)
+			if err := filepath.Walk(root, resumableWalk(root, cp, j.walk(c.check))); err != nil {
+				if err == errScrubAborted {
+					return
+				}
+				glog.Errorf("%s: failed to traverse %s, err: %v", j.xscrub, root, err)
+				j.xscrub.AddErr(err)
+			}
+			ckpt.set(root, scrubDone, "")
+		}
+	}
+}
+
+func (j *scrubJogger) walk(check func(fqn string, fi os.FileInfo)) filepath.WalkFunc {
+	return func(fqn string, fi os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			glog.Errorf("scrub walk function callback invoked with error: %v", err)
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		select {
+		case <-j.xscrub.ChanAbort():
+			return errScrubAborted
+		default:
+		}
+		governor.Gov.Throttle(j.mpathInfo.Path, governor.PrioLow)
+		check(fqn, fi)
+		return nil
+	}
+}
+
+// orphaned workfile: created by a different (previous) process
+func (j *scrubJogger) checkWorkfile(fqn string, fi os.FileInfo) {
+	if _, info := fs.CSM.FileSpec(fqn); info == nil || !info.Old {
+		return
+	}
+	var (
+		action = cmn.ScrubActReported
+		detail string
+		err    error
+	)
+	switch j.policy {
+	case cmn.ScrubQuarantine:
+		if detail, err = j.quarantine(fqn); err != nil {
+			j.xscrub.AddErr(err)
+			return
+		}
+		action = cmn.ScrubActQuarantined
+	case cmn.ScrubRepair:
+		if err = os.Remove(fqn); err != nil && !os.IsNotExist(err) {
+			j.xscrub.AddErr(err)
+			return
+		}
+		action = cmn.ScrubActRemoved
+	}
+	j.xscrub.found(cmn.ScrubWorkfile, fqn, action, detail)
+}
+
+// EC slice without metafile - neither on the same mountpath nor on the HRW one
+func (j *scrubJogger) checkSlice(fqn string, fi os.FileInfo) {
+	if time.Since(fi.ModTime()) < scrubGrace {
+		return
+	}
+	if _, err := os.Stat(fs.CSM.GenContentFQN(fqn, ec.MetaType, "")); err == nil {
+		return
+	}
+	parsedFQN, err := fs.Mountpaths.FQN2Info(fqn)
+	if err != nil {
+		j.xscrub.AddErr(err)
+		return
+	}
+	metaFQN, errstr := cluster.FQN(ec.MetaType, parsedFQN.Bucket, parsedFQN.Objname, parsedFQN.IsLocal)
+	if errstr != "" {
+		j.xscrub.AddErr(errors.New(errstr))
+		return
+	}
+	if _, err := os.Stat(metaFQN); err == nil {
+		return
+	}
+	var (
+		action = cmn.ScrubActReported
+		detail string
+	)
+	switch j.policy {
+	case cmn.ScrubQuarantine:
+		if detail, err = j.quarantine(fqn); err != nil {
+			j.xscrub.AddErr(err)
+			return
+		}
+		action = cmn.ScrubActQuarantined
+	case cmn.ScrubRepair:
+		if err := os.Remove(fqn); err != nil && !os.IsNotExist(err) {
+			j.xscrub.AddErr(err)
+			return
+		}
+		action = cmn.ScrubActRemoved
+	}
+	j.xscrub.found(cmn.ScrubECSlice, fqn, action, detail)
+}
+
+func (j *scrubJogger) checkObject(fqn string, fi os.FileInfo) {
+	lom := &cluster.LOM{T: j.t, FQN: fqn}
+	if errstr := lom.Fill("", cluster.LomFstat|cluster.LomCopy); errstr != "" {
+		j.xscrub.AddErr(errors.New(errstr))
+		return
+	}
+	if !lom.Exists() {
+		return
+	}
+	if !j.t.rtnamemap.TryLock(lom.Uname, false) {
+		return // busy - next pass
+	}
+	corrupted, dangling := j.inspect(lom)
+	j.t.rtnamemap.Unlock(lom.Uname, false)
+	j.xscrub.checked(lom.Size)
+	j.checkPlacement(lom)
+	if !corrupted && !dangling {
+		return
+	}
+
+	// upgrade the lock and make sure the object is still there and as bad as it was
+	j.t.rtnamemap.Lock(lom.Uname, true)
+	defer j.t.rtnamemap.Unlock(lom.Uname, true)
+	lom = &cluster.LOM{T: j.t, FQN: fqn}
+	if errstr := lom.Fill("", cluster.LomFstat|cluster.LomCopy); errstr != "" || !lom.Exists() {
+		return
+	}
+	if corrupted, dangling = j.inspect(lom); corrupted {
+		j.repairCorrupted(lom)
+	} else if dangling {
+		j.repairDangling(lom)
+	}
+}
+
+// verifies the checksum and the copy's cross-reference (under lock)
+func (j *scrubJogger) inspect(lom *cluster.LOM) (corrupted, dangling bool) {
+	if lom.CksumConf.Type != cmn.ChecksumNone {
+		action := cluster.LomCksum | cluster.LomCksumPresentRecomp
+		if j.policy == cmn.ScrubRepair {
+			action |= cluster.LomCksumMissingRecomp
+		}
+		if errstr := lom.Fill("", action); errstr != "" && !lom.BadCksum {
+			j.xscrub.AddErr(errors.New(errstr))
+		}
+		if lom.BadCksum {
+			return true, false
+		}
+	}
+	if lom.CopyFQN == "" {
+		return
+	}
+	if lom.CopyFQN == lom.FQN {
+		return false, true
+	}
+	if _, err := os.Stat(lom.CopyFQN); err != nil {
+		return false, os.IsNotExist(err)
+	}
+	peer := &cluster.LOM{T: j.t, FQN: lom.CopyFQN}
+	if errstr := peer.Fill("", cluster.LomCopy); errstr != "" {
+		j.xscrub.AddErr(errors.New(errstr))
+		return
+	}
+	return false, peer.CopyFQN != lom.FQN
+}
+
+// misplaced objects are only reported - moving them is rebalance's job
+func (j *scrubJogger) checkPlacement(lom *cluster.LOM) {
+	if j.rebalance || lom.IsCopy() {
+		return
+	}
+	if lom.Misplaced() {
+		j.xscrub.found(cmn.ScrubMisplaced, lom.FQN, cmn.ScrubActReported, "expected "+lom.HrwFQN)
+		return
+	}
+	smap := j.t.smapowner.get()
+	// EC replicas are objects too - stored on the targets that follow the main one
+	if meta, err := ec.LoadMetadata(fs.CSM.GenContentFQN(lom.FQN, ec.MetaType, "")); err == nil {
+		cnt := meta.Parity + 1
+		if !meta.IsCopy {
+			cnt += meta.Data
+		}
+		list, errstr := cluster.HrwTargetList(lom.Bucket, lom.Objname, &smap.Smap, cnt)
+		if errstr == "" && !hrwContains(list, j.t.si.DaemonID) {
+			j.xscrub.found(cmn.ScrubMisplaced, lom.FQN, cmn.ScrubActReported, fmt.Sprintf("expected at one of %v", hrwIDs(list)))
+		}
+		return
+	}
+	si, errstr := cluster.HrwTarget(lom.Bucket, lom.Objname, &smap.Smap)
+	if errstr != "" {
+		return
+	}
+	if si.DaemonID != j.t.si.DaemonID {
+		j.xscrub.found(cmn.ScrubMisplaced, lom.FQN, cmn.ScrubActReported, "expected at "+si.DaemonID)
+	}
+}
+
+func (j *scrubJogger) repairCorrupted(lom *cluster.LOM) {
+	if j.policy == cmn.ScrubReport {
+		j.xscrub.found(cmn.ScrubCorrupted, lom.FQN, cmn.ScrubActReported, "")
+		return
+	}
+	qfqn, err := j.quarantine(lom.FQN)
+	if err != nil {
+		j.xscrub.AddErr(err)
+		return
+	}
+	if j.policy == cmn.ScrubQuarantine {
+		j.xscrub.found(cmn.ScrubCorrupted, lom.FQN, cmn.ScrubActQuarantined, qfqn)
+		return
+	}
+	from, err := j.restore(lom)
+	if err != nil {
+		j.xscrub.found(cmn.ScrubCorrupted, lom.FQN, cmn.ScrubActQuarantined,
+			fmt.Sprintf("%s, failed to restore: %v", qfqn, err))
+		return
+	}
+	j.xscrub.found(cmn.ScrubCorrupted, lom.FQN, cmn.ScrubActRepaired, "restored from "+from)
+}
+
+// restore (the quarantined) object from its mirror copy, EC, or the Cloud - in that order
+func (j *scrubJogger) restore(lom *cluster.LOM) (from string, err error) {
+	if lom.CopyFQN != "" {
+		if err = j.restoreMirror(lom); err == nil {
+			return "mirror", nil
+		}
+		glog.Warningf("%s: failed to restore %s from mirror, err: %v", j.xscrub, lom, err)
+	}
+	if lom.IsCopy() {
+		return "", errors.New("no intact object to copy from")
+	}
+	if lom.BckProps != nil && lom.BckProps.EC.Enabled {
+		if err = j.t.ecmanager.RestoreObject(lom); err == nil {
+			return "EC", nil
+		}
+		glog.Warningf("%s: failed to restore %s from EC, err: %v", j.xscrub, lom, err)
+	}
+	if lom.BckIsLocal {
+		if err == nil {
+			err = errors.New("no redundancy")
+		}
+		return
+	}
+	if err = j.restoreCloud(lom); err == nil {
+		return "Cloud", nil
+	}
+	return
+}
+
+func (j *scrubJogger) restoreMirror(lom *cluster.LOM) error {
+	peer := &cluster.LOM{T: j.t, FQN: lom.CopyFQN}
+	if errstr := peer.Fill("", cluster.LomFstat|cluster.LomVersion|cluster.LomCksum|cluster.LomCksumPresentRecomp); errstr != "" {
+		return errors.New(errstr)
+	}
+	if !peer.Exists() || peer.BadCksum {
+		return fmt.Errorf("%s is missing or corrupted", peer)
+	}
+	slab := gmem2.SelectSlab2(peer.Size)
+	buf := slab.Alloc()
+	defer slab.Free(buf)
+	if err := peer.CopyObject(lom.FQN, buf); err != nil {
+		return err
+	}
+	if errstr := lom.SetXcopy(peer.FQN); errstr != "" {
+		return errors.New(errstr)
+	}
+	return nil
+}
+
+// (cold GET under the scrubber's lock)
+func (j *scrubJogger) restoreCloud(lom *cluster.LOM) error {
+	workFQN := lom.GenFQN(fs.WorkfileType, fs.WorkfileColdget)
+	props, errstr, _ := getcloudif().getobj(context.Background(), workFQN, lom.Bucket, lom.Objname)
+	if errstr != "" {
+		return errors.New(errstr)
+	}
+	if err := cmn.MvFile(workFQN, lom.FQN); err != nil {
+		if errRemove := os.Remove(workFQN); errRemove != nil && !os.IsNotExist(errRemove) {
+			glog.Errorf("Nested error %v => (remove %s => err: %v)", err, workFQN, errRemove)
+		}
+		return err
+	}
+	lom.RestoredReceived(props)
+	if errstr := lom.Persist(); errstr != "" {
+		return errors.New(errstr)
+	}
+	return nil
+}
+
+// the object references a copy that does not exist (or does not reference it back);
+// the copy references an object that does not exist (or references some other copy)
+func (j *scrubJogger) repairDangling(lom *cluster.LOM) {
+	var (
+		action = cmn.ScrubActReported
+		detail = "copy " + lom.CopyFQN
+		err    error
+	)
+	switch {
+	case j.policy == cmn.ScrubReport:
+	case !lom.IsCopy():
+		detail = "dropped reference to " + lom.CopyFQN
+		if errstr := lom.DelXcopy(); errstr != "" {
+			j.xscrub.AddErr(errors.New(errstr))
+			return
+		}
+		action = cmn.ScrubActRepaired
+		if j.policy == cmn.ScrubRepair {
+			j.t.localMirror(lom) // (if enabled)
+		}
+	case j.policy == cmn.ScrubQuarantine:
+		if detail, err = j.quarantine(lom.FQN); err != nil {
+			j.xscrub.AddErr(err)
+			return
+		}
+		action = cmn.ScrubActQuarantined
+	default:
+		if _, err = os.Stat(lom.HrwFQN); os.IsNotExist(err) {
+			// the only (and verified) replica: promote
+			if err = cmn.MvFile(lom.FQN, lom.HrwFQN); err != nil {
+				j.xscrub.AddErr(err)
+				return
+			}
+			promoted := &cluster.LOM{T: j.t, FQN: lom.HrwFQN}
+			if errstr := promoted.DelXcopy(); errstr != "" {
+				j.xscrub.AddErr(errors.New(errstr))
+			}
+			action, detail = cmn.ScrubActRepaired, "restored "+lom.HrwFQN
+		} else {
+			if err = os.Remove(lom.FQN); err != nil && !os.IsNotExist(err) {
+				j.xscrub.AddErr(err)
+				return
+			}
+			action = cmn.ScrubActRemoved
+		}
+	}
+	j.xscrub.found(cmn.ScrubDanglingCopy, lom.FQN, action, detail)
+}
+
+// moves the file aside, to the quarantine on the same mountpath
+func (j *scrubJogger) quarantine(fqn string) (qfqn string, err error) {
+	parsedFQN, err := fs.Mountpaths.FQN2Info(fqn)
+	if err != nil {
+		return
+	}
+	qfqn = fs.CSM.GenContentParsedFQN(parsedFQN, fs.QuarantineType, "")
+	err = cmn.MvFile(fqn, qfqn)
+	return
+}
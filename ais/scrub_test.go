@@ -0,0 +1,283 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/ec"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/memsys"
+)
+
+const scrubBucket = "scrub-bucket"
+
+var scrubPolicies = []string{cmn.ScrubReport, cmn.ScrubQuarantine, cmn.ScrubRepair}
+
+// two mountpaths (to mirror) and a local bucket with checksumming
+func newScrubTarget(t *testing.T) (tgt *targetrunner, cleanup func()) {
+	root, err := ioutil.TempDir("", "scrub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs.Mountpaths = fs.NewMountedFS()
+	fs.Mountpaths.DisableFsIDCheck()
+	for _, mpath := range []string{filepath.Join(root, "1"), filepath.Join(root, "2")} {
+		if err := cmn.CreateDir(mpath); err != nil {
+			t.Fatal(err)
+		}
+		if err := fs.Mountpaths.Add(mpath); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fs.CSM.RegisterFileType(fs.ObjectType, &fs.ObjectContentResolver{})
+	fs.CSM.RegisterFileType(fs.WorkfileType, &fs.WorkfileContentResolver{})
+	fs.CSM.RegisterFileType(fs.QuarantineType, &fs.QuarantineContentResolver{})
+	fs.CSM.RegisterFileType(ec.SliceType, &ec.SliceSpec{})
+	fs.CSM.RegisterFileType(ec.MetaType, &ec.MetaSpec{})
+	if gmem2 == nil {
+		gmem2 = &memsys.Mem2{Name: "scrubtest"}
+		if err := gmem2.Init(false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tgt = newFakeTargetRunner()
+	tgt.rtnamemap = newrtnamemap()
+	tgt.bmdowner = &bmdowner{}
+	bmd := newBucketMD()
+	bmd.add(scrubBucket, true, &cmn.BucketProps{Cksum: cmn.CksumConf{Type: cmn.ChecksumXXHash}})
+	tgt.bmdowner.put(bmd)
+	return tgt, func() { os.RemoveAll(root) }
+}
+
+func newScrubJogger(tgt *targetrunner, policy string) *scrubJogger {
+	// (placement is not checked while rebalancing)
+	return &scrubJogger{t: tgt, xscrub: &xactScrub{}, policy: policy, rebalance: true}
+}
+
+// puts a checksummed object and, optionally, its mirrored copy; returns the object
+func scrubPut(t *testing.T, tgt *targetrunner, objname string, data []byte, mirror bool) *cluster.LOM {
+	lom := &cluster.LOM{T: tgt, Bucket: scrubBucket, Objname: objname}
+	if errstr := lom.Fill("", 0); errstr != "" {
+		t.Fatal(errstr)
+	}
+	if err := cmn.CreateDir(filepath.Dir(lom.FQN)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(lom.FQN, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if errstr := lom.Fill("", cluster.LomFstat|cluster.LomCksum|cluster.LomCksumMissingRecomp); errstr != "" {
+		t.Fatal(errstr)
+	}
+	if !mirror {
+		return lom
+	}
+	availablePaths, _ := fs.Mountpaths.Get()
+	for _, mpathInfo := range availablePaths {
+		if mpathInfo.Path == lom.ParsedFQN.MpathInfo.Path {
+			continue
+		}
+		copyFQN := fs.CSM.FQN(mpathInfo, fs.ObjectType, true, scrubBucket, objname)
+		if err := lom.CopyObject(copyFQN, make([]byte, cmn.KiB)); err != nil {
+			t.Fatal(err)
+		}
+		if errstr := lom.SetXcopy(copyFQN); errstr != "" {
+			t.Fatal(errstr)
+		}
+		break
+	}
+	return lom
+}
+
+func scrubCheck(t *testing.T, j *scrubJogger, check func(fqn string, fi os.FileInfo), fqn string) {
+	fi, err := os.Stat(fqn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(fqn, fi)
+	if stats := j.xscrub.XactBase.Snapshot(); stats.ErrCnt != 0 {
+		t.Fatalf("%s: unexpected error: %s", j.policy, stats.LastErr)
+	}
+}
+
+func exists(fqn string) bool {
+	_, err := os.Stat(fqn)
+	return err == nil
+}
+
+func TestScrubCorruptedWithMirror(t *testing.T) {
+	tgt, cleanup := newScrubTarget(t)
+	defer cleanup()
+	data := []byte("the quick brown fox jumps over the lazy dog")
+	for _, policy := range scrubPolicies {
+		lom := scrubPut(t, tgt, "corrupted-"+policy, data, true /*mirror*/)
+		if err := ioutil.WriteFile(lom.FQN, bytes.ToUpper(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		j := newScrubJogger(tgt, policy)
+		scrubCheck(t, j, j.checkObject, lom.FQN)
+
+		stats := j.xscrub.stats
+		if stats.Corrupted != 1 || len(stats.Findings) != 1 {
+			t.Fatalf("%s: expecting a single corrupted object, got %+v", policy, stats)
+		}
+		content, _ := ioutil.ReadFile(lom.FQN)
+		switch policy {
+		case cmn.ScrubReport:
+			if !bytes.Equal(content, bytes.ToUpper(data)) {
+				t.Errorf("%s: the object must stay as is", policy)
+			}
+		case cmn.ScrubQuarantine:
+			if exists(lom.FQN) || stats.Quarantined != 1 {
+				t.Errorf("%s: expecting the object quarantined, %+v", policy, stats)
+			}
+		case cmn.ScrubRepair:
+			if !bytes.Equal(content, data) || stats.Repaired != 1 || stats.Findings[0].Detail != "restored from mirror" {
+				t.Errorf("%s: expecting the object restored from the mirror, %+v", policy, stats)
+			}
+			restored := &cluster.LOM{T: tgt, FQN: lom.FQN}
+			if errstr := restored.Fill("", cluster.LomFstat|cluster.LomCopy); errstr != "" || !restored.HasCopy() {
+				t.Errorf("%s: expecting the restored object to have its copy (%s)", policy, errstr)
+			}
+		}
+	}
+}
+
+func TestScrubDanglingCopy(t *testing.T) {
+	tgt, cleanup := newScrubTarget(t)
+	defer cleanup()
+	data := []byte("dangling")
+
+	// the object references a copy that does not exist
+	for _, policy := range scrubPolicies {
+		lom := scrubPut(t, tgt, "noref-"+policy, data, true /*mirror*/)
+		if err := os.Remove(lom.CopyFQN); err != nil {
+			t.Fatal(err)
+		}
+		j := newScrubJogger(tgt, policy)
+		scrubCheck(t, j, j.checkObject, lom.FQN)
+		if j.xscrub.stats.Copies != 1 {
+			t.Fatalf("%s: expecting a single dangling copy, got %+v", policy, j.xscrub.stats)
+		}
+		check := &cluster.LOM{T: tgt, FQN: lom.FQN}
+		if errstr := check.Fill("", cluster.LomFstat|cluster.LomCopy); errstr != "" {
+			t.Fatal(errstr)
+		}
+		if dropped := check.CopyFQN == ""; dropped != (policy != cmn.ScrubReport) {
+			t.Errorf("%s: unexpected reference to the copy %q", policy, check.CopyFQN)
+		}
+	}
+
+	// the copy of an object that does not exist
+	for _, policy := range scrubPolicies {
+		lom := scrubPut(t, tgt, "nobj-"+policy, data, true /*mirror*/)
+		copyFQN := lom.CopyFQN
+		if err := os.Remove(lom.FQN); err != nil {
+			t.Fatal(err)
+		}
+		j := newScrubJogger(tgt, policy)
+		scrubCheck(t, j, j.checkObject, copyFQN)
+		stats := j.xscrub.stats
+		if stats.Copies != 1 {
+			t.Fatalf("%s: expecting a single dangling copy, got %+v", policy, stats)
+		}
+		switch policy {
+		case cmn.ScrubReport:
+			if !exists(copyFQN) || exists(lom.FQN) {
+				t.Errorf("%s: the copy must stay as is", policy)
+			}
+		case cmn.ScrubQuarantine:
+			if exists(copyFQN) || stats.Quarantined != 1 {
+				t.Errorf("%s: expecting the copy quarantined, %+v", policy, stats)
+			}
+		case cmn.ScrubRepair:
+			content, _ := ioutil.ReadFile(lom.FQN)
+			if exists(copyFQN) || !bytes.Equal(content, data) || stats.Repaired != 1 {
+				t.Errorf("%s: expecting the copy promoted to the object, %+v", policy, stats)
+			}
+		}
+	}
+}
+
+func TestScrubSlice(t *testing.T) {
+	tgt, cleanup := newScrubTarget(t)
+	defer cleanup()
+	old := time.Now().Add(-2 * scrubGrace)
+	for _, policy := range scrubPolicies {
+		objname := "slice-" + policy
+		sliceFQN, errstr := cluster.FQN(ec.SliceType, scrubBucket, objname, true)
+		if errstr != "" {
+			t.Fatal(errstr)
+		}
+		if err := cmn.CreateDir(filepath.Dir(sliceFQN)); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(sliceFQN, []byte("slice"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		// young slices may still be waiting for their metafiles
+		j := newScrubJogger(tgt, policy)
+		scrubCheck(t, j, j.checkSlice, sliceFQN)
+		if len(j.xscrub.stats.Findings) != 0 {
+			t.Fatalf("%s: unexpected findings %+v", policy, j.xscrub.stats)
+		}
+		if err := os.Chtimes(sliceFQN, old, old); err != nil {
+			t.Fatal(err)
+		}
+		scrubCheck(t, j, j.checkSlice, sliceFQN)
+		stats := j.xscrub.stats
+		if stats.ECSlices != 1 {
+			t.Fatalf("%s: expecting a single orphaned slice, got %+v", policy, stats)
+		}
+		if stay := exists(sliceFQN); stay != (policy == cmn.ScrubReport) {
+			t.Errorf("%s: the slice exists: %t", policy, stay)
+		}
+		if (policy == cmn.ScrubQuarantine && stats.Quarantined != 1) || (policy == cmn.ScrubRepair && stats.Removed != 1) {
+			t.Errorf("%s: unexpected %+v", policy, stats)
+		}
+	}
+}
+
+func TestScrubWorkfile(t *testing.T) {
+	tgt, cleanup := newScrubTarget(t)
+	defer cleanup()
+	for _, policy := range scrubPolicies {
+		lom := scrubPut(t, tgt, "work-"+policy, []byte("work"), false)
+		workFQN := lom.GenFQN(fs.WorkfileType, fs.WorkfilePut)
+		// created by a different process
+		oldFQN := workFQN[:strings.LastIndex(workFQN, ".")+1] + fmt.Sprintf("%x", os.Getpid()+1)
+		for _, fqn := range []string{workFQN, oldFQN} {
+			if err := cmn.CreateDir(filepath.Dir(fqn)); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(fqn, nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		j := newScrubJogger(tgt, policy)
+		scrubCheck(t, j, j.checkWorkfile, workFQN)
+		scrubCheck(t, j, j.checkWorkfile, oldFQN)
+		stats := j.xscrub.stats
+		if stats.Workfiles != 1 || stats.Findings[0].FQN != oldFQN {
+			t.Fatalf("%s: expecting a single orphaned workfile, got %+v", policy, stats)
+		}
+		if !exists(workFQN) {
+			t.Errorf("%s: the workfile of this process must stay", policy)
+		}
+		if stay := exists(oldFQN); stay != (policy == cmn.ScrubReport) {
+			t.Errorf("%s: the orphaned workfile exists: %t", policy, stay)
+		}
+	}
+}
@@ -54,11 +54,14 @@ cat > $CONFFILE <<EOL
 	},
 	"xaction":{
 	    "disk_util_low_wm":  20,
-	    "disk_util_high_wm": 80
+	    "disk_util_high_wm": 80,
+	    "bandwidth":         "0"
 	},
 	"rebalance": {
 		"enabled":         true,
-		"dest_retry_time": "2m"
+		"dest_retry_time": "2m",
+		"bandwidth":       "0",
+		"disk_util_max":   0
 	},
 	"cksum": {
 		"type":                       "xxhash",
@@ -104,6 +107,24 @@ cat > $CONFFILE <<EOL
 		"test_files":  4,
 		"error_limit": 2
 	},
+	"cloud": {
+		"max_retries":       3,
+		"retry_backoff":     "200ms",
+		"retry_backoff_max": "10s",
+		"breaker_threshold": 10,
+		"breaker_timeout":   "30s",
+		"neg_cache_ttl":     "30s",
+		"neg_cache_max":     65536
+	},
+	"scrub": {
+		"enabled":  false,
+		"policy":   "report",
+		"interval": "1h"
+	},
+	"gc": {
+		"interval": "1h",
+		"max_age":  "24h"
+	},
 	"auth": {
 		"secret":  "$SECRETKEY",
 		"enabled": ${AUTHENABLED:-false},
@@ -111,17 +132,20 @@ cat > $CONFFILE <<EOL
 	},
 	"keepalivetracker": {
 		"proxy": {
-			"interval": "10s",
-			"name":     "heartbeat",
-			"factor":   3
+			"interval":  "10s",
+			"name":      "heartbeat",
+			"factor":    3,
+			"threshold": 8
 		},
 		"target": {
-			"interval": "10s",
-			"name":     "heartbeat",
-			"factor":   3
+			"interval":  "10s",
+			"name":      "heartbeat",
+			"factor":    3,
+			"threshold": 8
 		},
 		"retry_factor":   5,
-		"timeout_factor": 3
+		"timeout_factor": 3,
+		"suspect_time":   "20s"
 	}
 }
 EOL
@@ -29,7 +29,8 @@ func (se *signalError) Error() string {
 //===========================================================================
 type sigrunner struct {
 	cmn.Named
-	chsig chan os.Signal
+	chsig  chan os.Signal
+	reload func() // SIGHUP: reload the configuration file (terminate if not set)
 }
 
 // signal handler
@@ -41,6 +42,10 @@ func (r *sigrunner) Run() error {
 		syscall.SIGTERM,
 		syscall.SIGQUIT)
 	s := <-r.chsig
+	for s == syscall.SIGHUP && r.reload != nil { // kill -SIGHUP XXXX
+		r.reload()
+		s = <-r.chsig
+	}
 	signal.Stop(r.chsig) // stop immediately
 	switch s {
 	case syscall.SIGHUP: // kill -SIGHUP XXXX
@@ -33,6 +33,7 @@ import (
 	"github.com/NVIDIA/aistore/dsort"
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/governor"
 	"github.com/NVIDIA/aistore/ios"
 	"github.com/NVIDIA/aistore/lru"
 	"github.com/NVIDIA/aistore/memsys"
@@ -117,6 +118,9 @@ type (
 
 		// User specified parameter to determine which bucket to put object
 		bckProvider string
+		// Number of AIStore tiers the object has traversed (non-zero when
+		// the object is being written by the previous tier).
+		tierHops int
 
 		// INTERNAL
 
@@ -134,7 +138,8 @@ type (
 
 	targetrunner struct {
 		httprunner
-		cloudif        cloudif // multi-cloud backend
+		cloudif        cloudif     // multi-cloud backend
+		cloudcache     *cloudCache // wraps the backend: negative caching, request coalescing, retries
 		uxprocess      *uxprocess
 		rtnamemap      *rtnamemap
 		prefetchQueue  chan filesWithDeadline
@@ -147,7 +152,8 @@ type (
 		ecmanager      *ecManager
 		rebManager     *rebManager
 		gfn            getFromNeighbors
-		regstate       regstate // the state of being registered with the primary (can be en/disabled via API)
+		regstate       regstate      // the state of being registered with the primary (can be en/disabled via API)
+		gcStopCh       chan struct{} // stops the periodic garbage collection (see gc.go)
 	}
 )
 
@@ -169,6 +175,15 @@ func (t *targetrunner) Run() error {
 	bucketmd := newBucketMD()
 	t.bmdowner.put(bucketmd)
 
+	if t.si.Weight, t.si.WeightCap, ereg = declaredWeight(clivars.weight); ereg != nil {
+		glog.Error(ereg)
+		return ereg
+	}
+	if t.si.Labels, ereg = declaredLabels(clivars.labels); ereg != nil {
+		glog.Error(ereg)
+		return ereg
+	}
+
 	smap := newSmap()
 	smap.Tmap[t.si.DaemonID] = t.si
 	t.smapowner.put(smap)
@@ -201,6 +216,10 @@ func (t *targetrunner) Run() error {
 		glog.Error(err)
 		os.Exit(1)
 	}
+	if err := fs.CSM.RegisterFileType(fs.QuarantineType, &fs.QuarantineContentResolver{}); err != nil {
+		glog.Error(err)
+		os.Exit(1)
+	}
 
 	if err := fs.Mountpaths.CreateBucketDir(cmn.LocalBs); err != nil {
 		glog.Error(err)
@@ -213,13 +232,18 @@ func (t *targetrunner) Run() error {
 	t.detectMpathChanges()
 
 	// cloud provider (empty stubs that may get populated via build tags)
+	var provider cloudif
 	if config.CloudProvider == cmn.ProviderAmazon {
-		t.cloudif = newAWSProvider(t)
+		provider = newAWSProvider(t)
 	} else if config.CloudProvider == cmn.ProviderGoogle {
-		t.cloudif = newGCPProvider(t)
+		provider = newGCPProvider(t)
 	} else {
-		t.cloudif = newEmptyCloud() // mock
+		provider = newEmptyCloud() // mock
 	}
+	cloudRetry := newCloudRetry(provider, config.CloudProvider, t.statsif)
+	t.cloudcache = newCloudCache(cloudRetry)
+	t.cloudif = t.cloudcache
+	getstorstatsrunner().CloudBreaker = cloudRetry.breakerStats
 
 	// prefetch
 	t.prefetchQueue = make(chan filesWithDeadline, prefetchChanSize)
@@ -252,6 +276,7 @@ func (t *targetrunner) Run() error {
 		networkHandler{r: cmn.Health, h: t.healthHandler, net: []string{cmn.NetworkIntraControl}},
 		networkHandler{r: cmn.Vote, h: t.voteHandler, net: []string{cmn.NetworkIntraControl}},
 		networkHandler{r: cmn.Sort, h: dsort.SortHandler, net: []string{cmn.NetworkIntraControl, cmn.NetworkIntraData}},
+		networkHandler{r: cmn.Xactions, h: t.xactHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl}},
 
 		networkHandler{r: "/", h: cmn.InvalidHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl, cmn.NetworkIntraData}},
 	}
@@ -266,6 +291,9 @@ func (t *targetrunner) Run() error {
 	t.uxprocess = &uxprocess{time.Now(), strconv.FormatInt(pid, 16), pid}
 
 	getfshealthchecker().SetDispatcher(t)
+	t.xactions.init(config.Confdir)
+	governor.Gov.SetActive(t.xactions.activeByPrio)
+	t.confowner.init(config.Confdir, t.si.DaemonID, t.setConfig)
 
 	ec.Init()
 	t.ecmanager = newECM(t)
@@ -277,8 +305,13 @@ func (t *targetrunner) Run() error {
 			t.rebManager.runLocalReb()
 		}()
 	}
+	if cmn.GCO.Get().Scrub.Enabled {
+		go t.runScrub() // (resumes the interrupted pass, if any)
+	}
 
 	dsort.RegisterNode(t.smapowner, t.si, t, t.rtnamemap)
+	t.gcStopCh = make(chan struct{})
+	go t.runGCLoop(t.gcStopCh) // (after all content types are registered)
 	if err := t.httprunner.run(); err != nil {
 		return err
 	}
@@ -299,7 +332,11 @@ func (t *targetrunner) setupRebalanceManager() error {
 
 	t.rebManager = &rebManager{
 		t: t,
+		limiter: transport.NewLimiter(cmn.GCO.Get().Rebalance.Bandwidth, func(time.Duration) {
+			t.statsif.Add(stats.RebThrottleNetCount, 1)
+		}),
 	}
+	t.rebManager.limiter.Chain(governor.Gov.NetLimiter(governor.PrioHigh)) // (aggregate for all xactions)
 
 	if _, err := transport.Register(network, "rebalance", t.rebManager.recvRebalanceObj); err != nil {
 		return err
@@ -313,6 +350,7 @@ func (t *targetrunner) setupRebalanceManager() error {
 		Multiplier:   4,
 		Network:      network,
 		Trname:       rebalanceStreamName,
+		Extra:        &transport.Extra{Limiter: t.rebManager.limiter}, // (shared by all rebalance streams)
 	}
 
 	t.rebManager.streams = transport.NewStreamBundle(t.smapowner, t.si, client, sbArgs)
@@ -327,6 +365,10 @@ func (t *targetrunner) registerStats() {
 	t.statsif.Register(stats.GetThroughput, stats.KindThroughput)
 	t.statsif.Register(stats.LruEvictSize, stats.KindCounter)
 	t.statsif.Register(stats.LruEvictCount, stats.KindCounter)
+	t.statsif.Register(stats.GCSize, stats.KindCounter)
+	t.statsif.Register(stats.GCCount, stats.KindCounter)
+	t.statsif.Register(stats.LruDemoteSize, stats.KindCounter)
+	t.statsif.Register(stats.LruDemoteCount, stats.KindCounter)
 	t.statsif.Register(stats.TxCount, stats.KindCounter)
 	t.statsif.Register(stats.TxSize, stats.KindCounter)
 	t.statsif.Register(stats.RxCount, stats.KindCounter)
@@ -343,6 +385,8 @@ func (t *targetrunner) registerStats() {
 	// rebalance
 	t.statsif.Register(stats.RebGlobalCount, stats.KindCounter)
 	t.statsif.Register(stats.RebLocalCount, stats.KindCounter)
+	t.statsif.Register(stats.RebThrottleNetCount, stats.KindCounter)
+	t.statsif.Register(stats.RebThrottleDiskCount, stats.KindCounter)
 	t.statsif.Register(stats.RebGlobalSize, stats.KindCounter)
 	t.statsif.Register(stats.RebLocalSize, stats.KindCounter)
 	// replication
@@ -351,12 +395,20 @@ func (t *targetrunner) registerStats() {
 	// download
 	t.statsif.Register(stats.DownloadSize, stats.KindCounter)
 	t.statsif.Register(stats.DownloadLatency, stats.KindLatency)
+	// cloud
+	t.statsif.Register(stats.CloudRetryCount, stats.KindCounter)
+	t.statsif.Register(stats.CloudBreakerTripCount, stats.KindCounter)
+	t.statsif.Register(stats.CloudBreakerRejectCount, stats.KindCounter)
 }
 
 // stop gracefully
 func (t *targetrunner) Stop(err error) {
 	glog.Infof("Stopping %s, err: %v", t.Getname(), err)
+	if t.gcStopCh != nil {
+		close(t.gcStopCh)
+	}
 	sleep := t.xactions.abortAll()
+	t.xactions.flush()
 	if t.publicServer.s != nil {
 		t.unregister() // ignore errors
 	}
@@ -481,6 +533,83 @@ func (t *targetrunner) RunLRU() {
 	xlru.EndTime(time.Now())
 }
 
+// declaredWeight returns the target's placement weight as per the -weight
+// command line: either a positive number or "capacity" - the total capacity of the
+// target's filesystems in GiB; zero (default) means same weight for all targets.
+// The two are not comparable - the proxy won't register targets that mix them.
+func declaredWeight(s string) (weight int64, capacity bool, err error) {
+	if s == "" {
+		return
+	}
+	if s != "capacity" {
+		weight, err = strconv.ParseInt(s, 10, 64)
+		if err != nil || weight <= 0 {
+			return 0, false, fmt.Errorf("invalid weight %q, err: %v", s, err)
+		}
+		return
+	}
+	var (
+		total             uint64
+		filesystems       = make(map[string]struct{})
+		availablePaths, _ = fs.Mountpaths.Get()
+	)
+	for _, mpathInfo := range availablePaths {
+		if _, ok := filesystems[mpathInfo.FileSystem]; ok {
+			continue
+		}
+		filesystems[mpathInfo.FileSystem] = struct{}{}
+		statfs := syscall.Statfs_t{}
+		if err = syscall.Statfs(mpathInfo.Path, &statfs); err != nil {
+			return 0, false, fmt.Errorf("failed to statfs %s, err: %v", mpathInfo.Path, err)
+		}
+		total += statfs.Blocks * uint64(statfs.Bsize)
+	}
+	if weight = int64(total / cmn.GiB); weight == 0 {
+		weight = 1
+	}
+	return weight, true, nil
+}
+
+// declaredLabels parses the -labels command line: comma-separated
+// zone=...,rack=...,host=... (any subset, in any order)
+func declaredLabels(s string) (labels cluster.NodeLabels, err error) {
+	if s == "" {
+		return
+	}
+	for _, kv := range strings.Split(s, ",") {
+		pair := strings.SplitN(strings.TrimSpace(kv), "=", 2)
+		if len(pair) != 2 || strings.TrimSpace(pair[1]) == "" {
+			return labels, fmt.Errorf("invalid label %q in %q (expecting <domain>=<value>)", kv, s)
+		}
+		value := strings.TrimSpace(pair[1])
+		switch strings.TrimSpace(pair[0]) {
+		case cluster.DomainZone:
+			labels.Zone = value
+		case cluster.DomainRack:
+			labels.Rack = value
+		case cluster.DomainHost:
+			labels.Host = value
+		default:
+			return labels, fmt.Errorf("invalid label %q in %q (expecting one of: %s, %s, %s)",
+				kv, s, cluster.DomainZone, cluster.DomainRack, cluster.DomainHost)
+		}
+	}
+	return
+}
+
+// placementPolicy returns the bucket's EC placement policy as per the current
+// cluster map (see cmn.HeaderBucketPlacement)
+func placementPolicy(props *cmn.BucketProps, smap *smapX) string {
+	if !props.EC.Enabled {
+		return cmn.PlacementHRW
+	}
+	domain, _ := smap.FailureDomain()
+	if domain == "" {
+		return cmn.PlacementHRW
+	}
+	return cmn.PlacementSpread + ":" + domain
+}
+
 func (t *targetrunner) PrefetchQueueLen() int { return len(t.prefetchQueue) }
 
 func (t *targetrunner) Prefetch() {
@@ -500,8 +629,10 @@ loop:
 			if bckIsLocal {
 				glog.Errorf("prefetch: bucket %s is local, nothing to do", fwd.bucket)
 			} else {
+				xpre.TotalAdd(int64(len(fwd.objnames)))
 				for _, objname := range fwd.objnames {
 					t.prefetchMissing(fwd.ctx, objname, fwd.bucket, fwd.bckProvider)
+					xpre.ObjectsAdd(1)
 				}
 			}
 			// Signal completion of prefetch
@@ -522,6 +653,7 @@ func (t *targetrunner) FSHC(err error, path string)   { t.fshc(err, path) }
 func (t *targetrunner) GetAtimeRunner() *atime.Runner { return getatimerunner() }
 func (t *targetrunner) GetMem2() *memsys.Mem2         { return gmem2 }
 func (t *targetrunner) GetFSPRG() fs.PathRunGroup     { return &t.fsprg }
+func (t *targetrunner) Demote(lom *cluster.LOM) error { return t.demote(lom) }
 
 func (t *targetrunner) Receive(workFQN string, reader io.ReadCloser, lom *cluster.LOM) error {
 	roi := &recvObjInfo{
@@ -640,6 +772,7 @@ func (t *targetrunner) httpobjget(w http.ResponseWriter, r *http.Request) {
 	if redirDelta := t.redirectLatency(started, query); redirDelta != 0 {
 		t.statsif.Add(stats.GetRedirLatency, redirDelta)
 	}
+	hops := tierHops(query)
 
 	rangeOff, rangeLen, errstr := t.offsetAndLength(query)
 	if errstr != "" {
@@ -667,7 +800,10 @@ func (t *targetrunner) httpobjget(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 2. under lock: versioning, checksum, restore from cluster
-	t.rtnamemap.Lock(lom.Uname, false)
+	if errstr, errcode = t.lockObj(r.Context(), lom, false); errstr != "" {
+		t.invalmsghdlr(w, r, errstr, errcode)
+		return
+	}
 	coldGet := !lom.Exists()
 
 	if !coldGet {
@@ -685,7 +821,7 @@ func (t *targetrunner) httpobjget(w http.ResponseWriter, r *http.Request) {
 	// check if dryrun is enabled to stop from getting LOM
 	if coldGet && lom.BckIsLocal && !dryRun.disk && !dryRun.network {
 		// does not exist in the local bucket: restore from neighbors
-		if errstr, errcode = t.restoreObjLBNeigh(lom, r, started); errstr != "" {
+		if errstr, errcode = t.restoreObjLBNeigh(lom, r, started, hops); errstr != "" {
 			t.rtnamemap.Unlock(lom.Uname, false)
 			t.invalmsghdlr(w, r, errstr, errcode)
 			return
@@ -729,7 +865,7 @@ func (t *targetrunner) httpobjget(w http.ResponseWriter, r *http.Request) {
 	// 3. coldget
 	if coldGet && !dryRun.disk && !dryRun.network {
 		t.rtnamemap.Unlock(lom.Uname, false)
-		if errstr, errcode := t.getCold(ct, lom, false); errstr != "" {
+		if errstr, errcode := t.getCold(ct, lom, false, hops); errstr != "" {
 			t.invalmsghdlr(w, r, errstr, errcode)
 			return
 		}
@@ -746,7 +882,7 @@ func (t *targetrunner) httpobjget(w http.ResponseWriter, r *http.Request) {
 //		targets using erasure coding(if it is on)
 // FIXME: must be done => (getfqn, and under write lock)
 //
-func (t *targetrunner) restoreObjLBNeigh(lom *cluster.LOM, r *http.Request, started time.Time) (errstr string, errcode int) {
+func (t *targetrunner) restoreObjLBNeigh(lom *cluster.LOM, r *http.Request, started time.Time, hops int) (errstr string, errcode int) {
 	bckProvider := r.URL.Query().Get(cmn.URLParamBckProvider)
 	// check FS-wide if local rebalance is running
 	aborted, running := t.xactions.localRebStatus()
@@ -789,7 +925,10 @@ func (t *targetrunner) restoreObjLBNeigh(lom *cluster.LOM, r *http.Request, star
 			}
 			return
 		}
-	} else if t.getFromTier(lom) {
+	}
+	// promote from the next tier (e.g., demoted by LRU)
+	var restored bool
+	if restored, errstr, errcode = t.getFromTier(lom, hops); restored {
 		if glog.FastV(4, glog.SmoduleAIS) {
 			glog.Infof("restored from tier: %s (%s)", lom, cmn.B2S(lom.Size, 1))
 		}
@@ -805,7 +944,10 @@ func (t *targetrunner) restoreObjLBNeigh(lom *cluster.LOM, r *http.Request, star
 		lom.SetExists(true)
 		return
 	} else if ecErr != ec.ErrorECDisabled {
-		errstr = fmt.Sprintf("Failed to restore object %s/%s: %v", lom.Bucket, lom.Objname, ecErr)
+		if errstr != "" {
+			errstr += ", "
+		}
+		errstr += fmt.Sprintf("Failed to restore object %s/%s: %v", lom.Bucket, lom.Objname, ecErr)
 	}
 
 	s := fmt.Sprintf("GET local: %s(%s) %s", lom, lom.FQN, cmn.DoesNotExist)
@@ -860,6 +1002,12 @@ func (t *targetrunner) objGetComplete(w http.ResponseWriter, r *http.Request, lo
 	if lom.Version != "" {
 		hdr.Add(cmn.HeaderObjVersion, lom.Version)
 	}
+	if lom.Atime.IsZero() && tierHops(r.URL.Query()) > 0 { // previous tier is asking - include atime
+		_ = lom.Fill("", cluster.LomAtime)
+	}
+	if !lom.Atime.IsZero() {
+		hdr.Add(cmn.HeaderObjAtime, lom.Atime.Format(cmn.RFC822))
+	}
 	hdr.Add(cmn.HeaderObjSize, strconv.FormatInt(lom.Size, 10))
 
 	// loopback if disk IO is disabled
@@ -1234,20 +1382,43 @@ func (t *targetrunner) httpbckpost(w http.ResponseWriter, r *http.Request) {
 		}
 		// re-checksum the bucket and return
 		t.runRechecksumBucket(bucket)
+	case cmn.ActResync:
+		// validation done in proxy.go
+		resyncMsg := &cmn.ResyncMsg{}
+		if msgInt.Value != nil {
+			jsbytes, err := jsoniter.Marshal(msgInt.Value)
+			cmn.AssertNoErr(err)
+			if err = jsoniter.Unmarshal(jsbytes, resyncMsg); err != nil {
+				t.invalmsghdlr(w, r, fmt.Sprintf("Invalid %s message, err: %v", cmn.ActResync, err))
+				return
+			}
+		}
+		xresync, err := t.startResync(t.contextWithAuth(r), bucket, resyncMsg)
+		if err != nil {
+			t.invalmsghdlr(w, r, fmt.Sprintf("Failed to resync bucket %s: %v", bucket, err))
+			return
+		}
+		jsbytes, err := jsoniter.Marshal(xresync.ID())
+		cmn.AssertNoErr(err)
+		t.writeJSON(w, r, jsbytes, "resync")
 	case cmn.ActEraseCopies:
 		bucket := apitems[0]
 		if !t.validatebckname(w, r, bucket) {
 			return
 		}
-		t.xactions.abortPutCopies(bucket)
-		bucketmd := t.bmdowner.get()
-		bckIsLocal := bucketmd.IsLocal(bucket)
-		t.xactions.renewEraseCopies(bucket, t, bckIsLocal)
+		t.eraseCopies(bucket)
 	default:
 		t.invalmsghdlr(w, r, "Unexpected action "+msgInt.Action)
 	}
 }
 
+// eraseCopies stops mirroring the bucket and removes its (local) copies
+func (t *targetrunner) eraseCopies(bucket string) {
+	t.xactions.abortPutCopies(bucket)
+	bckIsLocal := t.bmdowner.get().IsLocal(bucket)
+	t.xactions.renewEraseCopies(bucket, t, bckIsLocal)
+}
+
 // POST /v1/objects/bucket-name/object-name
 func (t *targetrunner) httpobjpost(w http.ResponseWriter, r *http.Request) {
 	var msg cmn.ActionMsg
@@ -1347,6 +1518,7 @@ func (t *targetrunner) httpbckhead(w http.ResponseWriter, r *http.Request) {
 	hdr.Add(cmn.HeaderBucketECMinSize, strconv.FormatUint(uint64(props.EC.ObjSizeLimit), 10))
 	hdr.Add(cmn.HeaderBucketECData, strconv.FormatUint(uint64(props.EC.DataSlices), 10))
 	hdr.Add(cmn.HeaderBucketECParity, strconv.FormatUint(uint64(props.EC.ParitySlices), 10))
+	hdr.Add(cmn.HeaderBucketPlacement, placementPolicy(props, t.smapowner.get()))
 }
 
 // HEAD /v1/objects/bucket-name/object-name
@@ -1359,6 +1531,7 @@ func (t *targetrunner) httpobjhead(w http.ResponseWriter, r *http.Request) {
 	)
 	query := r.URL.Query()
 	checkCached, _ = parsebool(query.Get(cmn.URLParamCheckCached))
+	hops := tierHops(query)
 	apitems, err := t.checkRESTItems(w, r, 2, false, cmn.Version, cmn.Objects)
 	if err != nil {
 		return
@@ -1379,17 +1552,24 @@ func (t *targetrunner) httpobjhead(w http.ResponseWriter, r *http.Request) {
 	}
 	if lom.BckIsLocal || checkCached {
 		if !lom.Exists() {
-			status := http.StatusNotFound
-			http.Error(w, http.StatusText(status), status)
-			return
+			if objmeta, errstr, errcode = t.headFromTier(lom, hops, checkCached); errstr != "" {
+				t.invalmsghdlr(w, r, errstr, errcode)
+				return
+			}
+			if objmeta == nil {
+				status := http.StatusNotFound
+				http.Error(w, http.StatusText(status), status)
+				return
+			}
 		} else if checkCached {
 			return
-		}
-		objmeta = make(cmn.SimpleKVs)
-		objmeta[cmn.HeaderObjSize] = strconv.FormatInt(lom.Size, 10)
-		objmeta[cmn.HeaderObjVersion] = lom.Version
-		if glog.FastV(4, glog.SmoduleAIS) {
-			glog.Infof("%s(%s), ver=%s", lom, cmn.B2S(lom.Size, 1), lom.Version)
+		} else {
+			objmeta = make(cmn.SimpleKVs)
+			objmeta[cmn.HeaderObjSize] = strconv.FormatInt(lom.Size, 10)
+			objmeta[cmn.HeaderObjVersion] = lom.Version
+			if glog.FastV(4, glog.SmoduleAIS) {
+				glog.Infof("%s(%s), ver=%s", lom, cmn.B2S(lom.Size, 1), lom.Version)
+			}
 		}
 	} else {
 		objmeta, errstr, errcode = getcloudif().headobject(t.contextWithAuth(r), bucket, objname)
@@ -1465,6 +1645,15 @@ func (t *targetrunner) metasyncHandlerPut(w http.ResponseWriter, r *http.Request
 		return
 	}
 	t.authn.updateRevokedList(revokedTokens)
+
+	conf, errstr := t.extractClusterConf(payload)
+	if errstr != "" {
+		t.invalmsghdlr(w, r, errstr)
+		return
+	}
+	if conf != nil {
+		t.confowner.synchronize(conf)
+	}
 }
 
 // GET /v1/health
@@ -1712,25 +1901,8 @@ func (t *targetrunner) getFromNeighbor(r *http.Request, lom *cluster.LOM) (remot
 	return
 }
 
-// TODO
-func (t *targetrunner) getFromTier(lom *cluster.LOM) (ok bool) {
-	if lom.BckProps == nil || lom.BckProps.NextTierURL == "" {
-		return
-	}
-	inNextTier, _, _ := t.objectInNextTier(lom.BckProps.NextTierURL, lom.Bucket, lom.Objname)
-	if !inNextTier {
-		return
-	}
-	props, errstr, _ := t.getObjectNextTier(lom.BckProps.NextTierURL, lom.Bucket, lom.Objname, lom.FQN)
-	if errstr == "" {
-		lom.RestoredReceived(props)
-		ok = true
-	}
-	return
-}
-
 // FIXME: recomputes checksum if called with a bad one (optimize)
-func (t *targetrunner) getCold(ct context.Context, lom *cluster.LOM, prefetch bool) (errstr string, errcode int) {
+func (t *targetrunner) getCold(ct context.Context, lom *cluster.LOM, prefetch bool, hops int) (errstr string, errcode int) {
 	var (
 		versioncfg      = &lom.Config.Ver
 		errv            string
@@ -1738,13 +1910,18 @@ func (t *targetrunner) getCold(ct context.Context, lom *cluster.LOM, prefetch bo
 		err             error
 		props           *cluster.LOM
 	)
+	// fail fast (and without serializing on the name lock) if the object is known to not exist
+	if !lom.BckIsLocal && t.cloudcache.notFound(lom.Bucket, lom.Objname) {
+		errstr, errcode = negCacheHit(lom.Bucket, lom.Objname)
+		return
+	}
 	if prefetch {
 		if !t.rtnamemap.TryLock(lom.Uname, true) {
 			glog.Infof("prefetch: cold GET race: %s - skipping", lom)
 			return "skip", 0
 		}
-	} else {
-		t.rtnamemap.Lock(lom.Uname, true) // one cold-GET at a time
+	} else if errstr, errcode = t.lockObj(ct, lom, true); errstr != "" { // one cold-GET at a time
+		return
 	}
 
 	// refill
@@ -1785,15 +1962,19 @@ func (t *targetrunner) getCold(ct context.Context, lom *cluster.LOM, prefetch bo
 	//
 	// next tier if
 	//
-	if lom.BckProps != nil && lom.BckProps.NextTierURL != "" && lom.BckProps.ReadPolicy == cmn.RWPolicyNextTier {
+	if nextTierURL := readTier(lom); nextTierURL != "" {
 		var inNextTier bool
-		if inNextTier, errstr, errcode = t.objectInNextTier(lom.BckProps.NextTierURL, lom.Bucket, lom.Objname); errstr == "" {
+		if inNextTier, errstr, errcode = t.objectInNextTier(nextTierURL, lom.Bucket, lom.Objname, hops); errstr == "" {
 			if inNextTier {
-				if props, errstr, errcode = t.getObjectNextTier(lom.BckProps.NextTierURL, lom.Bucket, lom.Objname, workFQN); errstr == "" {
+				if props, errstr, errcode = t.getObjectNextTier(nextTierURL, lom, workFQN, hops); errstr == "" {
 					coldGet = false
 				}
 			}
 		}
+		if errstr != "" {
+			glog.Warningf("%s - falling back to cloud: %s", lom, errstr)
+			errstr, errcode = "", 0
+		}
 	}
 	//
 	// cloud
@@ -1842,6 +2023,19 @@ ret:
 	return
 }
 
+// lockObj waits in line for the object's lock - for at most the (long) default timeout
+// or until the context is canceled (e.g., when the client goes away)
+func (t *targetrunner) lockObj(ct context.Context, lom *cluster.LOM, exclusive bool) (errstr string, errcode int) {
+	ctx, cancel := context.WithTimeout(ct, lom.Config.Timeout.DefaultLong)
+	err := t.rtnamemap.LockCtx(ctx, lom.Uname, exclusive)
+	cancel()
+	if err != nil {
+		errstr = fmt.Sprintf("%s: failed to acquire lock (exclusive=%t), err: %v", lom, exclusive, err)
+		errcode = http.StatusServiceUnavailable
+	}
+	return
+}
+
 func (t *targetrunner) lookupRemotely(lom *cluster.LOM) *cluster.Snode {
 	res := t.broadcastTo(
 		cmn.URLPath(cmn.Version, cmn.Objects, lom.Bucket, lom.Objname),
@@ -2272,12 +2466,21 @@ func (t *targetrunner) doPut(r *http.Request, bucket, objname string) (err error
 		cksumToCheck: cksum,
 		ctx:          t.contextWithAuth(r),
 		bckProvider:  bckProvider,
+		tierHops:     tierHops(r.URL.Query()),
 	}
 	if err := roi.init(); err != nil {
 		return err, http.StatusInternalServerError
 	}
-
-	return roi.recv()
+	if err, errcode = roi.recv(); err != nil {
+		return
+	}
+	// written by the previous tier: preserve the original atime
+	if timeStr := r.Header.Get(cmn.HeaderObjAtime); timeStr != "" && roi.tierHops > 0 {
+		if tm, err := time.Parse(cmn.RFC822, timeStr); err == nil {
+			roi.lom.UpdateAtime(tm)
+		}
+	}
+	return
 }
 
 // TODO: this function is for now unused because replication does not work
@@ -2382,17 +2585,8 @@ func (roi *recvObjInfo) commit() (errstr string, errCode int) {
 }
 
 func (roi *recvObjInfo) tryCommit() (errstr string, errCode int) {
-	if !roi.lom.BckIsLocal && !roi.migrated {
-		file, err := os.Open(roi.workFQN)
-		if err != nil {
-			errstr = fmt.Sprintf("Failed to open %s err: %v", roi.workFQN, err)
-			return
-		}
-
-		cmn.Assert(roi.lom.Cksum != nil)
-		roi.lom.Version, errstr, errCode = getcloudif().putobj(roi.ctx, file, roi.lom.Bucket, roi.lom.Objname, roi.lom.Cksum)
-		file.Close()
-		if errstr != "" {
+	if !roi.migrated {
+		if errstr, errCode = roi.writeThrough(); errstr != "" {
 			return
 		}
 	}
@@ -2719,7 +2913,13 @@ func (t *targetrunner) httpdaeput(w http.ResponseWriter, r *http.Request) {
 			t.handleMountpathReq(w, r)
 			return
 		case cmn.ActSetConfig: // setconfig #1 - via query parameters and "?n1=v1&n2=v2..."
-			kvs := cmn.NewSimpleKVsFromQuery(r.URL.Query())
+			kvs, dryRun := setConfigQuery(r)
+			if dryRun {
+				if errstr := cmn.ValidateConfigMany(kvs); errstr != "" {
+					t.invalmsghdlr(w, r, errstr)
+				}
+				return
+			}
 			if errstr := t.setConfig(kvs); errstr != "" {
 				t.invalmsghdlr(w, r, errstr)
 				return
@@ -2749,6 +2949,8 @@ func (t *targetrunner) httpdaeput(w http.ResponseWriter, r *http.Request) {
 			t.invalmsghdlr(w, r, errstr)
 			return
 		}
+	case cmn.ActReloadConfig:
+		t.httpReloadConfig(w, r)
 	case cmn.ActShutdown:
 		_ = syscall.Kill(syscall.Getpid(), syscall.SIGINT)
 	default:
@@ -2764,11 +2966,21 @@ func (t *targetrunner) setConfig(kvs cmn.SimpleKVs) (errstr string) {
 	}
 
 	config := cmn.GCO.Get()
+	if prevConfig.Rebalance.Bandwidth != config.Rebalance.Bandwidth && t.rebManager != nil {
+		t.rebManager.limiter.SetRate(config.Rebalance.Bandwidth)
+	}
 	if prevConfig.LRU.Enabled && !config.LRU.Enabled {
-		lruXaction := t.xactions.findU(cmn.ActLRU)
+		_, lruXaction := t.xactions.findL(cmn.ActLRU)
 		if lruXaction != nil {
 			glog.V(3).Infof("Aborting LRU due to lru.enabled config change")
-			lruXaction.Abort()
+			lruXaction.AbortWith("lru disabled")
+		}
+	}
+	if !prevConfig.Scrub.Enabled && config.Scrub.Enabled {
+		go t.runScrub()
+	} else if prevConfig.Scrub.Enabled && !config.Scrub.Enabled {
+		if _, xscrub := t.xactions.findL(cmn.ActScrub); xscrub != nil {
+			xscrub.AbortWith("scrubbing disabled")
 		}
 	}
 	return
@@ -2853,6 +3065,12 @@ func (t *targetrunner) httpdaeget(w http.ResponseWriter, r *http.Request) {
 			cmn.AssertNoErr(err)
 		}
 		t.writeJSON(w, r, jsbytes, "httpdaeget-"+getWhat)
+	case cmn.GetWhatRebPreview:
+		t.httpRebPreview(w, r)
+	case cmn.GetWhatLocks:
+		jsbytes, err := jsoniter.Marshal(t.rtnamemap.Locks())
+		cmn.AssertNoErr(err)
+		t.writeJSON(w, r, jsbytes, "httpdaeget-"+getWhat)
 	case cmn.GetWhatMountpaths:
 		mpList := cmn.MountpathList{}
 		availablePaths, disabledPaths := fs.Mountpaths.Get()
@@ -3298,12 +3516,12 @@ func (t *targetrunner) handleMountpathReq(w http.ResponseWriter, r *http.Request
 	}
 }
 
-func (t *targetrunner) stopXactions(xacts []string) {
+func (t *targetrunner) stopXactions(reason string, xacts []string) {
 	for _, name := range xacts {
 		xactList := t.xactions.selectL(name)
 		for _, xact := range xactList {
 			if !xact.Finished() {
-				xact.Abort()
+				xact.AbortWith(reason)
 			}
 		}
 	}
@@ -3320,7 +3538,7 @@ func (t *targetrunner) handleEnableMountpathReq(w http.ResponseWriter, r *http.R
 		t.invalmsghdlr(w, r, fmt.Sprintf("Mountpath %s not found", mountpath), http.StatusNotFound)
 		return
 	}
-	t.stopXactions([]string{cmn.ActLRU, cmn.ActPutCopies, cmn.ActEraseCopies})
+	t.stopXactions("mountpath "+mountpath+" enabled", []string{cmn.ActLRU, cmn.ActPutCopies, cmn.ActEraseCopies})
 }
 
 func (t *targetrunner) handleDisableMountpathReq(w http.ResponseWriter, r *http.Request, mountpath string) {
@@ -3335,7 +3553,7 @@ func (t *targetrunner) handleDisableMountpathReq(w http.ResponseWriter, r *http.
 		return
 	}
 
-	t.stopXactions([]string{cmn.ActLRU, cmn.ActPutCopies, cmn.ActEraseCopies})
+	t.stopXactions("mountpath "+mountpath+" disabled", []string{cmn.ActLRU, cmn.ActPutCopies, cmn.ActEraseCopies})
 }
 
 func (t *targetrunner) handleAddMountpathReq(w http.ResponseWriter, r *http.Request, mountpath string) {
@@ -3344,7 +3562,7 @@ func (t *targetrunner) handleAddMountpathReq(w http.ResponseWriter, r *http.Requ
 		t.invalmsghdlr(w, r, fmt.Sprintf("Could not add mountpath, error: %v", err))
 		return
 	}
-	t.stopXactions([]string{cmn.ActLRU, cmn.ActPutCopies, cmn.ActEraseCopies})
+	t.stopXactions("mountpath "+mountpath+" added", []string{cmn.ActLRU, cmn.ActPutCopies, cmn.ActEraseCopies})
 }
 
 func (t *targetrunner) handleRemoveMountpathReq(w http.ResponseWriter, r *http.Request, mountpath string) {
@@ -3354,7 +3572,7 @@ func (t *targetrunner) handleRemoveMountpathReq(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	t.stopXactions([]string{cmn.ActLRU, cmn.ActPutCopies, cmn.ActEraseCopies})
+	t.stopXactions("mountpath "+mountpath+" removed", []string{cmn.ActLRU, cmn.ActPutCopies, cmn.ActEraseCopies})
 }
 
 // FIXME: use the message
@@ -3435,7 +3653,8 @@ func (t *targetrunner) receiveSmap(newsmap *smapX, msgInt *actionMsgInternal) (e
 	if errstr = t.smapowner.synchronize(newsmap, false /*saveSmap*/, true /* lesserIsErr */); errstr != "" {
 		return
 	}
-	if msgInt.Action == cmn.ActGlobalReb {
+	// NOTE: decommission migrates the target's content regardless of the rebalance config
+	if msgInt.Action == cmn.ActGlobalReb || msgInt.Action == cmn.ActDecommission {
 		go t.rebManager.runGlobalReb(newsmap, newTargetID)
 		return
 	}
@@ -3610,7 +3829,7 @@ func (t *targetrunner) enable() error {
 func (t *targetrunner) Disable(mountpath string, why string) (disabled, exists bool) {
 	// TODO: notify admin that the mountpath is gone
 	glog.Warningf("Disabling mountpath %s: %s", mountpath, why)
-	t.stopXactions([]string{cmn.ActLRU, cmn.ActPutCopies, cmn.ActEraseCopies})
+	t.stopXactions("mountpath "+mountpath+" disabled: "+why, []string{cmn.ActLRU, cmn.ActPutCopies, cmn.ActEraseCopies})
 	return t.fsprg.disableMountpath(mountpath)
 }
 
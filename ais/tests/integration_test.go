@@ -5,7 +5,6 @@
 package ais_test
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path"
@@ -22,7 +21,6 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fs"
-	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/tutils"
 )
 
@@ -1627,30 +1625,9 @@ func testLocalMirror(t *testing.T, erase bool) {
 		if err := api.EraseCopies(baseParams, m.bucket); err != nil {
 			t.Fatalf("Failed to start erase-copies xaction, err: %v", err)
 		}
-		timedout := 60 // seconds
-		ok := false
-		for i := 0; i < timedout+1; i++ {
-			var allDetails = make(map[string][]stats.XactionDetails) // TODO: missing API
-			time.Sleep(time.Second)
-
-			responseBytes, err := tutils.GetXactionResponse(m.proxyURL, cmn.ActEraseCopies)
-			tutils.CheckFatal(err, t)
-			err = json.Unmarshal(responseBytes, &allDetails)
-			tutils.CheckFatal(err, t)
-			ok = true
-			for tid := range allDetails {
-				detail := allDetails[tid][0] // TODO
-				if detail.Status == cmn.XactionStatusInProgress {
-					ok = false
-					break
-				}
-			}
-			if ok {
-				break
-			}
-		}
-		if !ok {
-			t.Errorf("timed-out waiting for %s to finish", cmn.ActEraseCopies)
+		xmsg := &cmn.XactMsg{Kind: cmn.ActEraseCopies, Bucket: m.bucket}
+		if err := api.WaitForXaction(baseParams, xmsg, 60*time.Second); err != nil {
+			t.Error(err)
 		}
 	}
 
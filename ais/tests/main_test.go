@@ -28,8 +28,8 @@ import (
 	"time"
 
 	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
-	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/tutils"
 	jsoniter "github.com/json-iterator/go"
 )
@@ -1202,7 +1202,7 @@ func TestChecksumValidateOnWarmGetForCloudBucket(t *testing.T) {
 		t.Errorf("Failed while reading the bucket from the local file system. Error: [%v]", err)
 	}
 	tutils.Logf("\nChanging file xattr[%s]: %s\n", fileName, fqn)
-	errstr = fs.SetXattr(fqn, cmn.XattrXXHash, []byte("01234abcde"))
+	errstr = cluster.SetXattrCksum(fqn, cmn.NewCksum(cmn.ChecksumXXHash, "01234abcde"))
 	if errstr != "" {
 		t.Error(errstr)
 	}
@@ -1217,7 +1217,7 @@ func TestChecksumValidateOnWarmGetForCloudBucket(t *testing.T) {
 		goto cleanup
 	}
 	tutils.Logf("\nChanging file xattr[%s]: %s\n", fileName, fqn)
-	errstr = fs.SetXattr(fqn, cmn.XattrXXHash, []byte("01234abcde"))
+	errstr = cluster.SetXattrCksum(fqn, cmn.NewCksum(cmn.ChecksumXXHash, "01234abcde"))
 	if errstr != "" {
 		t.Error(errstr)
 	}
@@ -1397,7 +1397,7 @@ func TestChecksumValidateOnWarmGetForLocalBucket(t *testing.T) {
 	fileName = <-fileNameCh
 	filepath.Walk(rootDir, fsWalkFunc)
 	tutils.Logf("Changing file xattr[%s]: %s\n", fileName, fqn)
-	errstr = fs.SetXattr(fqn, cmn.XattrXXHash, []byte("01234abcde"))
+	errstr = cluster.SetXattrCksum(fqn, cmn.NewCksum(cmn.ChecksumXXHash, "01234abcde"))
 	if errstr != "" {
 		t.Error(errstr)
 	}
@@ -1411,7 +1411,7 @@ func TestChecksumValidateOnWarmGetForLocalBucket(t *testing.T) {
 		goto cleanup
 	}
 	tutils.Logf("Changing file xattr[%s]: %s\n", fileName, fqn)
-	errstr = fs.SetXattr(fqn, cmn.XattrXXHash, []byte("01234abcde"))
+	errstr = cluster.SetXattrCksum(fqn, cmn.NewCksum(cmn.ChecksumXXHash, "01234abcde"))
 	if errstr != "" {
 		t.Error(errstr)
 	}
@@ -260,11 +260,6 @@ func TestPutObjectNextTierPolicy(t *testing.T) {
 		baseParams                        = tutils.BaseAPIParams(proxyURL)
 		err                               error
 	)
-	// TODO: enable and review when the time is right
-	if true {
-		t.Skip(fmt.Sprintf("test %q requires next-version tiering", t.Name()))
-	}
-
 	tutils.Logf("proxyurl: %q \n", proxyURL)
 	if !isCloudBucket(t, proxyURL, clibucket) {
 		t.Skip(fmt.Sprintf("test %q requires a cloud bucket", t.Name()))
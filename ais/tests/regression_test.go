@@ -26,7 +26,6 @@ import (
 	"github.com/NVIDIA/aistore/api"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
-	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/tutils"
@@ -284,7 +283,7 @@ func TestGetCorruptFileAfterPut(t *testing.T) {
 	fName = <-filenameCh
 	filepath.Walk(rootDir, fsWalkFunc)
 	tutils.Logf("Corrupting file xattr[%s]: %s\n", fName, fqn)
-	if errstr := fs.SetXattr(fqn, cmn.XattrXXHash, []byte("01234abcde")); errstr != "" {
+	if errstr := cluster.SetXattrCksum(fqn, cmn.NewCksum(cmn.ChecksumXXHash, "01234abcde")); errstr != "" {
 		t.Error(errstr)
 	}
 	_, err = api.GetObjectWithValidation(tutils.DefaultBaseAPIParams(t), bucket, path.Join(SmokeStr, fName))
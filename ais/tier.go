@@ -5,119 +5,352 @@
 package ais
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
 
+	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
 )
 
-func (t *targetrunner) objectInNextTier(nextTierURL, bucket, objname string) (in bool, errstr string, errcode int) {
-	url := nextTierURL + cmn.URLPath(cmn.Version, cmn.Objects, bucket, objname)
-	url += fmt.Sprintf("?%s=true", cmn.URLParamCheckCached)
+//
+// Multi-tier: each AIStore cluster in the hierarchy may have (per bucket) the next
+// tier configured via BucketProps.NextTierURL. Reads (ReadPolicy) and writes
+// (WritePolicy) are forwarded to the next tier which, in turn, may forward them
+// further down the chain. Object attributes (checksum, version, atime) travel
+// in both directions via the cmn.HeaderObj* headers; the number of traversed
+// tiers is carried by the cmn.URLParamTierHops query parameter and is limited
+// to protect against misconfigured (looped) hierarchies.
+//
 
-	resp, err := t.httprunner.httpclientLongTimeout.Head(url)
+const maxTierHops = 8
+
+// returns the number of tiers the request has traversed so far (zero for user requests)
+func tierHops(query url.Values) (hops int) {
+	if s := query.Get(cmn.URLParamTierHops); s != "" {
+		hops, _ = strconv.Atoi(s)
+	}
+	return
+}
+
+func nextTierObjURL(nextTierURL, bucket, objname string, hops int, query url.Values) (string, string) {
+	if hops >= maxTierHops {
+		return "", fmt.Sprintf("%s/%s: exceeded max number of tiers (%d) - tiering loop via %s?",
+			bucket, objname, maxTierHops, nextTierURL)
+	}
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set(cmn.URLParamTierHops, strconv.Itoa(hops+1))
+	return nextTierURL + cmn.URLPath(cmn.Version, cmn.Objects, bucket, objname) + "?" + query.Encode(), ""
+}
+
+func nextTierUnreachable(nextTierURL string, err error) (errstr string, errcode int) {
+	errstr = fmt.Sprintf("next tier %s is unreachable, err: %v", nextTierURL, err)
+	errcode = http.StatusServiceUnavailable
+	return
+}
+
+func nextTierErr(resp *http.Response, nextTierURL, bucket, objname string) (errstr string, errcode int) {
+	errcode = resp.StatusCode
+	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		errstr = err.Error()
+		errstr = fmt.Sprintf("HTTP status code: %d, failed to read response body (err: %v), bucket/object: %s/%s, next tier URL: %s",
+			resp.StatusCode, err, bucket, objname, nextTierURL)
 		return
 	}
+	errstr = fmt.Sprintf(
+		"HTTP status code: %d, HTTP response body: %s, bucket/object: %s/%s, next tier URL: %s",
+		resp.StatusCode, string(b), bucket, objname, nextTierURL,
+	)
+	return
+}
+
+// readTier returns the next tier to read the object from when the object is not
+// present locally, or "" if the bucket's ReadPolicy says otherwise; GET (cold GET
+// and getFromTier) and HEAD resolve the tier in the same way
+func readTier(lom *cluster.LOM) string {
+	if lom.BckProps == nil || lom.BckProps.NextTierURL == "" || lom.BckProps.ReadPolicy != cmn.RWPolicyNextTier {
+		return ""
+	}
+	return lom.BckProps.NextTierURL
+}
+
+func (t *targetrunner) objectInNextTier(nextTierURL, bucket, objname string, hops int) (in bool, errstr string, errcode int) {
+	var objmeta cmn.SimpleKVs
+	objmeta, errstr, errcode = t.headObjectNextTier(nextTierURL, bucket, objname, hops, true /*checkCached*/)
+	in = objmeta != nil
+	return
+}
+
+// headObjectNextTier returns the object's properties (size and version) as reported
+// by the next tier, or nil if the next tier does not have the object
+func (t *targetrunner) headObjectNextTier(nextTierURL, bucket, objname string, hops int,
+	checkCached bool) (objmeta cmn.SimpleKVs, errstr string, errcode int) {
+	query := url.Values{}
+	if checkCached {
+		query.Add(cmn.URLParamCheckCached, "true")
+	}
+	objURL, errstr := nextTierObjURL(nextTierURL, bucket, objname, hops, query)
+	if errstr != "" {
+		return
+	}
+	resp, err := t.httprunner.httpclientLongTimeout.Head(objURL)
+	if err != nil {
+		errstr, errcode = nextTierUnreachable(nextTierURL, err)
+		return
+	}
+	defer resp.Body.Close()
 	if resp.StatusCode >= http.StatusBadRequest {
 		if resp.StatusCode == http.StatusNotFound {
-			resp.Body.Close()
 			return
 		}
 		errcode = resp.StatusCode
-		_, err := ioutil.ReadAll(resp.Body)
 		errstr = fmt.Sprintf("Failed to get %s/%s from %s tier, status %d", bucket, objname, nextTierURL, resp.StatusCode)
-		if err != nil {
-			errstr += fmt.Sprintf(" (Failed to read response, err: %v)", err)
-		}
-		resp.Body.Close()
 		return
 	}
-	in = true
-	resp.Body.Close()
+	objmeta = make(cmn.SimpleKVs, 2)
+	for _, k := range []string{cmn.HeaderObjSize, cmn.HeaderObjVersion} {
+		if v := resp.Header.Get(k); v != "" {
+			objmeta[k] = v
+		}
+	}
 	return
 }
 
-func (t *targetrunner) getObjectNextTier(nextTierURL, bucket, objname, fqn string) (lom *cluster.LOM, errstr string, errcode int) {
-	var url = nextTierURL + cmn.URLPath(cmn.Version, cmn.Objects, bucket, objname)
-
-	resp, err := t.httprunner.httpclientLongTimeout.Get(url)
+// getObjectNextTier receives the object from the next tier into the specified workfile
+// and returns the object's properties (size, checksum, version and atime) as reported
+// by the next tier; the caller is responsible for committing the workfile
+func (t *targetrunner) getObjectNextTier(nextTierURL string, lom *cluster.LOM, workFQN string, hops int) (props *cluster.LOM, errstr string, errcode int) {
+	objURL, errstr := nextTierObjURL(nextTierURL, lom.Bucket, lom.Objname, hops, nil)
+	if errstr != "" {
+		return
+	}
+	resp, err := t.httprunner.httpclientLongTimeout.Get(objURL)
 	if err != nil {
-		errstr = err.Error()
+		errstr, errcode = nextTierUnreachable(nextTierURL, err)
 		return
 	}
-
 	if resp.StatusCode >= http.StatusBadRequest {
-		errcode = resp.StatusCode
-		b, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			errstr = err.Error()
-			resp.Body.Close()
-			return
-		}
-		errstr = fmt.Sprintf(
-			"HTTP status code: %d, HTTP response body: %s, bucket/object: %s/%s, next tier URL: %s",
-			resp.StatusCode, string(b), bucket, objname, nextTierURL,
-		)
+		errstr, errcode = nextTierErr(resp, nextTierURL, lom.Bucket, lom.Objname)
 		resp.Body.Close()
 		return
 	}
-
-	lom = &cluster.LOM{T: t, Bucket: bucket, Objname: objname}
-	if errstr = lom.Fill("", 0); errstr != "" {
-		resp.Body.Close()
-		return
+	var (
+		cksumValue = resp.Header.Get(cmn.HeaderObjCksumVal)
+		cksumType  = resp.Header.Get(cmn.HeaderObjCksumType)
+		cksum      = cmn.NewCksum(cksumType, cksumValue)
+		version    = resp.Header.Get(cmn.HeaderObjVersion)
+	)
+	props = lom.Copy(cluster.LOMCopyProps{Cksum: cksum, Version: version})
+	if timeStr := resp.Header.Get(cmn.HeaderObjAtime); timeStr != "" {
+		if tm, err := time.Parse(cmn.RFC822, timeStr); err == nil {
+			props.Atime, props.Atimestr = tm, timeStr
+		}
 	}
-	// TODO: we should send atime, checksum and version
 	roi := &recvObjInfo{
 		t:       t,
-		workFQN: fqn,
-		cold:    true,
-		r:       resp.Body,
-		lom:     lom,
+		workFQN: workFQN,
+		r:       resp.Body, // NOTE: closed by writeToFile
+		lom:     props,
+	}
+	// the next tier is another AIStore cluster that stores (and reports) xxhash
+	// checksums - same as intra-cluster migration; otherwise, treat it as cold GET
+	if cksumType == cmn.ChecksumXXHash && cksumValue != "" {
+		roi.migrated, roi.cksumToCheck = true, cksum
+	} else {
+		roi.cold = true
 	}
 	if err = roi.writeToFile(); err != nil {
 		errstr = err.Error()
+		props = nil
 	}
-	resp.Body.Close()
 	return
 }
 
-// TODO: this function is currently unused should be placed in `put`
-//lint:ignore U1000 unused
-func (t *targetrunner) putObjectNextTier(nextTierURL, bucket, object string, body io.ReadCloser,
-	reopenBody func() (io.ReadCloser, error)) (errstr string, errcode int) {
-	var url = nextTierURL + cmn.URLPath(cmn.Version, cmn.Objects, bucket, object)
-
-	req, err := http.NewRequest(http.MethodPut, url, body)
+// putObjectNextTier sends the object (that is, its content stored at fqn) to the
+// next tier along with its checksum, version and atime
+func (t *targetrunner) putObjectNextTier(nextTierURL string, lom *cluster.LOM, fqn string, hops int) (errstr string, errcode int) {
+	objURL, errstr := nextTierObjURL(nextTierURL, lom.Bucket, lom.Objname, hops, nil)
+	if errstr != "" {
+		return
+	}
+	file, err := os.Open(fqn)
+	if err != nil {
+		errstr = fmt.Sprintf("Failed to open %s, err: %v", fqn, err)
+		return
+	}
+	req, err := http.NewRequest(http.MethodPut, objURL, file)
 	if err != nil {
+		file.Close()
 		errstr = fmt.Sprintf("failed to create new HTTP request, err: %v", err)
 		return
 	}
-	req.GetBody = reopenBody
-	resp, err := t.httprunner.httpclientLongTimeout.Do(req)
+	req.GetBody = func() (io.ReadCloser, error) {
+		return os.Open(fqn)
+	}
+	req.ContentLength = lom.Size
+	if lom.Cksum != nil {
+		cksumType, cksumValue := lom.Cksum.Get()
+		req.Header.Add(cmn.HeaderObjCksumType, cksumType)
+		req.Header.Add(cmn.HeaderObjCksumVal, cksumValue)
+	}
+	if lom.Version != "" {
+		req.Header.Add(cmn.HeaderObjVersion, lom.Version)
+	}
+	if !lom.Atime.IsZero() {
+		req.Header.Add(cmn.HeaderObjAtime, lom.Atime.Format(cmn.RFC822))
+	}
+	resp, err := t.httprunner.httpclientLongTimeout.Do(req) // NOTE: closes the file
 	if err != nil {
-		errstr = err.Error()
+		errstr, errcode = nextTierUnreachable(nextTierURL, err)
 		return
 	}
-
 	if resp.StatusCode >= http.StatusBadRequest {
-		errcode = resp.StatusCode
-		b, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			errstr = err.Error()
-		} else {
-			errstr = fmt.Sprintf(
-				"HTTP status code: %d, HTTP response body: %s, bucket/object: %s/%s, next tier URL: %s",
-				resp.StatusCode, string(b), bucket, object, nextTierURL,
-			)
+		errstr, errcode = nextTierErr(resp, nextTierURL, lom.Bucket, lom.Objname)
+	}
+	resp.Body.Close()
+	return
+}
+
+// getFromTier restores a missing object from the next tier (if configured for reading
+// and if the object is there); on success the object is committed and its metadata persisted
+func (t *targetrunner) getFromTier(lom *cluster.LOM, hops int) (ok bool, errstr string, errcode int) {
+	var (
+		inNextTier  bool
+		props       *cluster.LOM
+		nextTierURL = readTier(lom)
+	)
+	if nextTierURL == "" {
+		return
+	}
+	if inNextTier, errstr, errcode = t.objectInNextTier(nextTierURL, lom.Bucket, lom.Objname, hops); !inNextTier {
+		return
+	}
+	workFQN := lom.GenFQN(fs.WorkfileType, fs.WorkfileRemote)
+	if props, errstr, errcode = t.getObjectNextTier(nextTierURL, lom, workFQN, hops); errstr != "" {
+		return
+	}
+	if err := cmn.MvFile(workFQN, lom.FQN); err != nil {
+		errstr = fmt.Sprintf("Unexpected failure to rename %s => %s, err: %v", workFQN, lom.FQN, err)
+		t.fshc(err, lom.FQN)
+		if errRemove := os.Remove(workFQN); errRemove != nil {
+			glog.Errorf("Nested error %s => (remove %s => err: %v)", errstr, workFQN, errRemove)
 		}
+		return
+	}
+	lom.RestoredReceived(props)
+	if errstr = lom.Persist(); errstr != "" {
+		return
 	}
+	lom.UpdateAtime(lom.Atime)
+	ok = true
+	return
+}
 
-	resp.Body.Close()
+// headFromTier returns the properties of a missing object if the object is stored
+// further down the hierarchy - the tier is resolved in the same way as for GET (see
+// getFromTier); for cloud buckets, only when asked by the previous tier in the chain
+func (t *targetrunner) headFromTier(lom *cluster.LOM, hops int, checkCached bool) (objmeta cmn.SimpleKVs, errstr string, errcode int) {
+	nextTierURL := readTier(lom)
+	if nextTierURL == "" || (!lom.BckIsLocal && hops == 0) {
+		return
+	}
+	return t.headObjectNextTier(nextTierURL, lom.Bucket, lom.Objname, hops, checkCached)
+}
+
+// writeThrough is called prior to committing the object locally - it forwards
+// the object being PUT to the next tier and/or Cloud as per bucket's WritePolicy:
+// - local bucket: the next tier (if configured) must succeed;
+// - cloud bucket: the next tier (if configured) takes over the Cloud write
+//   (which becomes its responsibility); if the next tier fails we fall back to Cloud
+func (roi *recvObjInfo) writeThrough() (errstr string, errCode int) {
+	var (
+		lom    = roi.lom
+		bprops = lom.BckProps
+	)
+	if !lom.BckIsLocal {
+		// and again once the object is written (see cloudCache)
+		roi.t.cloudcache.invalidate(lom.Bucket, lom.Objname)
+		defer roi.t.cloudcache.invalidate(lom.Bucket, lom.Objname)
+	}
+	if bprops != nil && bprops.NextTierURL != "" && bprops.WritePolicy == cmn.RWPolicyNextTier {
+		if lom.Atime.IsZero() {
+			lom.Atime = roi.started
+		}
+		if errstr, errCode = roi.t.putObjectNextTier(bprops.NextTierURL, lom, roi.workFQN, roi.tierHops); errstr == "" {
+			if glog.FastV(4, glog.SmoduleAIS) {
+				glog.Infof("PUT %s => next tier %s", lom, bprops.NextTierURL)
+			}
+			return
+		}
+		if lom.BckIsLocal {
+			return
+		}
+		glog.Warningf("%s: %s - falling back to cloud", lom, errstr)
+		errstr, errCode = "", 0
+	}
+	if lom.BckIsLocal {
+		return
+	}
+	file, err := os.Open(roi.workFQN)
+	if err != nil {
+		errstr = fmt.Sprintf("Failed to open %s err: %v", roi.workFQN, err)
+		return
+	}
+	cmn.Assert(lom.Cksum != nil)
+	lom.Version, errstr, errCode = getcloudif().putobj(roi.ctx, file, lom.Bucket, lom.Objname, lom.Cksum)
+	file.Close()
 	return
 }
+
+// demote sends a local-bucket object to the next tier (unless it is already there,
+// e.g. written through) and verifies that it is there; the caller (LRU) removes
+// the local copy only upon successful demotion
+func (t *targetrunner) demote(lom *cluster.LOM) error {
+	bprops := lom.BckProps
+	if bprops == nil || bprops.NextTierURL == "" {
+		return fmt.Errorf("%s: next tier is not configured", lom)
+	}
+	if errstr := lom.Fill("", cluster.LomFstat|cluster.LomVersion|cluster.LomCksum); errstr != "" {
+		return errors.New(errstr)
+	}
+	if !lom.Exists() {
+		return fmt.Errorf("%s: %s", lom, cmn.DoesNotExist)
+	}
+	objmeta, errstr, _ := t.headObjectNextTier(bprops.NextTierURL, lom.Bucket, lom.Objname, 0, true /*checkCached*/)
+	if errstr != "" {
+		return fmt.Errorf("failed to demote %s: %s", lom, errstr)
+	}
+	if inNextTier(lom, objmeta) {
+		return nil
+	}
+	if errstr, _ := t.putObjectNextTier(bprops.NextTierURL, lom, lom.FQN, 0); errstr != "" {
+		return fmt.Errorf("failed to demote %s: %s", lom, errstr)
+	}
+	in, errstr, _ := t.objectInNextTier(bprops.NextTierURL, lom.Bucket, lom.Objname, 0)
+	if errstr != "" {
+		return fmt.Errorf("failed to verify demoted %s: %s", lom, errstr)
+	}
+	if !in {
+		return fmt.Errorf("failed to verify demoted %s: not found in the next tier %s", lom, bprops.NextTierURL)
+	}
+	return nil
+}
+
+// tells whether the next tier has the same object (size and version) as the one
+// described by its HEAD properties
+func inNextTier(lom *cluster.LOM, objmeta cmn.SimpleKVs) bool {
+	if objmeta == nil || objmeta[cmn.HeaderObjSize] != strconv.FormatInt(lom.Size, 10) {
+		return false
+	}
+	return lom.Version == "" || objmeta[cmn.HeaderObjVersion] == lom.Version
+}
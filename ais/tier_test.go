@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+func TestReadTier(t *testing.T) {
+	const nextTierURL = "http://tier2:8080"
+	tests := []struct {
+		props *cmn.BucketProps
+		tier  string
+	}{
+		{nil, ""},
+		{&cmn.BucketProps{ReadPolicy: cmn.RWPolicyNextTier}, ""},
+		{&cmn.BucketProps{NextTierURL: nextTierURL, ReadPolicy: cmn.RWPolicyNextTier}, nextTierURL},
+		{&cmn.BucketProps{NextTierURL: nextTierURL, ReadPolicy: cmn.RWPolicyCloud}, ""},
+	}
+	for _, test := range tests {
+		lom := &cluster.LOM{BckProps: test.props}
+		if tier := readTier(lom); tier != test.tier {
+			t.Errorf("%+v: expecting %q, got %q", test.props, test.tier, tier)
+		}
+	}
+}
+
+func TestHeadFromTier(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get(cmn.URLParamTierHops) == "" {
+			http.Error(w, "expecting the number of tier hops", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path != cmn.URLPath(cmn.Version, cmn.Objects, "bucket", "obj") {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		w.Header().Set(cmn.HeaderObjSize, "1024")
+		w.Header().Set(cmn.HeaderObjVersion, "3")
+	}))
+	defer ts.Close()
+
+	tgt := newFakeTargetRunner()
+	tgt.httpclientLongTimeout = &http.Client{}
+	props := &cmn.BucketProps{NextTierURL: ts.URL, ReadPolicy: cmn.RWPolicyNextTier}
+	tests := []struct {
+		lom   *cluster.LOM
+		hops  int
+		found bool
+	}{
+		{&cluster.LOM{Bucket: "bucket", Objname: "obj", BckIsLocal: true, BckProps: props}, 0, true},
+		{&cluster.LOM{Bucket: "bucket", Objname: "none", BckIsLocal: true, BckProps: props}, 0, false},
+		// cloud bucket: only when asked by the previous tier
+		{&cluster.LOM{Bucket: "bucket", Objname: "obj", BckProps: props}, 0, false},
+		{&cluster.LOM{Bucket: "bucket", Objname: "obj", BckProps: props}, 1, true},
+		// read policy: cloud
+		{&cluster.LOM{Bucket: "bucket", Objname: "obj", BckIsLocal: true,
+			BckProps: &cmn.BucketProps{NextTierURL: ts.URL, ReadPolicy: cmn.RWPolicyCloud}}, 0, false},
+	}
+	for i, test := range tests {
+		objmeta, errstr, _ := tgt.headFromTier(test.lom, test.hops, false /*checkCached*/)
+		if errstr != "" {
+			t.Fatalf("test case %d: %s", i, errstr)
+		}
+		if found := objmeta != nil; found != test.found {
+			t.Errorf("test case %d: expecting found=%t, got %t", i, test.found, found)
+		} else if found && (objmeta[cmn.HeaderObjSize] != "1024" || objmeta[cmn.HeaderObjVersion] != "3") {
+			t.Errorf("test case %d: unexpected properties %v", i, objmeta)
+		}
+	}
+}
+
+func TestDemote(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		objs    = make(map[string]int64) // next tier: objname => size
+		puts    int
+		failPut bool
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		objname := r.URL.Path[len(cmn.URLPath(cmn.Version, cmn.Objects, scrubBucket))+1:]
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodHead:
+			size, ok := objs[objname]
+			if !ok {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+			w.Header().Set(cmn.HeaderObjSize, strconv.FormatInt(size, 10))
+		case http.MethodPut:
+			puts++
+			if failPut {
+				http.Error(w, "failed to PUT", http.StatusInternalServerError)
+				return
+			}
+			size, _ := io.Copy(ioutil.Discard, r.Body)
+			objs[objname] = size
+		}
+	}))
+	defer ts.Close()
+
+	tgt, cleanup := newScrubTarget(t)
+	defer cleanup()
+	tgt.httpclientLongTimeout = &http.Client{}
+	tgt.bmdowner.get().LBmap[scrubBucket].NextTierURL = ts.URL
+	data := []byte("demoted")
+
+	// sent and verified
+	lom := scrubPut(t, tgt, "demote", data, false)
+	if err := tgt.demote(lom); err != nil {
+		t.Fatal(err)
+	}
+	if objs["demote"] != int64(len(data)) || puts != 1 {
+		t.Fatalf("expecting the object in the next tier, got %v (%d PUTs)", objs, puts)
+	}
+	// already there: not sent again
+	if err := tgt.demote(lom); err != nil || puts != 1 {
+		t.Fatalf("expecting no PUT, got %d PUTs (err: %v)", puts, err)
+	}
+
+	// the next tier fails (or does not have the object after all)
+	failPut = true
+	lom = scrubPut(t, tgt, "fail", data, false)
+	if err := tgt.demote(lom); err == nil {
+		t.Fatal("expecting demotion to fail")
+	}
+	if _, err := os.Stat(lom.FQN); err != nil || puts != 2 {
+		t.Fatalf("expecting the object to stay (%d PUTs, err: %v)", puts, err)
+	}
+
+	// unreachable
+	ts.Close()
+	lom = scrubPut(t, tgt, "unreachable", data, false)
+	if err := tgt.demote(lom); err == nil {
+		t.Fatal("expecting demotion to fail")
+	}
+}
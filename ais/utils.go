@@ -7,6 +7,7 @@ package ais
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"strconv"
@@ -173,6 +174,15 @@ func parsebool(s string) (value bool, err error) {
 	return
 }
 
+// setConfigQuery returns the named values of the setconfig request ("?n1=v1&n2=v2...")
+// and whether the request is a dry-run - only to validate the values
+func setConfigQuery(r *http.Request) (kvs cmn.SimpleKVs, dryRun bool) {
+	query := r.URL.Query()
+	dryRun, _ = parsebool(query.Get(cmn.URLParamDryRun))
+	query.Del(cmn.URLParamDryRun)
+	return cmn.NewSimpleKVsFromQuery(query), dryRun
+}
+
 // versioningConfigured returns true if versioning for a given bucket is enabled
 // NOTE:
 //    AWS bucket versioning can be disabled on the cloud. In this case we do not
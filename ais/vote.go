@@ -331,10 +331,17 @@ func (p *proxyrunner) doProxyElection(vr *VoteRecord, curPrimary *cluster.Snode,
 }
 
 func (p *proxyrunner) electAmongProxies(vr *VoteRecord) (winner bool, errors map[string]bool) {
-	// Simple Majority Vote
-	resch := p.requestVotes(vr)
+	return countVotes(vr, p.smapowner.get(), p.requestVotes(vr))
+}
+
+// countVotes requires both the simple majority of the votes and the quorum:
+// YES votes from the majority of the last known proxies (see quorum.go)
+func countVotes(vr *VoteRecord, smap *smapX, resch chan voteResult) (winner bool, errors map[string]bool) {
+	var (
+		y, n = 0, 0
+		py   = 1 // proxies voting YES (self included)
+	)
 	errors = make(map[string]bool)
-	y, n := 0, 0
 
 	for res := range resch {
 		if res.err != nil {
@@ -355,6 +362,9 @@ func (p *proxyrunner) electAmongProxies(vr *VoteRecord) (winner bool, errors map
 			}
 			if res.yes {
 				y++
+				if smap.GetProxy(res.daemonID) != nil {
+					py++
+				}
 			} else {
 				n++
 			}
@@ -362,6 +372,10 @@ func (p *proxyrunner) electAmongProxies(vr *VoteRecord) (winner bool, errors map
 	}
 
 	winner = y > n || (y+n == 0) // No Votes: Default Winner
+	if q := quorum(smap.CountProxies()); py < q {
+		glog.Errorf("No quorum: %d out of %d proxies (self included) voted YES, %d required", py, smap.CountProxies(), q)
+		winner = false
+	}
 	glog.Infof("Vote Results:\n Y: %v, N:%v\n Victory: %v\n", y, n, winner)
 	return
 }
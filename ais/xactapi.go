@@ -0,0 +1,303 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+//
+// Unified xaction API: /v1/xactions
+//
+// GET  ?kind=&bucket=&xid=&all=&since=&until=   - list xactions (proxy: cluster-wide, by target)
+// PUT  {"action": "xactstart", "value": XactMsg} - start xaction of a given kind
+// PUT  {"action": "xactstop", "value": XactMsg}  - abort xaction(s) by (target, ID) or kind
+//
+// Xaction IDs are assigned by targets and are unique only within a given target -
+// hence, aborting by ID requires the target ID as well (and only the latter gets
+// the request); targets keep (and persist across restarts) the history of finished
+// xactions that gets reported with all=true (see maxFinishedXacts and cmn.XactHistoryFile).
+//
+
+// the kinds that can be started via /v1/xactions - other xactions (put-copies,
+// EC, download, dsort, prefetch, resync) require kind-specific parameters and are
+// started by their respective APIs or on demand (see docs/xaction.md)
+var xactStartable = map[string]bool{
+	cmn.ActGlobalReb:   false, // bucket not applicable
+	cmn.ActLocalReb:    false,
+	cmn.ActLRU:         false,
+	cmn.ActRechecksum:  true, // bucket required
+	cmn.ActEraseCopies: true,
+	cmn.ActScrub:       false,
+	cmn.ActGC:          false,
+}
+
+func xactMsgFromAction(msg *cmn.ActionMsg) (xmsg *cmn.XactMsg, errstr string) {
+	xmsg = &cmn.XactMsg{}
+	b, err := jsoniter.Marshal(msg.Value)
+	if err == nil {
+		err = jsoniter.Unmarshal(b, xmsg)
+	}
+	if err != nil {
+		errstr = fmt.Sprintf("%s: invalid value (%+v, %T), err: %v", msg.Action, msg.Value, msg.Value, err)
+		return
+	}
+	switch msg.Action {
+	case cmn.ActXactStart:
+		bucketRequired, ok := xactStartable[xmsg.Kind]
+		if !ok {
+			errstr = fmt.Sprintf("%s: %q xaction cannot be started via /%s - use the respective API (startable kinds: %s)",
+				msg.Action, xmsg.Kind, cmn.Xactions, strings.Join(xactStartableKinds(), ", "))
+		} else if bucketRequired && xmsg.Bucket == "" {
+			errstr = fmt.Sprintf("%s: %q xaction requires bucket", msg.Action, xmsg.Kind)
+		}
+	case cmn.ActXactStop:
+		if xmsg.ID == 0 && xmsg.Kind == "" {
+			errstr = fmt.Sprintf("%s: xaction ID or kind must be specified", msg.Action)
+		} else if xmsg.ID != 0 && xmsg.Target == "" {
+			errstr = fmt.Sprintf("%s: xaction ID %d requires target ID (xaction IDs are unique only within a target)",
+				msg.Action, xmsg.ID)
+		}
+	default:
+		errstr = fmt.Sprintf("Unexpected cmn.ActionMsg <- JSON [%v]", msg)
+	}
+	return
+}
+
+func xactStartableKinds() (kinds []string) {
+	kinds = make([]string, 0, len(xactStartable))
+	for kind := range xactStartable {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return
+}
+
+func xactMsgFromQuery(r *http.Request) (xmsg *cmn.XactMsg, all bool, errstr string) {
+	var (
+		err   error
+		query = r.URL.Query()
+	)
+	xmsg = &cmn.XactMsg{Kind: query.Get(cmn.URLParamXactKind), Bucket: query.Get(cmn.URLParamXactBucket)}
+	if s := query.Get(cmn.URLParamXactID); s != "" {
+		if xmsg.ID, err = strconv.ParseInt(s, 10, 64); err != nil {
+			errstr = fmt.Sprintf("invalid %s=%s, err: %v", cmn.URLParamXactID, s, err)
+			return
+		}
+	}
+	if s := query.Get(cmn.URLParamXactAll); s != "" {
+		if all, err = strconv.ParseBool(s); err != nil {
+			errstr = fmt.Sprintf("invalid %s=%s, err: %v", cmn.URLParamXactAll, s, err)
+			return
+		}
+	}
+	if s := query.Get(cmn.URLParamSince); s != "" {
+		if xmsg.Since, err = time.Parse(time.RFC3339Nano, s); err != nil {
+			errstr = fmt.Sprintf("invalid %s=%s, err: %v", cmn.URLParamSince, s, err)
+			return
+		}
+	}
+	if s := query.Get(cmn.URLParamUntil); s != "" {
+		if xmsg.Until, err = time.Parse(time.RFC3339Nano, s); err != nil {
+			errstr = fmt.Sprintf("invalid %s=%s, err: %v", cmn.URLParamUntil, s, err)
+		}
+	}
+	return
+}
+
+//
+// proxy
+//
+
+func (p *proxyrunner) xactHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		p.httpxactget(w, r)
+	case http.MethodPut:
+		p.httpxactput(w, r)
+	default:
+		cmn.InvalidHandlerWithMsg(w, r, "invalid method for /xactions path")
+	}
+}
+
+// GET /v1/xactions: target ID => the target's xactions
+func (p *proxyrunner) httpxactget(w http.ResponseWriter, r *http.Request) {
+	if _, err := p.checkRESTItems(w, r, 0, false, cmn.Version, cmn.Xactions); err != nil {
+		return
+	}
+	if _, _, errstr := xactMsgFromQuery(r); errstr != "" {
+		p.invalmsghdlr(w, r, errstr)
+		return
+	}
+	smap := p.smapowner.get()
+	results := p.broadcastTo(
+		cmn.URLPath(cmn.Version, cmn.Xactions),
+		r.URL.Query(),
+		r.Method,
+		nil, // message
+		smap,
+		cmn.GCO.Get().Timeout.Default,
+		cmn.NetworkIntraControl,
+		cluster.Targets,
+	)
+	targetResults := make(map[string]jsoniter.RawMessage, smap.CountTargets())
+	for result := range results {
+		if result.err != nil {
+			p.invalmsghdlr(w, r, result.errstr)
+			return
+		}
+		targetResults[result.si.DaemonID] = jsoniter.RawMessage(result.outjson)
+	}
+	jsbytes, err := jsoniter.Marshal(targetResults)
+	cmn.AssertNoErr(err)
+	p.writeJSON(w, r, jsbytes, "getXactions")
+}
+
+// PUT /v1/xactions: start or abort
+func (p *proxyrunner) httpxactput(w http.ResponseWriter, r *http.Request) {
+	var msg cmn.ActionMsg
+	if _, err := p.checkRESTItems(w, r, 0, false, cmn.Version, cmn.Xactions); err != nil {
+		return
+	}
+	if cmn.ReadJSON(w, r, &msg) != nil {
+		return
+	}
+	xmsg, errstr := xactMsgFromAction(&msg)
+	if errstr != "" {
+		p.invalmsghdlr(w, r, errstr)
+		return
+	}
+	if p.forwardCP(w, r, &msg, xmsg.Kind, nil) {
+		return
+	}
+	// global rebalance gets started by the cluster map (see ActGlobalReb in httpcluput)
+	if msg.Action == cmn.ActXactStart && xmsg.Kind == cmn.ActGlobalReb {
+		smap := p.smapowner.get()
+		msgInt := p.newActionMsgInternal(&cmn.ActionMsg{Action: cmn.ActGlobalReb}, smap, nil)
+		p.metasyncer.sync(false, smap, msgInt)
+		return
+	}
+	body, err := jsoniter.Marshal(&msg)
+	cmn.AssertNoErr(err)
+	smap := p.smapowner.get()
+	// abort by ID: the one target that has it
+	if xmsg.ID != 0 {
+		si := smap.GetTarget(xmsg.Target)
+		if si == nil {
+			p.invalmsghdlr(w, r, fmt.Sprintf("%s(%+v): unknown target %s", msg.Action, *xmsg, xmsg.Target), http.StatusNotFound)
+			return
+		}
+		args := callArgs{
+			si: si,
+			req: reqArgs{
+				method: http.MethodPut,
+				path:   cmn.URLPath(cmn.Version, cmn.Xactions),
+				body:   body,
+			},
+			timeout: cmn.GCO.Get().Timeout.CplaneOperation,
+		}
+		if res := p.call(args); res.err != nil {
+			p.invalmsghdlr(w, r, fmt.Sprintf("%s(%+v): %s failed, err: %s", msg.Action, *xmsg, si, res.errstr))
+		}
+		return
+	}
+	results := p.broadcastTo(
+		cmn.URLPath(cmn.Version, cmn.Xactions),
+		nil, // query
+		http.MethodPut,
+		body,
+		smap,
+		cmn.GCO.Get().Timeout.CplaneOperation,
+		cmn.NetworkIntraControl,
+		cluster.Targets,
+	)
+	for result := range results {
+		if result.err != nil {
+			p.invalmsghdlr(w, r, fmt.Sprintf("%s(%+v): %s failed, err: %s", msg.Action, *xmsg, result.si, result.errstr))
+			return
+		}
+	}
+}
+
+//
+// target
+//
+
+func (t *targetrunner) xactHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		t.httpxactget(w, r)
+	case http.MethodPut:
+		t.httpxactput(w, r)
+	default:
+		cmn.InvalidHandlerWithMsg(w, r, "invalid method for /xactions path")
+	}
+}
+
+// GET /v1/xactions: this target's xactions
+func (t *targetrunner) httpxactget(w http.ResponseWriter, r *http.Request) {
+	if _, err := t.checkRESTItems(w, r, 0, false, cmn.Version, cmn.Xactions); err != nil {
+		return
+	}
+	xmsg, all, errstr := xactMsgFromQuery(r)
+	if errstr != "" {
+		t.invalmsghdlr(w, r, errstr)
+		return
+	}
+	jsbytes, err := jsoniter.Marshal(t.xactions.list(xmsg, all))
+	cmn.AssertNoErr(err)
+	t.writeJSON(w, r, jsbytes, "getXactions")
+}
+
+// PUT /v1/xactions
+func (t *targetrunner) httpxactput(w http.ResponseWriter, r *http.Request) {
+	var msg cmn.ActionMsg
+	if _, err := t.checkRESTItems(w, r, 0, false, cmn.Version, cmn.Xactions); err != nil {
+		return
+	}
+	if cmn.ReadJSON(w, r, &msg) != nil {
+		return
+	}
+	xmsg, errstr := xactMsgFromAction(&msg)
+	if errstr != "" {
+		t.invalmsghdlr(w, r, errstr)
+		return
+	}
+	if msg.Action == cmn.ActXactStop {
+		if xmsg.ID != 0 && xmsg.Target != t.si.DaemonID {
+			t.invalmsghdlr(w, r, fmt.Sprintf("%s(%+v): not %s", msg.Action, *xmsg, t.si))
+			return
+		}
+		cnt := t.xactions.abortL(xmsg, "user request")
+		glog.Infof("%s: %s(%+v) - aborted %d", t.si, msg.Action, *xmsg, cnt)
+		return
+	}
+	switch xmsg.Kind {
+	case cmn.ActLRU:
+		go t.RunLRU()
+	case cmn.ActLocalReb:
+		go t.rebManager.runLocalReb()
+	case cmn.ActRechecksum:
+		go t.runRechecksumBucket(xmsg.Bucket)
+	case cmn.ActEraseCopies:
+		t.eraseCopies(xmsg.Bucket)
+	case cmn.ActScrub:
+		go t.runScrub()
+	case cmn.ActGC:
+		go t.runGC()
+	default: // global rebalance is started by the primary via metasync
+		t.invalmsghdlr(w, r, fmt.Sprintf("%s: %q xaction is started by the primary proxy", msg.Action, xmsg.Kind))
+	}
+}
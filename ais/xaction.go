@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -20,15 +21,22 @@ import (
 	"github.com/NVIDIA/aistore/downloader"
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/governor"
 	"github.com/NVIDIA/aistore/mirror"
 )
 
 type (
 	xactions struct {
 		sync.Mutex
-		v       []cmn.Xact
-		nextid  int64
-		cleanup bool
+		v        []cmn.Xact
+		finished []cmn.XactStats // the most recently finished xactions, oldest first (see _cleanup)
+		pathname string          // where to persist the finished ones; empty - not to persist (proxies)
+		nextid   int64
+		cleanup  bool
+		// persisting the history outside xactions lock (see _cleanup and persist)
+		histMtx  sync.Mutex
+		histVer  int64 // under xactions lock
+		savedVer int64 // under histMtx
 	}
 	xactRebBase struct {
 		cmn.XactBase
@@ -60,8 +68,18 @@ type (
 		cmn.XactBase
 		bucket string
 	}
+	xactGC struct {
+		cmn.XactBase
+	}
+	xactResync struct {
+		cmn.XactBase
+		mu     sync.Mutex
+		report cmn.ResyncReport
+	}
 )
 
+const maxFinishedXacts = 256 // finished xactions to keep, persist, and report (see GET /v1/xactions)
+
 func makeXactRebBase(id int64, rebType int, runnerCnt int) xactRebBase {
 	kind := ""
 	switch rebType {
@@ -91,13 +109,36 @@ func newXs() *xactions {
 	return xs
 }
 
+// init (targets only) loads the history of finished xactions, if any
+func (xs *xactions) init(confdir string) {
+	xs.pathname = filepath.Join(confdir, cmn.XactHistoryFile)
+	finished := make([]cmn.XactStats, 0, maxFinishedXacts)
+	if err := cmn.LocalLoad(xs.pathname, &finished); err != nil {
+		if !os.IsNotExist(err) {
+			glog.Errorf("Failed to load xaction history %s, err: %v", xs.pathname, err)
+		}
+		return
+	}
+	xs.Lock()
+	xs.finished = finished
+	// IDs of the new xactions must not repeat the historic ones
+	for _, st := range finished {
+		if st.ID > xs.nextid {
+			xs.nextid = st.ID
+		}
+	}
+	xs.Unlock()
+}
+
 func (xs *xactions) uniqueid() int64   { xs.nextid++; return xs.nextid } // under lock
 func (xs *xactions) add(xact cmn.Xact) { xs.v = append(xs.v, xact) }     // ditto
 
 func (xs *xactions) findU(kind string) (xact cmn.Xact) {
 	if xs.cleanup {
 		// TODO: keep longer, e.g. until creation of same kind
-		xs._cleanup()
+		if xs._cleanup() {
+			go xs.persist(xs._history())
+		}
 	}
 	for _, x := range xs.v {
 		if x.Finished() {
@@ -112,14 +153,57 @@ func (xs *xactions) findU(kind string) (xact cmn.Xact) {
 	return
 }
 
-func (xs *xactions) _cleanup() {
-	for i := len(xs.v) - 1; i >= 0; i-- {
+// _cleanup removes finished xactions while keeping their final stats; returns
+// true if the history has changed and is to be persisted (outside the lock)
+func (xs *xactions) _cleanup() (changed bool) {
+	var cnt int
+	for i := 0; i < len(xs.v); {
 		x := xs.v[i]
-		if x.Finished() {
-			xs.delAt(i)
+		if !x.Finished() {
+			i++
+			continue
 		}
+		xs.finished = append(xs.finished, x.Snapshot())
+		xs.delAt(i)
+		cnt++
+	}
+	if l := len(xs.finished); l > maxFinishedXacts {
+		xs.finished = append(xs.finished[:0], xs.finished[l-maxFinishedXacts:]...)
 	}
 	xs.cleanup = false
+	if cnt > 0 && xs.pathname != "" {
+		xs.histVer++
+		changed = true
+	}
+	return
+}
+
+// _history returns the copy of the current history and its version
+func (xs *xactions) _history() ([]cmn.XactStats, int64) {
+	return append(make([]cmn.XactStats, 0, len(xs.finished)), xs.finished...), xs.histVer
+}
+
+// persist stores the history unless the same or newer version has been stored already
+func (xs *xactions) persist(hist []cmn.XactStats, ver int64) {
+	xs.histMtx.Lock()
+	defer xs.histMtx.Unlock()
+	if xs.pathname == "" || ver <= xs.savedVer {
+		return
+	}
+	if err := cmn.LocalSave(xs.pathname, hist); err != nil {
+		glog.Errorf("Failed to store xaction history %s, err: %v", xs.pathname, err)
+		return
+	}
+	xs.savedVer = ver
+}
+
+// flush moves the finished xactions to the (persistent) history right away
+func (xs *xactions) flush() {
+	xs.Lock()
+	xs._cleanup()
+	hist, ver := xs._history()
+	xs.Unlock()
+	xs.persist(hist, ver)
 }
 
 func (xs *xactions) findL(kind string) (idx int, xact cmn.Xact) {
@@ -143,6 +227,75 @@ func (xs *xactions) selectL(kind string) (matching []cmn.Xact) {
 	return
 }
 
+// list returns the stats of the matching xactions: running (and not yet cleaned up)
+// followed by the recently finished ones, if requested
+func (xs *xactions) list(msg *cmn.XactMsg, all bool) []cmn.XactStats {
+	out := make([]cmn.XactStats, 0, 8)
+	xs.Lock()
+	for _, x := range xs.v {
+		st := x.Snapshot()
+		if xactMatch(msg, &st) && (all || !x.Finished()) {
+			out = append(out, st)
+		}
+	}
+	if all {
+		for i := range xs.finished {
+			if xactMatch(msg, &xs.finished[i]) {
+				out = append(out, xs.finished[i])
+			}
+		}
+	}
+	xs.Unlock()
+	return out
+}
+
+// activeByPrio counts running xactions by priority class (see governor.Governor)
+func (xs *xactions) activeByPrio() (active [governor.NumPrio]int) {
+	xs.Lock()
+	for _, x := range xs.v {
+		if !x.Finished() {
+			active[governor.KindPrio(x.Kind())]++
+		}
+	}
+	xs.Unlock()
+	return
+}
+
+// abortL aborts the matching running xactions and returns their number
+func (xs *xactions) abortL(msg *cmn.XactMsg, reason string) (cnt int) {
+	xs.Lock()
+	for _, x := range xs.v {
+		if x.Finished() {
+			continue
+		}
+		st := x.Snapshot()
+		if xactMatch(msg, &st) {
+			x.AbortWith(reason)
+			cnt++
+		}
+	}
+	xs.Unlock()
+	return
+}
+
+// xactMatch filters by ID, if specified, or else by kind, bucket, and the time interval
+// the xaction was running; bucket-specific kinds (e.g. "rechecksum/<bucket>") match their generic kind
+func xactMatch(msg *cmn.XactMsg, st *cmn.XactStats) bool {
+	if msg.ID != 0 {
+		return st.ID == msg.ID
+	}
+	if msg.Kind != "" && st.Kind != msg.Kind && path.Dir(st.Kind) != msg.Kind {
+		return false
+	}
+	if msg.Bucket != "" && st.Bucket != msg.Bucket {
+		return false
+	}
+	if !msg.Until.IsZero() && !st.StartTime.Before(msg.Until) {
+		return false
+	}
+	return msg.Since.IsZero() || st.EndTime.IsZero() || !st.EndTime.Before(msg.Since)
+}
+
 func (xs *xactions) delAt(k int) {
 	l := len(xs.v)
 	if k < l-1 {
@@ -167,7 +320,7 @@ func (xs *xactions) renewGlobalReb(smapVersion int64, runnerCnt int) *xactRebBas
 			xs.Unlock()
 			return nil
 		}
-		xGlobalReb.Abort()
+		xGlobalReb.AbortWith(fmt.Sprintf("superseded by Smap v%d", smapVersion))
 		for i := 0; i < xGlobalReb.runnerCnt; i++ {
 			<-xGlobalReb.confirmCh
 		}
@@ -226,7 +379,7 @@ func (xs *xactions) renewLocalReb(runnerCnt int) *xactRebBase {
 	xx := xs.findU(cmn.ActLocalReb)
 	if xx != nil {
 		xLocalReb := xx.(*xactLocalReb)
-		xLocalReb.Abort()
+		xLocalReb.AbortWith("restarted")
 		for i := 0; i < xLocalReb.runnerCnt; i++ {
 			<-xLocalReb.confirmCh
 		}
@@ -318,12 +471,53 @@ func (xs *xactions) renewRechecksum(bucket string) *xactRechecksum {
 		return nil
 	}
 	id := xs.uniqueid()
-	xrcksum := &xactRechecksum{XactBase: *cmn.NewXactBase(id, kind), bucket: bucket}
+	xrcksum := &xactRechecksum{XactBase: *cmn.NewXactBase(id, kind, bucket), bucket: bucket}
 	xs.add(xrcksum)
 	xs.Unlock()
 	return xrcksum
 }
 
+func (xs *xactions) renewScrub() *xactScrub {
+	xs.Lock()
+	defer xs.Unlock()
+	if xx := xs.findU(cmn.ActScrub); xx != nil {
+		glog.Infof("%s already running, nothing to do", xx)
+		return nil
+	}
+	id := xs.uniqueid()
+	xscrub := &xactScrub{XactBase: *cmn.NewXactBase(id, cmn.ActScrub)}
+	xs.add(xscrub)
+	return xscrub
+}
+
+func (xs *xactions) renewGC() *xactGC {
+	xs.Lock()
+	defer xs.Unlock()
+	if xx := xs.findU(cmn.ActGC); xx != nil {
+		glog.Infof("%s already running, nothing to do", xx)
+		return nil
+	}
+	id := xs.uniqueid()
+	xgc := &xactGC{XactBase: *cmn.NewXactBase(id, cmn.ActGC)}
+	xs.add(xgc)
+	return xgc
+}
+
+func (xs *xactions) renewResync(bucket string) *xactResync {
+	kind := path.Join(cmn.ActResync, bucket)
+	xs.Lock()
+	defer xs.Unlock()
+	xx := xs.findU(kind)
+	if xx != nil {
+		glog.Infof("%s already running for bucket %s, nothing to do", xx, bucket)
+		return nil
+	}
+	id := xs.uniqueid()
+	xresync := &xactResync{XactBase: *cmn.NewXactBase(id, kind, bucket)}
+	xs.add(xresync)
+	return xresync
+}
+
 func (xs *xactions) renewPutCopies(lom *cluster.LOM, t *targetrunner) (xcopy *mirror.XactCopy) {
 	kindput := path.Join(cmn.ActPutCopies, lom.Bucket)
 	xs.Lock()
@@ -371,7 +565,7 @@ func (xs *xactions) abortPutCopies(bucket string) {
 	xs.Lock()
 	xx := xs.findU(kindput)
 	if xx != nil {
-		xx.Abort()
+		xx.AbortWith("mirroring disabled")
 	}
 	xs.Unlock()
 }
@@ -415,7 +609,7 @@ func (xs *xactions) abortBucketSpecific(bucket string) {
 			if xx == nil {
 				return
 			}
-			xx.Abort()
+			xx.AbortWith("bucket destroyed")
 			for i := 0; i < 5; i++ {
 				time.Sleep(time.Millisecond * 500)
 				if xx.Finished() {
@@ -450,7 +644,7 @@ func (xs *xactions) abortAll() (sleep bool) {
 	xs.Lock()
 	for _, xact := range xs.v {
 		if !xact.Finished() {
-			xact.Abort()
+			xact.AbortWith("shutdown")
 			sleep = true
 		}
 	}
@@ -0,0 +1,173 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+func TestXactionsListAbort(t *testing.T) {
+	xs := newXs()
+	xlru := xs.renewLRU()
+	xs.renewRechecksum("b1")
+	xs.renewRechecksum("b2")
+
+	if l := xs.list(&cmn.XactMsg{}, false); len(l) != 3 {
+		t.Fatalf("expecting 3 running xactions, got %d", len(l))
+	}
+	l := xs.list(&cmn.XactMsg{Kind: cmn.ActRechecksum, Bucket: "b2"}, false)
+	if len(l) != 1 || l[0].Bucket != "b2" || l[0].Status != cmn.XactionStatusInProgress {
+		t.Fatalf("expecting b2 %s in progress, got %+v", cmn.ActRechecksum, l)
+	}
+
+	xlru.ObjectsAdd(5)
+	xlru.BytesAdd(cmn.KiB)
+	if cnt := xs.abortL(&cmn.XactMsg{ID: xlru.ID()}, "test"); cnt != 1 {
+		t.Fatalf("expecting 1 aborted xaction, got %d", cnt)
+	}
+	xlru.Abort() // must be idempotent
+	if cnt := xs.abortL(&cmn.XactMsg{Kind: cmn.ActLRU}, "test"); cnt != 0 {
+		t.Fatalf("expecting nothing to abort, got %d", cnt)
+	}
+
+	xs.findL(cmn.ActLRU) // cleanup
+	xs.findL(cmn.ActLRU)
+	if l := xs.list(&cmn.XactMsg{Kind: cmn.ActLRU}, false); len(l) != 0 {
+		t.Fatalf("expecting no running %s, got %+v", cmn.ActLRU, l)
+	}
+	l = xs.list(&cmn.XactMsg{Kind: cmn.ActLRU}, true)
+	if len(l) != 1 || l[0].Status != cmn.XactionStatusAborted || l[0].Reason != "test" ||
+		l[0].Objects != 5 || l[0].Bytes != cmn.KiB {
+		t.Fatalf("expecting aborted %s with 5 objects, got %+v", cmn.ActLRU, l)
+	}
+}
+
+func TestXactionTotal(t *testing.T) {
+	xs := newXs()
+	xlru := xs.renewLRU()
+	if stats := xlru.Snapshot(); stats.Total != 0 || stats.ETA != 0 {
+		t.Fatalf("expecting unknown total, got %+v", stats)
+	}
+	// e.g., two LRU joggers that have selected the objects to evict
+	xlru.TotalAdd(6)
+	xlru.TotalAdd(4)
+	xlru.ObjectsAdd(5)
+	if stats := xlru.Snapshot(); stats.Total != 10 || stats.ETA <= 0 {
+		t.Fatalf("expecting total 10 and ETA, got %+v", stats)
+	}
+	xlru.ObjectsAdd(5)
+	if stats := xlru.Snapshot(); stats.ETA != 0 {
+		t.Fatalf("expecting no ETA once done, got %+v", stats)
+	}
+}
+
+func TestXactionHistoryPersisted(t *testing.T) {
+	confdir, err := ioutil.TempDir("", "xactions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(confdir)
+
+	xs := newXs()
+	xs.init(confdir)
+	xs.renewRechecksum("b1").AbortWith("test")
+	xlru := xs.renewLRU()
+	xlru.ObjectsAdd(3)
+	xlru.EndTime(time.Now())
+	xs.renewLocalReb(0) // still running
+	xs.flush()
+
+	// restart
+	xs = newXs()
+	xs.init(confdir)
+	if l := xs.list(&cmn.XactMsg{}, true); len(l) != 2 {
+		t.Fatalf("expecting 2 finished xactions, got %+v", l)
+	}
+	l := xs.list(&cmn.XactMsg{Kind: cmn.ActRechecksum, Bucket: "b1"}, true)
+	if len(l) != 1 || l[0].Status != cmn.XactionStatusAborted || l[0].Reason != "test" {
+		t.Fatalf("expecting aborted %s, got %+v", cmn.ActRechecksum, l)
+	}
+	l = xs.list(&cmn.XactMsg{Kind: cmn.ActLRU}, true)
+	if len(l) != 1 || l[0].Status != cmn.XactionStatusCompleted || l[0].Objects != 3 {
+		t.Fatalf("expecting completed %s, got %+v", cmn.ActLRU, l)
+	}
+	if l = xs.list(&cmn.XactMsg{Since: time.Now()}, true); len(l) != 0 {
+		t.Errorf("expecting no xactions running since now, got %+v", l)
+	}
+	if l = xs.list(&cmn.XactMsg{Until: time.Now().Add(-time.Hour)}, true); len(l) != 0 {
+		t.Errorf("expecting no xactions running an hour ago, got %+v", l)
+	}
+
+	// new IDs do not repeat the historic ones
+	xs.Lock()
+	xs.nextid = 0
+	xs.Unlock()
+	xs.init(confdir)
+	if id := xs.renewLRU().ID(); id <= xlru.ID() {
+		t.Errorf("expecting new xaction ID > %d, got %d", xlru.ID(), id)
+	}
+}
+
+func TestXactScrubStats(t *testing.T) {
+	xs := newXs()
+	xscrub := xs.renewScrub()
+	if xscrub == nil || xs.renewScrub() != nil {
+		t.Fatalf("expecting a single %s xaction", cmn.ActScrub)
+	}
+	xscrub.newPass(1, cmn.ScrubRepair, true)
+	xscrub.checked(cmn.KiB)
+	for i := 0; i < maxScrubFindings+5; i++ {
+		xscrub.found(cmn.ScrubWorkfile, fmt.Sprintf("/tmp/work/%d", i), cmn.ScrubActRemoved, "")
+	}
+	xscrub.found(cmn.ScrubCorrupted, "/tmp/obj", cmn.ScrubActRepaired, "restored from mirror")
+
+	l := xs.list(&cmn.XactMsg{Kind: cmn.ActScrub}, false)
+	if len(l) != 1 || l[0].Scrub == nil {
+		t.Fatalf("expecting %s stats, got %+v", cmn.ActScrub, l)
+	}
+	st := l[0].Scrub
+	if st.Pass != 1 || !st.Resumed || st.Policy != cmn.ScrubRepair || st.Checked != 1 || l[0].Bytes != cmn.KiB {
+		t.Fatalf("unexpected progress: %+v", st)
+	}
+	if st.Workfiles != maxScrubFindings+5 || st.Removed != maxScrubFindings+5 || st.Corrupted != 1 || st.Repaired != 1 {
+		t.Fatalf("unexpected counters: %+v", st)
+	}
+	if len(st.Findings) != maxScrubFindings || st.Findings[0].FQN != "/tmp/work/6" ||
+		st.Findings[maxScrubFindings-1].Type != cmn.ScrubCorrupted {
+		t.Fatalf("expecting the %d most recent findings, got %d (%s ... %s)",
+			maxScrubFindings, len(st.Findings), st.Findings[0].FQN, st.Findings[len(st.Findings)-1].FQN)
+	}
+}
+
+func TestXactMsgFromAction(t *testing.T) {
+	tests := []struct {
+		action string
+		xmsg   cmn.XactMsg
+		valid  bool
+	}{
+		{cmn.ActXactStart, cmn.XactMsg{Kind: cmn.ActLRU}, true},
+		{cmn.ActXactStart, cmn.XactMsg{Kind: cmn.ActRechecksum}, false},
+		{cmn.ActXactStart, cmn.XactMsg{Kind: cmn.ActRechecksum, Bucket: "b"}, true},
+		{cmn.ActXactStart, cmn.XactMsg{Kind: cmn.ActEraseCopies}, false},
+		{cmn.ActXactStart, cmn.XactMsg{Kind: cmn.ActEraseCopies, Bucket: "b"}, true},
+		{cmn.ActXactStart, cmn.XactMsg{Kind: cmn.ActPutCopies}, false},
+		{cmn.ActXactStop, cmn.XactMsg{}, false},
+		{cmn.ActXactStop, cmn.XactMsg{Kind: cmn.ActLRU}, true},
+		{cmn.ActXactStop, cmn.XactMsg{ID: 1}, false}, // IDs are unique only within a target
+		{cmn.ActXactStop, cmn.XactMsg{ID: 1, Target: "t1"}, true},
+	}
+	for _, test := range tests {
+		xmsg := test.xmsg
+		_, errstr := xactMsgFromAction(&cmn.ActionMsg{Action: test.action, Value: &xmsg})
+		if (errstr == "") != test.valid {
+			t.Errorf("%s(%+v): expected valid=%t, got %q", test.action, test.xmsg, test.valid, errstr)
+		}
+	}
+}
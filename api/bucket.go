@@ -174,6 +174,7 @@ func HeadBucket(baseParams *BaseParams, bucket string, query ...url.Values) (*cm
 		LRU:           lruProps,
 		Mirror:        mirrorProps,
 		EC:            ecProps,
+		Placement:     r.Header.Get(cmn.HeaderBucketPlacement),
 	}, nil
 }
 
@@ -339,3 +340,58 @@ func EraseCopies(baseParams *BaseParams, bucket string) error {
 	_, err = DoHTTPRequest(baseParams, path, b)
 	return err
 }
+
+// ResyncBucket API
+//
+// ResyncBucket starts comparing the cached objects of a given cloud bucket with the Cloud
+// and, depending on the message options, evicting, refreshing, and/or prefetching objects;
+// returns the IDs of the respective xactions by target ID (see GetResyncReport and AbortXaction)
+func ResyncBucket(baseParams *BaseParams, bucket string, msg *cmn.ResyncMsg, query ...url.Values) (map[string]int64, error) {
+	var querystr string
+	if len(query) > 0 {
+		querystr = "?" + query[0].Encode()
+	}
+	b, err := jsoniter.Marshal(cmn.ActionMsg{Action: cmn.ActResync, Value: msg})
+	if err != nil {
+		return nil, err
+	}
+	baseParams.Method = http.MethodPost
+	path := cmn.URLPath(cmn.Version, cmn.Buckets, bucket) + querystr
+	respBody, err := DoHTTPRequest(baseParams, path, b)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]int64)
+	if err = jsoniter.Unmarshal(respBody, &ids); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resync xaction IDs, err: %v", err)
+	}
+	return ids, nil
+}
+
+// GetResyncReport API
+//
+// GetResyncReport returns the report of the most recent resync of a given bucket,
+// aggregated over all targets, and the number of targets that are still running it
+func GetResyncReport(baseParams *BaseParams, bucket string) (report *cmn.ResyncReport, running int, err error) {
+	xacts, err := GetXactions(baseParams, &cmn.XactMsg{Kind: cmn.ActResync, Bucket: bucket}, true)
+	if err != nil {
+		return nil, 0, err
+	}
+	report = &cmn.ResyncReport{}
+	for _, list := range xacts {
+		var latest *cmn.XactStats
+		for i := range list {
+			if list[i].Resync != nil && (latest == nil || list[i].StartTime.After(latest.StartTime)) {
+				latest = &list[i]
+			}
+		}
+		if latest == nil {
+			continue
+		}
+		if latest.Status == cmn.XactionStatusInProgress {
+			running++
+		}
+		report.Merge(latest.Resync)
+	}
+	return report, running, nil
+}
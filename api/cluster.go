@@ -10,6 +10,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
@@ -107,6 +110,56 @@ func UnregisterTarget(baseParams *BaseParams, unregisterSID string) error {
 	return err
 }
 
+// StartMaintenance API
+//
+// Puts the target in maintenance for reboot: the target stays in the clustermap,
+// its (temporary) absence does not trigger rebalancing
+func StartMaintenance(baseParams *BaseParams, sid string) error {
+	return targetMaintenance(baseParams, cmn.ActStartMaint, sid)
+}
+
+// StopMaintenance API
+//
+// Takes the target out of maintenance; cancels decommissioning (if in progress)
+func StopMaintenance(baseParams *BaseParams, sid string) error {
+	return targetMaintenance(baseParams, cmn.ActStopMaint, sid)
+}
+
+// DecommissionTarget API
+//
+// Gracefully removes the target from the cluster: the target stops accepting
+// new objects and migrates its content to the rest of the cluster; it leaves
+// the clustermap only after the migration completes
+func DecommissionTarget(baseParams *BaseParams, sid string) error {
+	return targetMaintenance(baseParams, cmn.ActDecommission, sid)
+}
+
+func targetMaintenance(baseParams *BaseParams, action, sid string) error {
+	msg, err := jsoniter.Marshal(cmn.ActionMsg{Action: action, Name: sid})
+	if err != nil {
+		return err
+	}
+	baseParams.Method = http.MethodPut
+	path := cmn.URLPath(cmn.Version, cmn.Cluster)
+	_, err = DoHTTPRequest(baseParams, path, msg)
+	return err
+}
+
+// SetTargetWeight API
+//
+// SetTargetWeight sets the placement weight of the target, overriding the one the
+// target has declared itself (zero weight removes the override); triggers rebalance
+func SetTargetWeight(baseParams *BaseParams, daemonID string, weight int64) error {
+	msg, err := jsoniter.Marshal(cmn.ActionMsg{Action: cmn.ActSetWeight, Name: daemonID, Value: strconv.FormatInt(weight, 10)})
+	if err != nil {
+		return err
+	}
+	baseParams.Method = http.MethodPut
+	path := cmn.URLPath(cmn.Version, cmn.Cluster)
+	_, err = DoHTTPRequest(baseParams, path, msg)
+	return err
+}
+
 // SetPrimaryProxy API
 //
 // Given a daemonID, it sets that corresponding proxy as the primary proxy of the cluster
@@ -121,7 +174,8 @@ func SetPrimaryProxy(baseParams *BaseParams, newPrimaryID string) error {
 //
 // Given a key and a value for a specific configuration parameter
 // this operation sets the cluster-wide configuration accordingly.
-// Setting cluster-wide configuration requires sending the request to a proxy
+// Setting cluster-wide configuration requires sending the request to a proxy;
+// each change produces a new version (see GetClusterConfigHistory)
 func SetClusterConfig(baseParams *BaseParams, key string, value interface{}) error {
 	valstr, err := convertToString(value)
 	if err != nil {
@@ -141,3 +195,192 @@ func SetClusterConfig(baseParams *BaseParams, key string, value interface{}) err
 	_, err = DoHTTPRequest(baseParams, path, msg)
 	return err
 }
+
+// SetNodeConfig API
+//
+// SetNodeConfig sets the given node's override of the cluster-wide configuration value
+func SetNodeConfig(baseParams *BaseParams, daemonID, key string, value interface{}) error {
+	valstr, err := convertToString(value)
+	if err != nil {
+		return err
+	}
+	return updateClusterConf(baseParams, daemonID, cmn.ActionMsg{Action: cmn.ActSetConfig, Name: key, Value: valstr})
+}
+
+// UnsetClusterConfig API
+//
+// UnsetClusterConfig removes the cluster-wide configuration value or, if daemonID is not empty,
+// the node's override; the nodes then revert to their own (local) configuration
+func UnsetClusterConfig(baseParams *BaseParams, daemonID, key string) error {
+	return updateClusterConf(baseParams, daemonID, cmn.ActionMsg{Action: cmn.ActUnsetConfig, Name: key})
+}
+
+// RollbackClusterConfig API
+//
+// RollbackClusterConfig makes the given previous version of the cluster-wide configuration
+// current again (as a new version)
+func RollbackClusterConfig(baseParams *BaseParams, version int64) error {
+	return updateClusterConf(baseParams, "", cmn.ActionMsg{Action: cmn.ActRollbackConfig, Value: version})
+}
+
+// ReloadClusterConfig API
+//
+// ReloadClusterConfig makes all nodes reload their configuration files (see ReloadDaemonConfig)
+// and returns the results by daemon ID
+func ReloadClusterConfig(baseParams *BaseParams, dryRun bool) (reloads map[string]*cmn.ConfigReload, err error) {
+	err = reloadConfig(baseParams, cmn.URLPath(cmn.Version, cmn.Cluster), dryRun, &reloads)
+	return
+}
+
+func updateClusterConf(baseParams *BaseParams, daemonID string, actMsg cmn.ActionMsg) error {
+	msg, err := jsoniter.Marshal(actMsg)
+	if err != nil {
+		return err
+	}
+	var optParams OptionalParams
+	if daemonID != "" {
+		optParams.Query = url.Values{cmn.URLParamNode: []string{daemonID}}
+	}
+	baseParams.Method = http.MethodPut
+	path := cmn.URLPath(cmn.Version, cmn.Cluster)
+	_, err = DoHTTPRequest(baseParams, path, msg, optParams)
+	return err
+}
+
+// GetClusterConfig API
+//
+// GetClusterConfig returns the given version of the cluster-wide configuration (zero - current)
+func GetClusterConfig(baseParams *BaseParams, version int64) (*cmn.ClusterConf, error) {
+	q := url.Values{cmn.URLParamWhat: []string{cmn.GetWhatClusterConf}}
+	if version != 0 {
+		q.Set(cmn.URLParamConfVersion, strconv.FormatInt(version, 10))
+	}
+	conf := &cmn.ClusterConf{}
+	err := getClusterConf(baseParams, q, conf)
+	return conf, err
+}
+
+// GetClusterConfigHistory API
+//
+// GetClusterConfigHistory returns all kept versions of the cluster-wide configuration, the oldest first
+func GetClusterConfigHistory(baseParams *BaseParams) ([]cmn.ClusterConf, error) {
+	var history []cmn.ClusterConf
+	err := getClusterConf(baseParams, url.Values{cmn.URLParamWhat: []string{cmn.GetWhatConfHistory}}, &history)
+	return history, err
+}
+
+// DiffClusterConfig API
+//
+// DiffClusterConfig returns the changes between two versions of the cluster-wide configuration
+// (to zero - the current version)
+func DiffClusterConfig(baseParams *BaseParams, from, to int64) ([]cmn.ConfChange, error) {
+	q := url.Values{cmn.URLParamWhat: []string{cmn.GetWhatConfDiff}}
+	q.Set(cmn.URLParamConfFrom, strconv.FormatInt(from, 10))
+	if to != 0 {
+		q.Set(cmn.URLParamConfTo, strconv.FormatInt(to, 10))
+	}
+	var changes []cmn.ConfChange
+	err := getClusterConf(baseParams, q, &changes)
+	return changes, err
+}
+
+func getClusterConf(baseParams *BaseParams, q url.Values, v interface{}) error {
+	baseParams.Method = http.MethodGet
+	path := cmn.URLPath(cmn.Version, cmn.Cluster)
+	b, err := DoHTTPRequest(baseParams, path, nil, OptionalParams{Query: q})
+	if err != nil {
+		return err
+	}
+	return jsoniter.Unmarshal(b, v)
+}
+
+// GetRebalancePreview API
+//
+// GetRebalancePreview computes the data that would move between the targets if
+// the given targets were added to and/or removed from the cluster (nothing moves)
+func GetRebalancePreview(baseParams *BaseParams, add, remove []string) (*cmn.RebPreview, error) {
+	q := url.Values{cmn.URLParamWhat: []string{cmn.GetWhatRebPreview}}
+	if len(add) > 0 {
+		q.Set(cmn.URLParamAddTargets, strings.Join(add, ","))
+	}
+	if len(remove) > 0 {
+		q.Set(cmn.URLParamRemoveTargets, strings.Join(remove, ","))
+	}
+	baseParams.Method = http.MethodGet
+	path := cmn.URLPath(cmn.Version, cmn.Cluster)
+	b, err := DoHTTPRequest(baseParams, path, nil, OptionalParams{Query: q})
+	if err != nil {
+		return nil, err
+	}
+	preview := &cmn.RebPreview{}
+	err = jsoniter.Unmarshal(b, preview)
+	return preview, err
+}
+
+// GetClusterEvents API
+//
+// GetClusterEvents retrieves the cluster event log: the events of the given types
+// (all, if none given) that happened within [since, until), where zero time means no limit
+func GetClusterEvents(baseParams *BaseParams, types []string, since, until time.Time) ([]cmn.ClusterEvent, error) {
+	q := url.Values{cmn.URLParamWhat: []string{cmn.GetWhatEvents}}
+	if len(types) > 0 {
+		q.Set(cmn.URLParamEventType, strings.Join(types, ","))
+	}
+	if !since.IsZero() {
+		q.Set(cmn.URLParamSince, since.Format(time.RFC3339Nano))
+	}
+	if !until.IsZero() {
+		q.Set(cmn.URLParamUntil, until.Format(time.RFC3339Nano))
+	}
+	baseParams.Method = http.MethodGet
+	path := cmn.URLPath(cmn.Version, cmn.Cluster)
+	b, err := DoHTTPRequest(baseParams, path, nil, OptionalParams{Query: q})
+	if err != nil {
+		return nil, err
+	}
+	var events []cmn.ClusterEvent
+	err = jsoniter.Unmarshal(b, &events)
+	return events, err
+}
+
+// AddJob API
+//
+// AddJob adds a scheduled (cron-style) job or replaces the existing one with the same name
+func AddJob(baseParams *BaseParams, job *cmn.Job) error {
+	return updateJobs(baseParams, cmn.ActionMsg{Action: cmn.ActAddJob, Value: job})
+}
+
+// RemoveJob API
+//
+// RemoveJob removes the scheduled job with the given name
+func RemoveJob(baseParams *BaseParams, name string) error {
+	return updateJobs(baseParams, cmn.ActionMsg{Action: cmn.ActRemoveJob, Name: name})
+}
+
+func updateJobs(baseParams *BaseParams, actMsg cmn.ActionMsg) error {
+	msg, err := jsoniter.Marshal(actMsg)
+	if err != nil {
+		return err
+	}
+	baseParams.Method = http.MethodPut
+	path := cmn.URLPath(cmn.Version, cmn.Cluster)
+	_, err = DoHTTPRequest(baseParams, path, msg)
+	return err
+}
+
+// GetJobs API
+//
+// GetJobs returns the scheduled jobs (the outcomes of their runs are recorded in
+// the cluster event log - see GetClusterEvents and cmn.EventJob)
+func GetJobs(baseParams *BaseParams) ([]cmn.Job, error) {
+	q := url.Values{cmn.URLParamWhat: []string{cmn.GetWhatJobs}}
+	baseParams.Method = http.MethodGet
+	path := cmn.URLPath(cmn.Version, cmn.Cluster)
+	b, err := DoHTTPRequest(baseParams, path, nil, OptionalParams{Query: q})
+	if err != nil {
+		return nil, err
+	}
+	var jobs []cmn.Job
+	err = jsoniter.Unmarshal(b, &jobs)
+	return jobs, err
+}
@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/NVIDIA/aistore/cmn"
 	jsoniter "github.com/json-iterator/go"
@@ -128,6 +129,23 @@ func GetDaemonSysInfo(baseParams *BaseParams) (sysInfo *cmn.TSysInfo, err error)
 	return
 }
 
+// GetNameLocks API
+//
+// Given the direct public URL of the target, returns the object locks that are currently
+// held and/or waited for on the target - the longest held first
+func GetNameLocks(baseParams *BaseParams) (locks []cmn.NameLockInfo, err error) {
+	baseParams.Method = http.MethodGet
+	path := cmn.URLPath(cmn.Version, cmn.Daemon)
+	query := url.Values{cmn.URLParamWhat: []string{cmn.GetWhatLocks}}
+	optParams := OptionalParams{Query: query}
+	b, err := DoHTTPRequest(baseParams, path, nil, optParams)
+	if err != nil {
+		return nil, err
+	}
+	err = jsoniter.Unmarshal(b, &locks)
+	return
+}
+
 // SetDaemonConfig API
 //
 // Given a key and a value for a specific configuration parameter
@@ -151,3 +169,28 @@ func SetDaemonConfig(baseParams *BaseParams, key string, value interface{}) erro
 	_, err = DoHTTPRequest(baseParams, path, msg)
 	return err
 }
+
+// ReloadDaemonConfig API
+//
+// Given the direct public URL of the daemon, ReloadDaemonConfig makes it re-read and
+// validate its configuration file and apply the values that can be changed at runtime;
+// dry-run only reports the values that would change and those that require restart
+func ReloadDaemonConfig(baseParams *BaseParams, dryRun bool) (*cmn.ConfigReload, error) {
+	reload := &cmn.ConfigReload{}
+	err := reloadConfig(baseParams, cmn.URLPath(cmn.Version, cmn.Daemon), dryRun, reload)
+	return reload, err
+}
+
+func reloadConfig(baseParams *BaseParams, path string, dryRun bool, v interface{}) error {
+	msg, err := jsoniter.Marshal(cmn.ActionMsg{Action: cmn.ActReloadConfig})
+	if err != nil {
+		return err
+	}
+	baseParams.Method = http.MethodPut
+	optParams := OptionalParams{Query: url.Values{cmn.URLParamDryRun: []string{strconv.FormatBool(dryRun)}}}
+	b, err := DoHTTPRequest(baseParams, path, msg, optParams)
+	if err != nil {
+		return err
+	}
+	return jsoniter.Unmarshal(b, v)
+}
@@ -0,0 +1,111 @@
+// Package api provides RESTful API to AIS object storage
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const xactPollInterval = time.Second
+
+// GetXactions API
+//
+// GetXactions returns the xactions that match the given ID or kind, bucket, and
+// time interval (zero values match all), grouped by target ID; with all == true
+// the list includes finished xactions from the targets' (persistent) history
+func GetXactions(baseParams *BaseParams, msg *cmn.XactMsg, all bool) (map[string][]cmn.XactStats, error) {
+	q := url.Values{}
+	if msg.ID != 0 {
+		q.Set(cmn.URLParamXactID, strconv.FormatInt(msg.ID, 10))
+	}
+	if msg.Kind != "" {
+		q.Set(cmn.URLParamXactKind, msg.Kind)
+	}
+	if msg.Bucket != "" {
+		q.Set(cmn.URLParamXactBucket, msg.Bucket)
+	}
+	if all {
+		q.Set(cmn.URLParamXactAll, "true")
+	}
+	if !msg.Since.IsZero() {
+		q.Set(cmn.URLParamSince, msg.Since.Format(time.RFC3339Nano))
+	}
+	if !msg.Until.IsZero() {
+		q.Set(cmn.URLParamUntil, msg.Until.Format(time.RFC3339Nano))
+	}
+	baseParams.Method = http.MethodGet
+	path := cmn.URLPath(cmn.Version, cmn.Xactions)
+	b, err := DoHTTPRequest(baseParams, path, nil, OptionalParams{Query: q})
+	if err != nil {
+		return nil, err
+	}
+	xacts := make(map[string][]cmn.XactStats)
+	err = jsoniter.Unmarshal(b, &xacts)
+	return xacts, err
+}
+
+// StartXaction API
+//
+// StartXaction starts the xaction of a given kind (global or local rebalance, LRU,
+// scrub, GC, or - for a given bucket - rechecksum) on all targets; other kinds are
+// started by their respective APIs
+func StartXaction(baseParams *BaseParams, kind, bucket string) error {
+	return xactAction(baseParams, cmn.ActXactStart, &cmn.XactMsg{Kind: kind, Bucket: bucket})
+}
+
+// AbortXaction API
+//
+// AbortXaction aborts the running xaction with a given ID on a given target (msg.Target
+// is required with msg.ID) or else all running xactions of a given kind (and bucket,
+// if specified)
+func AbortXaction(baseParams *BaseParams, msg *cmn.XactMsg) error {
+	return xactAction(baseParams, cmn.ActXactStop, msg)
+}
+
+func xactAction(baseParams *BaseParams, action string, msg *cmn.XactMsg) error {
+	b, err := jsoniter.Marshal(cmn.ActionMsg{Action: action, Value: msg})
+	if err != nil {
+		return err
+	}
+	baseParams.Method = http.MethodPut
+	path := cmn.URLPath(cmn.Version, cmn.Xactions)
+	_, err = DoHTTPRequest(baseParams, path, b)
+	return err
+}
+
+// WaitForXaction API
+//
+// WaitForXaction blocks until none of the matching xactions is running on any
+// target, or until the timeout (zero - no timeout) expires
+func WaitForXaction(baseParams *BaseParams, msg *cmn.XactMsg, timeout time.Duration) error {
+	var deadline time.Time
+	if timeout != 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	for {
+		xacts, err := GetXactions(baseParams, msg, false)
+		if err != nil {
+			return err
+		}
+		running := 0
+		for _, list := range xacts {
+			running += len(list)
+		}
+		if running == 0 {
+			return nil
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for %d xaction(s) %+v to finish", running, *msg)
+		}
+		time.Sleep(xactPollInterval)
+	}
+}
@@ -5,6 +5,8 @@
 package hrw_bench
 
 import (
+	"math"
+
 	"github.com/NVIDIA/aistore/xoshiro256"
 	"github.com/OneOfOne/xxhash"
 )
@@ -15,6 +17,7 @@ type node struct {
 	id          string
 	idDigestInt uint64
 	idDigestXX  *xxhash.XXHash64
+	weight      int64 // relative weight (weighted variant only)
 }
 
 func hrwXXHash(key string, nodes []node) int {
@@ -88,3 +91,24 @@ func hrwHybridXXHashXoshiro256(key string, nodes []node) int {
 
 	return destIdx
 }
+
+// Weighted rendezvous hashing (as in cluster.HrwTarget): the score -weight/ln(h),
+// where h is the xoshiro256 hash mapped onto (0, 1), makes the probability of a node
+// to be selected proportional to its weight.
+func hrwWeightedXXHashXoshiro256(key string, nodes []node) int {
+	keyHash := xxhash.ChecksumString64S(":"+key, xxHashSeed)
+
+	var maxScore float64
+	var destIdx int
+	for idx, node := range nodes {
+		cksum := xoshiro256.Hash(node.idDigestInt ^ keyHash)
+		h := (float64(cksum>>11) + 0.5) / (1 << 53)
+		score := -float64(node.weight) / math.Log(h)
+		if score > maxScore {
+			maxScore = score
+			destIdx = idx
+		}
+	}
+
+	return destIdx
+}
@@ -24,6 +24,7 @@ func BenchmarkHRW(b *testing.B) {
 		{name: "hrwXXHashWithAppend", hashF: hrwXXHashWithAppend},
 		{name: "hrwXXHash+XorShift", hashF: hrwHybridXXHashXorshift},
 		{name: "hrwXXHash+Xoshiro", hashF: hrwHybridXXHashXoshiro256},
+		{name: "hrwWeighted", hashF: hrwWeightedXXHashXoshiro256},
 	}
 
 	// Length of name: {256, 512, 1024}
@@ -124,6 +125,41 @@ func TestEqualDistribution(t *testing.T) {
 	}
 }
 
+// TestWeightedDistribution validates that the weighted variant places objects
+// in proportion to the nodes' weights - e.g., a mix of 40TB and 200TB nodes
+func TestWeightedDistribution(t *testing.T) {
+	seed := time.Now().UTC().UnixNano()
+	t.Logf("Seed: %d", seed)
+	randGen := rand.New(rand.NewSource(seed))
+
+	const totalObjs = 1000000
+	for _, weights := range [][]int64{{1, 1, 1, 1}, {40, 200}, {40, 40, 200, 200, 200}, {1, 2, 3, 4, 5, 6, 7, 8}} {
+		var (
+			nodes     = randNodeIDs(len(weights), randGen)
+			countObjs = make([]int, len(nodes))
+			sum       int64
+		)
+		for i, w := range weights {
+			nodes[i].weight = w
+			sum += w
+		}
+		bucketName := randFileName(randGen, fqnMaxLen-objNameLen)
+		for n := 0; n < totalObjs; n++ {
+			countObjs[hrwWeightedXXHashXoshiro256(similarFileName(bucketName, n), nodes)]++
+		}
+		maxDiff := 0.0
+		for i, c := range countObjs {
+			expected := float64(totalObjs) * float64(weights[i]) / float64(sum)
+			diff := math.Abs(expected-float64(c)) / expected
+			if diff > 0.03 { // 3% (~5 sigma for the lightest node)
+				t.Errorf("Weights: %v, Node: %d, Expected: %f, Actual: %d, Diff: %.2f%%", weights, i, expected, c, diff*100)
+			}
+			maxDiff = math.Max(maxDiff, diff)
+		}
+		t.Logf("Weights: %v, MaxDiff: %.3f%%\n", weights, maxDiff*100)
+	}
+}
+
 func invokeHashFunctions(seed int64, numObjs, numNodes int, useSimilarNames bool, hashFuncs []hashFuncs, dist [][]int) {
 	randGen := rand.New(rand.NewSource(seed))
 	nodes := randNodeIDs(numNodes, randGen)
@@ -6,6 +6,7 @@ package cluster
 
 import (
 	"fmt"
+	"math"
 	"sort"
 
 	"github.com/NVIDIA/aistore/cmn"
@@ -23,13 +24,31 @@ func Uname(bucket, objname string) string {
 	return bucket + "/" + objname
 }
 
+// hrwScore is the weighted HRW score of a target (the logarithmic method by
+// Schindelhauer and Schomaker): -weight/ln(h), where h is the target's
+// (uniformly distributed) hash mapped onto the (0, 1) interval. The probability
+// for a target to win is proportional to its weight; for equal weights the
+// ordering is the same as the one of the hashes.
+func hrwScore(cs uint64, weight int64) float64 {
+	h := (float64(cs>>11) + 0.5) / (1 << 53)
+	return -float64(weight) / math.Log(h)
+}
+
+// NOTE: targets that are being decommissioned are excluded from placement
+// NOTE: weighted rendezvous hashing is used when the targets' weights differ (see Smap.Weight)
 func HrwTarget(bucket, objname string, smap *Smap) (si *Snode, errstr string) {
 	var (
 		max    uint64
 		name   = Uname(bucket, objname)
 		digest = xxhash.ChecksumString64S(name, MLCG32)
 	)
-	for _, sinfo := range smap.Tmap {
+	if !smap.uniformWeights() {
+		return hrwTargetWeighted(digest, smap)
+	}
+	for id, sinfo := range smap.Tmap {
+		if smap.Decommissioning(id) {
+			continue
+		}
 		cs := xoshiro256.Hash(sinfo.idDigest ^ digest)
 		if cs > max {
 			max = cs
@@ -42,35 +61,76 @@ func HrwTarget(bucket, objname string, smap *Smap) (si *Snode, errstr string) {
 	return
 }
 
+func hrwTargetWeighted(digest uint64, smap *Smap) (si *Snode, errstr string) {
+	var max float64
+	for id, sinfo := range smap.Tmap {
+		if smap.Decommissioning(id) {
+			continue
+		}
+		score := hrwScore(xoshiro256.Hash(sinfo.idDigest^digest), smap.Weight(id))
+		if score > max {
+			max = score
+			si = sinfo
+		}
+	}
+	if si == nil {
+		errstr = "cluster map is empty: no targets"
+	}
+	return
+}
+
 // Returns count number of first targets with highest random weight. The list
 // of targets is sorted from the greatest to least.
+// When the targets are labeled with their failure domains (see NodeLabels) the
+// list is spread across distinct domains where possible (see spreadDomains);
+// the first target is always the one returned by HrwTarget.
 // Returns error if the cluster does not have enough targets
 func HrwTargetList(bucket, objname string, smap *Smap, count int) (si []*Snode, errstr string) {
 	if count <= 0 {
 		return nil, fmt.Sprintf("invalid number of targets requested: %d", count)
 	}
-	if smap.CountTargets() < count {
-		errstr = fmt.Sprintf("Number of targets %d is fewer than requested %d", smap.CountTargets(), count)
+	cnt := smap.CountPlacementTargets()
+	if cnt < count {
+		errstr = fmt.Sprintf("Number of targets %d is fewer than requested %d", cnt, count)
 		return
 	}
 
 	type tsi struct {
-		node *Snode
-		hash uint64
+		node  *Snode
+		hash  uint64
+		score float64
 	}
-	arr := make([]tsi, len(smap.Tmap))
+	arr := make([]tsi, cnt)
 	si = make([]*Snode, count)
 	name := Uname(bucket, objname)
 	digest := xxhash.ChecksumString64S(name, MLCG32)
+	weighted := !smap.uniformWeights()
 
 	i := 0
-	for _, sinfo := range smap.Tmap {
+	for id, sinfo := range smap.Tmap {
+		if smap.Decommissioning(id) {
+			continue
+		}
 		cs := xoshiro256.Hash(sinfo.idDigest ^ digest)
-		arr[i] = tsi{sinfo, cs}
+		arr[i] = tsi{node: sinfo, hash: cs}
+		if weighted {
+			arr[i].score = hrwScore(cs, smap.Weight(id))
+		}
 		i++
 	}
 
-	sort.Slice(arr, func(i, j int) bool { return arr[i].hash > arr[j].hash })
+	if weighted {
+		sort.Slice(arr, func(i, j int) bool { return arr[i].score > arr[j].score })
+	} else {
+		sort.Slice(arr, func(i, j int) bool { return arr[i].hash > arr[j].hash })
+	}
+	if smap.labeled() {
+		nodes := make([]*Snode, cnt)
+		for i := range arr {
+			nodes[i] = arr[i].node
+		}
+		return spreadDomains(nodes, count), ""
+	}
 	for i := 0; i < count; i++ {
 		si[i] = arr[i].node
 	}
@@ -78,6 +138,59 @@ func HrwTargetList(bucket, objname string, smap *Smap, count int) (si []*Snode,
 	return
 }
 
+// spreadDomains selects count targets out of the HRW-ordered list, one at a
+// time: the next selected is the first (in the HRW order) among the ones that
+// share the least number of zones with the already selected targets, and then
+// racks, and then hosts
+func spreadDomains(nodes []*Snode, count int) []*Snode {
+	var (
+		selected = make([]*Snode, 0, count)
+		taken    [len(domainLevels)]map[string]int // per domain level: value => number of selected targets
+	)
+	for level := range taken {
+		taken[level] = make(map[string]int, count)
+	}
+	penalty := func(si *Snode) (p [len(domainLevels)]int) {
+		for level := range taken {
+			if v := si.Labels.domain(level); v != "" {
+				p[level] = taken[level][v]
+			}
+		}
+		return
+	}
+	less := func(a, b [len(domainLevels)]int) bool {
+		for level := range a {
+			if a[level] != b[level] {
+				return a[level] < b[level]
+			}
+		}
+		return false
+	}
+	for len(selected) < count {
+		var (
+			best        = -1
+			bestPenalty [len(domainLevels)]int
+		)
+		for i, si := range nodes {
+			if si == nil {
+				continue
+			}
+			if p := penalty(si); best < 0 || less(p, bestPenalty) {
+				best, bestPenalty = i, p
+			}
+		}
+		si := nodes[best]
+		nodes[best] = nil
+		selected = append(selected, si)
+		for level := range taken {
+			if v := si.Labels.domain(level); v != "" {
+				taken[level][v]++
+			}
+		}
+	}
+	return selected
+}
+
 func HrwProxy(smap *Smap, idToSkip string) (pi *Snode, errstr string) {
 	if smap.CountProxies() == 0 {
 		errstr = "cluster map is empty: no proxies"
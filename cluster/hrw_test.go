@@ -0,0 +1,216 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster_test
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HRW", func() {
+	const (
+		numTargets = 5
+		numObjects = 1000
+		bucket     = "hrw-test"
+	)
+	newSmap := func() *cluster.Smap {
+		smap := &cluster.Smap{Tmap: make(cluster.NodeMap, numTargets)}
+		for i := 0; i < numTargets; i++ {
+			si := &cluster.Snode{DaemonID: fmt.Sprintf("t%d", i)}
+			si.Digest()
+			smap.Tmap[si.DaemonID] = si
+		}
+		return smap
+	}
+
+	It("should not place objects on a target that is being decommissioned", func() {
+		var (
+			smap  = newSmap()
+			moved int
+		)
+		before := make(map[string]string, numObjects)
+		for i := 0; i < numObjects; i++ {
+			objname := fmt.Sprintf("obj%d", i)
+			si, errstr := cluster.HrwTarget(bucket, objname, smap)
+			Expect(errstr).To(BeEmpty())
+			before[objname] = si.DaemonID
+		}
+
+		smap.Maintenance = cmn.SimpleKVs{"t0": cmn.MaintenanceDecommission}
+		Expect(smap.CountPlacementTargets()).To(Equal(numTargets - 1))
+		for objname, sid := range before {
+			si, errstr := cluster.HrwTarget(bucket, objname, smap)
+			Expect(errstr).To(BeEmpty())
+			Expect(si.DaemonID).NotTo(Equal("t0"))
+			if sid == "t0" {
+				moved++
+			} else {
+				Expect(si.DaemonID).To(Equal(sid)) // only the decommissioned target's objects move
+			}
+			list, errstr := cluster.HrwTargetList(bucket, objname, smap, numTargets-1)
+			Expect(errstr).To(BeEmpty())
+			for _, si := range list {
+				Expect(si.DaemonID).NotTo(Equal("t0"))
+			}
+		}
+		Expect(moved).To(BeNumerically(">", 0))
+		_, errstr := cluster.HrwTargetList(bucket, "obj0", smap, numTargets)
+		Expect(errstr).NotTo(BeEmpty())
+	})
+
+	It("should distribute objects in proportion to the targets' weights", func() {
+		const numObjs = 100000
+		smap := newSmap()
+		smap.Tmap["t0"].Weight = 5 // (e.g., 200TB vs 40TB)
+		counts := make(map[string]int, numTargets)
+		for i := 0; i < numObjs; i++ {
+			si, errstr := cluster.HrwTarget(bucket, fmt.Sprintf("obj%d", i), smap)
+			Expect(errstr).To(BeEmpty())
+			counts[si.DaemonID]++
+		}
+		expected := float64(numObjs) * 5 / (5 + numTargets - 1)
+		Expect(float64(counts["t0"])).To(BeNumerically("~", expected, expected*0.03))
+
+		// the operator's weight overrides the declared one; the list agrees with HrwTarget
+		smap.Weights = map[string]int64{"t0": 1}
+		Expect(smap.Weight("t0")).To(Equal(int64(1)))
+		smap.Weights = map[string]int64{"t1": 3}
+		for i := 0; i < numObjects; i++ {
+			objname := fmt.Sprintf("obj%d", i)
+			si, _ := cluster.HrwTarget(bucket, objname, smap)
+			list, errstr := cluster.HrwTargetList(bucket, objname, smap, numTargets)
+			Expect(errstr).To(BeEmpty())
+			Expect(list[0].DaemonID).To(Equal(si.DaemonID))
+		}
+	})
+
+	It("should keep the unweighted placement when all weights are equal", func() {
+		smap := newSmap()
+		before := make([]string, numObjects)
+		for i := range before {
+			si, _ := cluster.HrwTarget(bucket, fmt.Sprintf("obj%d", i), smap)
+			before[i] = si.DaemonID
+		}
+		for _, si := range smap.Tmap {
+			si.Weight = 7
+		}
+		for i := range before {
+			si, _ := cluster.HrwTarget(bucket, fmt.Sprintf("obj%d", i), smap)
+			Expect(si.DaemonID).To(Equal(before[i]))
+		}
+	})
+
+	It("should use the weights cached when the Smap is installed", func() {
+		var (
+			smap     = newSmap()
+			uniform  = make([]string, numObjects)
+			weighted = make([]string, numObjects)
+		)
+		place := func(placed []string) {
+			for i := range placed {
+				si, errstr := cluster.HrwTarget(bucket, fmt.Sprintf("obj%d", i), smap)
+				Expect(errstr).To(BeEmpty())
+				placed[i] = si.DaemonID
+			}
+		}
+		place(uniform)
+		smap.Tmap["t0"].Weight = 5
+		place(weighted)
+		Expect(weighted).NotTo(Equal(uniform))
+
+		smap.InitPlacement()
+		placed := make([]string, numObjects)
+		place(placed)
+		Expect(placed).To(Equal(weighted))
+
+		// a modified copy must drop the cached value
+		smap.Tmap["t0"].Weight = 0
+		smap.ClearPlacement()
+		place(placed)
+		Expect(placed).To(Equal(uniform))
+	})
+
+	It("should spread the target list across distinct failure domains", func() {
+		smap := newSmap()
+		// t0, t1 and t2 share rack r1, t3 and t4 - rack r2 (same zone)
+		for i := 0; i < numTargets; i++ {
+			rack := "r1"
+			if i >= 3 {
+				rack = "r2"
+			}
+			smap.Tmap[fmt.Sprintf("t%d", i)].Labels = cluster.NodeLabels{Zone: "z1", Rack: rack}
+		}
+		domain, n := smap.FailureDomain()
+		Expect(domain).To(Equal(cluster.DomainRack))
+		Expect(n).To(Equal(2))
+		for i := 0; i < numObjects; i++ {
+			objname := fmt.Sprintf("obj%d", i)
+			si, _ := cluster.HrwTarget(bucket, objname, smap)
+			list, errstr := cluster.HrwTargetList(bucket, objname, smap, 3)
+			Expect(errstr).To(BeEmpty())
+			Expect(list[0].DaemonID).To(Equal(si.DaemonID))
+			racks := make(map[string]int)
+			for _, si := range list {
+				racks[si.Labels.Rack]++
+			}
+			// 3 targets over 2 racks: 2+1, never 3+0
+			Expect(racks).To(HaveLen(2))
+		}
+	})
+
+	It("should use the labels cached when the Smap is installed", func() {
+		var (
+			smap   = newSmap()
+			plain  = make([][]*cluster.Snode, numObjects)
+			spread = make([][]*cluster.Snode, numObjects)
+		)
+		list := func(lists [][]*cluster.Snode) {
+			for i := range lists {
+				l, errstr := cluster.HrwTargetList(bucket, fmt.Sprintf("obj%d", i), smap, 3)
+				Expect(errstr).To(BeEmpty())
+				lists[i] = l
+			}
+		}
+		list(plain)
+		smap.InitPlacement()
+		for i := 0; i < numTargets; i++ {
+			smap.Tmap[fmt.Sprintf("t%d", i)].Labels = cluster.NodeLabels{Rack: fmt.Sprintf("r%d", i%2)}
+		}
+		// cached: not labeled
+		list(spread)
+		Expect(spread).To(Equal(plain))
+
+		smap.ClearPlacement()
+		list(spread)
+		Expect(spread).NotTo(Equal(plain))
+	})
+
+	It("should keep the HRW order when the targets are not labeled", func() {
+		smap := newSmap()
+		domain, _ := smap.FailureDomain()
+		Expect(domain).To(BeEmpty())
+		list, _ := cluster.HrwTargetList(bucket, "obj", smap, numTargets)
+		for _, si := range smap.Tmap {
+			si.Labels = cluster.NodeLabels{Zone: "z1", Rack: "r1"} // single domain
+		}
+		labeled, _ := cluster.HrwTargetList(bucket, "obj", smap, numTargets)
+		Expect(labeled).To(Equal(list))
+	})
+
+	It("should keep placement for a target in maintenance for reboot", func() {
+		smap := newSmap()
+		for i := 0; i < numObjects; i++ {
+			objname := fmt.Sprintf("obj%d", i)
+			si, _ := cluster.HrwTarget(bucket, objname, smap)
+			smap.Maintenance = cmn.SimpleKVs{"t0": cmn.MaintenanceReboot}
+			si2, _ := cluster.HrwTarget(bucket, objname, smap)
+			smap.Maintenance = nil
+			Expect(si2.DaemonID).To(Equal(si.DaemonID))
+		}
+	})
+})
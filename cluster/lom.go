@@ -24,6 +24,9 @@ import (
 // - associated runtime context including properties and configuration of the
 //   bucket that contains the object, etc.
 //
+// The metadata is persisted as a single record (see lommeta.go) and cached
+// in memory (see lomcache.go).
+//
 
 // actions - lom (LOM) state can be filled by applying those incrementally, on as needed basis
 const (
@@ -70,6 +73,9 @@ type (
 		BckIsLocal bool // the bucket (that contains this object) is local
 		BadCksum   bool // this object has a bad checksum
 		exists     bool // determines if the object exists or not (initially set by fstat)
+		// persistent metadata (loaded on demand) and the fstat it was validated against
+		md    *lmeta
+		finfo os.FileInfo
 	}
 )
 
@@ -105,6 +111,7 @@ func (lom *LOM) GenFQN(ty, prefix string) string {
 func (lom *LOM) Copy(props LOMCopyProps) *LOM {
 	dstLOM := &LOM{}
 	*dstLOM = *lom
+	dstLOM.md, dstLOM.finfo = nil, nil
 
 	if dstLOM.Cksum != nil && props.Cksum == nil {
 		_ = dstLOM.checksum(0) // already copied; ignoring "get" errors at this point
@@ -129,8 +136,9 @@ func (lom *LOM) Copy(props LOMCopyProps) *LOM {
 // local replica management
 //
 func (lom *LOM) SetXcopy(cpyfqn string) (errstr string) { // cross-ref
-	if errstr = fs.SetXattr(lom.FQN, cmn.XattrCopies, []byte(cpyfqn)); errstr == "" {
-		if errstr = fs.SetXattr(cpyfqn, cmn.XattrCopies, []byte(lom.FQN)); errstr == "" {
+	lom.md = nil
+	if errstr = updateMeta(lom.FQN, func(md *lmeta) { md.copyFQN = cpyfqn }); errstr == "" {
+		if errstr = updateMeta(cpyfqn, func(md *lmeta) { md.copyFQN = lom.FQN }); errstr == "" {
 			lom.CopyFQN = cpyfqn
 			return
 		}
@@ -146,7 +154,15 @@ func (lom *LOM) DelCopy() (errstr string) {
 		lom.T.FSHC(err, lom.FQN)
 		return err.Error()
 	}
-	errstr = fs.DelXattr(lom.FQN, cmn.XattrCopies)
+	return lom.DelXcopy()
+}
+
+// DelXcopy removes the reference to the copy (but not the copy itself)
+func (lom *LOM) DelXcopy() (errstr string) {
+	lom.md = nil
+	if errstr = updateMeta(lom.FQN, func(md *lmeta) { md.copyFQN = "" }); errstr == "" {
+		lom.CopyFQN = ""
+	}
 	return
 }
 
@@ -244,14 +260,15 @@ func (lom *LOM) Fill(bckProvider string, action int, config ...*cmn.Config) (err
 			return
 		}
 		lom.Size = finfo.Size()
-
+		lom.md, lom.finfo = nil, finfo // reload (from cache, if still valid)
 	}
-	if action&LomVersion != 0 {
-		var version []byte
-		if version, errstr = fs.GetXattr(lom.FQN, cmn.XattrVersion); errstr != "" {
+	if action&(LomVersion|LomCopy) != 0 && lom.md == nil {
+		if errstr = lom.loadMeta(); errstr != "" {
 			return
 		}
-		lom.Version = string(version)
+	}
+	if action&LomVersion != 0 {
+		lom.Version = lom.md.version
 	}
 	if action&LomAtime != 0 { // FIXME: RFC822 format
 		lom.Atimestr, lom.Atime, _ = lom.T.GetAtimeRunner().FormatAtime(lom.FQN, lom.ParsedFQN.MpathInfo.Path, lom.AtimeRespCh, lom.LRUenabled())
@@ -263,15 +280,36 @@ func (lom *LOM) Fill(bckProvider string, action int, config ...*cmn.Config) (err
 		}
 	}
 	if action&LomCopy != 0 {
-		var copyfqn []byte
-		if copyfqn, errstr = fs.GetXattr(lom.FQN, cmn.XattrCopies); errstr != "" {
-			return
-		}
-		lom.CopyFQN = string(copyfqn)
+		lom.CopyFQN = lom.md.copyFQN
 	}
 	return
 }
 
+// loads the metadata record - from the cache if the (last) fstat tells that it is valid
+func (lom *LOM) loadMeta() (errstr string) {
+	if lom.finfo == nil || lom.ParsedFQN.MpathInfo == nil {
+		lom.md, _, errstr = readMeta(lom.FQN)
+		return
+	}
+	lc := lcache(lom.ParsedFQN.MpathInfo.Path)
+	if lom.md = lc.get(lom.FQN, lom.finfo); lom.md != nil {
+		return
+	}
+	// the fstat precedes the read and may not reflect an update that lands in between
+	// (ctime is coarse): read and cache under the meta lock, so that the update
+	// invalidates the entry after it is put, not before
+	mtx := metaLock(lom.FQN)
+	mtx.Lock()
+	defer mtx.Unlock()
+	var md *lmeta
+	if md, _, errstr = readMeta(lom.FQN); errstr != "" {
+		return
+	}
+	lom.md = md
+	lc.put(lom.FQN, lom.finfo, md)
+	return
+}
+
 func (lom *LOM) BadCksumErr(cksum cmn.CksumProvider) (errstr string) {
 	if lom.Cksum != nil {
 		errstr = fmt.Sprintf("BAD CHECKSUM: %s (%s != %s)", lom, cksum, lom.Cksum)
@@ -281,19 +319,19 @@ func (lom *LOM) BadCksumErr(cksum cmn.CksumProvider) (errstr string) {
 	return
 }
 
-// xattrs
+// stores checksum and version
 func (lom *LOM) Persist() (errstr string) {
-	if lom.Cksum != nil {
-		_, cksumValue := lom.Cksum.Get()
-		if errstr = fs.SetXattr(lom.FQN, cmn.XattrXXHash, []byte(cksumValue)); errstr != "" {
-			return errstr
+	lom.md = nil
+	errstr = updateMeta(lom.FQN, func(md *lmeta) {
+		if lom.Cksum != nil {
+			md.setCksum(lom.Cksum)
 		}
-	}
-	if lom.Version != "" {
-		errstr = fs.SetXattr(lom.FQN, cmn.XattrVersion, []byte(lom.Version))
-	}
+		if lom.Version != "" {
+			md.version = lom.Version
+		}
+	})
 	// NOTE: atime is updated explicitly, via UpdateAtime() below
-	//       cmn.XattrCopies is also updated separately by the 2-way mirroring code
+	//       the copy is also updated separately by the 2-way mirroring code (SetXcopy)
 	return
 }
 
@@ -320,11 +358,11 @@ func (lom *LOM) IncObjectVersion() (newVersion string, errstr string) {
 		return
 	}
 
-	var vbytes []byte
-	if vbytes, errstr = fs.GetXattr(lom.FQN, cmn.XattrVersion); errstr != "" {
+	var md *lmeta
+	if md, _, errstr = readMeta(lom.FQN); errstr != "" {
 		return
 	}
-	if currValue, err := strconv.Atoi(string(vbytes)); err != nil {
+	if currValue, err := strconv.Atoi(md.version); err != nil {
 		newVersion = initialVersion
 	} else {
 		newVersion = fmt.Sprintf("%d", currValue+1)
@@ -441,7 +479,6 @@ func (lom *LOM) initBckIsLocal(bckProvider string) error {
 func (lom *LOM) checksum(action int) (errstr string) {
 	var (
 		storedCksum, computedCksum string
-		b                          []byte
 		cksumType                  = lom.CksumConf.Type
 	)
 	if cksumType == cmn.ChecksumNone {
@@ -450,13 +487,15 @@ func (lom *LOM) checksum(action int) (errstr string) {
 	cmn.AssertMsg(cksumType == cmn.ChecksumXXHash, fmt.Sprintf("Unsupported checksum algorithm '%s'", cksumType))
 	if lom.Cksum != nil {
 		_, storedCksum = lom.Cksum.Get()
-	} else if b, errstr = fs.GetXattr(lom.FQN, cmn.XattrXXHash); errstr != "" {
-		lom.T.FSHC(errors.New(errstr), lom.FQN)
-		return
-	} else if b != nil {
-		storedCksum = string(b)
+	} else if lom.md == nil {
+		if errstr = lom.loadMeta(); errstr != "" {
+			return
+		}
+	}
+	if lom.Cksum == nil && lom.md.cksumValue != "" {
+		storedCksum = lom.md.cksumValue
 		lom.Cksum = cmn.NewCksum(cksumType, storedCksum)
-	} else {
+	} else if storedCksum == "" {
 		glog.Warningf("%s is not checksummed", lom)
 	}
 	if action == 0 {
@@ -467,12 +506,12 @@ func (lom *LOM) checksum(action int) (errstr string) {
 		if computedCksum, errstr = lom.recomputeXXHash(lom.FQN, lom.Size); errstr != "" {
 			return
 		}
-		if errstr = fs.SetXattr(lom.FQN, cmn.XattrXXHash, []byte(computedCksum)); errstr != "" {
+		lom.Cksum = cmn.NewCksum(cksumType, computedCksum)
+		lom.md = nil
+		if errstr = updateMeta(lom.FQN, func(md *lmeta) { md.setCksum(lom.Cksum) }); errstr != "" {
 			lom.Cksum = nil
 			lom.T.FSHC(errors.New(errstr), lom.FQN)
-			return
 		}
-		lom.Cksum = cmn.NewCksum(cksumType, computedCksum)
 		return
 	}
 	if storedCksum != "" && action&LomCksumPresentRecomp != 0 {
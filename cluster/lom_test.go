@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/NVIDIA/aistore/atime"
@@ -318,7 +319,7 @@ var _ = Describe("LOM", func() {
 				createTestFile(localFQN, testFileSize)
 				expectedChecksum := getTestFileHash(localFQN)
 				lom := &cluster.LOM{T: tMock, FQN: localFQN}
-				Expect(fs.SetXattr(lom.FQN, cmn.XattrXXHash, []byte(expectedChecksum))).To(BeEmpty())
+				Expect(cluster.SetXattrCksum(lom.FQN, cmn.NewCksum(cmn.ChecksumXXHash, expectedChecksum))).To(BeEmpty())
 
 				Expect(lom.Fill("", cluster.LomCksum|cluster.LomCksumPresentRecomp)).To(BeEmpty())
 
@@ -331,7 +332,7 @@ var _ = Describe("LOM", func() {
 				createTestFile(localFQN, testFileSize)
 				badChecksum := "EA5EACE"
 				lom := &cluster.LOM{T: tMock, FQN: localFQN}
-				Expect(fs.SetXattr(lom.FQN, cmn.XattrXXHash, []byte(badChecksum))).To(BeEmpty())
+				Expect(cluster.SetXattrCksum(lom.FQN, cmn.NewCksum(cmn.ChecksumXXHash, badChecksum))).To(BeEmpty())
 
 				Expect(lom.Fill("", cluster.LomCksum|cluster.LomCksumPresentRecomp)).ToNot(BeEmpty())
 
@@ -349,8 +350,124 @@ var _ = Describe("LOM", func() {
 
 				_, cksumValue := lom.Cksum.Get()
 				Expect(cksumValue).To(BeEquivalentTo(expectedHash))
-				xattrVal, _ := fs.GetXattr(lom.FQN, cmn.XattrXXHash)
-				Expect(xattrVal).To(BeEquivalentTo(expectedHash))
+
+				lomFresh := &cluster.LOM{T: tMock, FQN: localFQN}
+				Expect(lomFresh.Fill("", cluster.LomCksum)).To(BeEmpty())
+				_, cksumValue = lomFresh.Cksum.Get()
+				Expect(cksumValue).To(BeEquivalentTo(expectedHash))
+			})
+		})
+
+		Describe("metadata", func() {
+			testFileSize := 123
+			testObject := "foldr/test-obj.ext"
+			desiredVersion := "9003"
+			localFQN := filepath.Join(mpath, fs.ObjectType, cmn.LocalBs, bucketLocalB, testObject)
+			copyFQN := filepath.Join(mpath2, fs.ObjectType, cmn.LocalBs, bucketLocalB, testObject)
+
+			It("should migrate legacy metadata", func() {
+				createTestFile(localFQN, testFileSize)
+				expectedHash := getTestFileHash(localFQN)
+				Expect(fs.SetXattr(localFQN, cmn.XattrXXHash, []byte(expectedHash))).To(BeEmpty())
+				Expect(fs.SetXattr(localFQN, cmn.XattrVersion, []byte(desiredVersion))).To(BeEmpty())
+				Expect(fs.SetXattr(localFQN, cmn.XattrCopies, []byte(copyFQN))).To(BeEmpty())
+
+				lom := &cluster.LOM{T: tMock, FQN: localFQN}
+				Expect(lom.Fill("", cluster.LomFstat|cluster.LomVersion|cluster.LomCksum|cluster.LomCopy)).To(BeEmpty())
+				_, cksumValue := lom.Cksum.Get()
+				Expect(cksumValue).To(BeEquivalentTo(expectedHash))
+				Expect(lom.Version).To(BeEquivalentTo(desiredVersion))
+				Expect(lom.CopyFQN).To(BeEquivalentTo(copyFQN))
+
+				// reading does not migrate
+				b, errstr := fs.GetXattr(localFQN, cmn.XattrLOM)
+				Expect(errstr).To(BeEmpty())
+				Expect(b).To(BeNil())
+
+				// the first update does
+				lom.Version = "9004"
+				Expect(lom.Persist()).To(BeEmpty())
+				b, errstr = fs.GetXattr(localFQN, cmn.XattrLOM)
+				Expect(errstr).To(BeEmpty())
+				Expect(b).NotTo(BeEmpty())
+				for _, name := range []string{cmn.XattrXXHash, cmn.XattrVersion, cmn.XattrCopies} {
+					b, errstr = fs.GetXattr(localFQN, name)
+					Expect(errstr).To(BeEmpty())
+					Expect(b).To(BeNil())
+				}
+
+				lom = &cluster.LOM{T: tMock, FQN: localFQN}
+				Expect(lom.Fill("", cluster.LomVersion|cluster.LomCksum|cluster.LomCopy)).To(BeEmpty())
+				_, cksumValue = lom.Cksum.Get()
+				Expect(cksumValue).To(BeEquivalentTo(expectedHash))
+				Expect(lom.Version).To(BeEquivalentTo("9004"))
+				Expect(lom.CopyFQN).To(BeEquivalentTo(copyFQN))
+			})
+
+			It("should not lose concurrent metadata updates", func() {
+				const iterations = 100
+				createTestFile(localFQN, testFileSize)
+				wg := &sync.WaitGroup{}
+				wg.Add(2)
+				go func() {
+					defer GinkgoRecover()
+					for i := 0; i < iterations; i++ {
+						Expect(cluster.SetXattrCksum(localFQN, cmn.NewCksum(cmn.ChecksumXXHash, strconv.Itoa(i)))).To(BeEmpty())
+					}
+					wg.Done()
+				}()
+				go func() {
+					defer GinkgoRecover()
+					for i := 0; i < iterations; i++ {
+						lom := &cluster.LOM{T: tMock, FQN: localFQN, Version: strconv.Itoa(i)}
+						Expect(lom.Fill("", 0)).To(BeEmpty())
+						Expect(lom.Persist()).To(BeEmpty())
+					}
+					wg.Done()
+				}()
+				wg.Wait()
+
+				lom := &cluster.LOM{T: tMock, FQN: localFQN}
+				Expect(lom.Fill("", cluster.LomVersion|cluster.LomCksum)).To(BeEmpty())
+				_, cksumValue := lom.Cksum.Get()
+				Expect(cksumValue).To(BeEquivalentTo(strconv.Itoa(iterations - 1)))
+				Expect(lom.Version).To(BeEquivalentTo(strconv.Itoa(iterations - 1)))
+			})
+
+			It("should detect corrupted metadata", func() {
+				createTestFile(localFQN, testFileSize)
+				lom := &cluster.LOM{T: tMock, FQN: localFQN, Version: desiredVersion}
+				Expect(lom.Fill("", 0)).To(BeEmpty())
+				Expect(lom.Persist()).To(BeEmpty())
+
+				b, _ := fs.GetXattr(localFQN, cmn.XattrLOM)
+				b[len(b)-1]++
+				Expect(fs.SetXattr(localFQN, cmn.XattrLOM, b)).To(BeEmpty())
+
+				lom = &cluster.LOM{T: tMock, FQN: localFQN}
+				Expect(lom.Fill("", cluster.LomVersion)).NotTo(BeEmpty())
+			})
+
+			It("should not return stale cached metadata", func() {
+				createTestFile(localFQN, testFileSize)
+				expectedHash := getTestFileHash(localFQN)
+				lom := &cluster.LOM{T: tMock, FQN: localFQN}
+				Expect(lom.Fill("", cluster.LomFstat|cluster.LomCksum|cluster.LomCksumMissingRecomp)).To(BeEmpty())
+				_, cksumValue := lom.Cksum.Get()
+				Expect(cksumValue).To(BeEquivalentTo(expectedHash))
+
+				// warm
+				lom = &cluster.LOM{T: tMock, FQN: localFQN}
+				Expect(lom.Fill("", cluster.LomFstat|cluster.LomCksum)).To(BeEmpty())
+				_, cksumValue = lom.Cksum.Get()
+				Expect(cksumValue).To(BeEquivalentTo(expectedHash))
+
+				// updated
+				Expect(cluster.SetXattrCksum(localFQN, cmn.NewCksum(cmn.ChecksumXXHash, "EA5EACE"))).To(BeEmpty())
+				lom = &cluster.LOM{T: tMock, FQN: localFQN}
+				Expect(lom.Fill("", cluster.LomFstat|cluster.LomCksum)).To(BeEmpty())
+				_, cksumValue = lom.Cksum.Get()
+				Expect(cksumValue).To(BeEquivalentTo("EA5EACE"))
 			})
 		})
 
@@ -422,11 +539,11 @@ var _ = Describe("LOM", func() {
 				expectedHash := getTestFileHash(localFQN)
 
 				//Check copy created
-				var xattr []byte
-				xattr, _ = fs.GetXattr(copyFQN, cmn.XattrVersion)
-				Expect(string(xattr)).To(BeEquivalentTo(desiredVersion))
-				xattr, _ = fs.GetXattr(copyFQN, cmn.XattrXXHash)
-				Expect(string(xattr)).To(BeEquivalentTo(expectedHash))
+				lomCopy := &cluster.LOM{T: tMock, FQN: copyFQN}
+				Expect(lomCopy.Fill("", cluster.LomVersion|cluster.LomCksum)).To(BeEmpty())
+				Expect(lomCopy.Version).To(BeEquivalentTo(desiredVersion))
+				_, cksumValue := lomCopy.Cksum.Get()
+				Expect(cksumValue).To(BeEquivalentTo(expectedHash))
 
 				//Check copy contents are corrrect
 				Expect(getTestFileHash(copyFQN)).To(BeEquivalentTo(expectedHash))
@@ -438,17 +555,16 @@ var _ = Describe("LOM", func() {
 				lom := prepareLomWithCopy(true)
 
 				// Check copy set
-				var xattr []byte
-				xattr, _ = fs.GetXattr(copyFQN, cmn.XattrCopies)
-				Expect(string(xattr)).To(BeEquivalentTo(localFQN))
-				xattr, _ = fs.GetXattr(localFQN, cmn.XattrCopies)
-				Expect(string(xattr)).To(BeEquivalentTo(copyFQN))
+				lomMain := &cluster.LOM{T: tMock, FQN: localFQN}
+				Expect(lomMain.Fill("", cluster.LomCopy)).To(BeEmpty())
+				Expect(lomMain.CopyFQN).To(BeEquivalentTo(copyFQN))
 
 				Expect(lom.CopyFQN).To(BeEquivalentTo(copyFQN))
 
 				// Check msic copy data
 				lomCopy := &cluster.LOM{T: tMock, FQN: copyFQN}
 				Expect(lomCopy.Fill("", cluster.LomVersion|cluster.LomCksum|cluster.LomCksumMissingRecomp|cluster.LomCopy)).To(BeEmpty())
+				Expect(lomCopy.CopyFQN).To(BeEquivalentTo(localFQN))
 
 				Expect(lomCopy.HrwFQN).To(BeEquivalentTo(lom.HrwFQN))
 				Expect(lom.IsCopy()).To(BeFalse())
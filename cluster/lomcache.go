@@ -0,0 +1,124 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"container/list"
+	"os"
+	"sync"
+	"syscall"
+
+	"github.com/NVIDIA/aistore/fs"
+)
+
+//
+// LOM cache: per mountpath, bounded (LRU) cache of the object metadata records
+// (see lommeta.go) that saves warm GETs and listings the xattr reads.
+//
+// Entries are keyed by FQN and validated against the object's fstat (that LOM.Fill
+// does anyway): inode, size, mtime and ctime - the latter changes with any update
+// of the object's xattrs, including updates made by other processes. Metadata
+// updates made by this process invalidate the respective entries explicitly, under
+// the same meta lock that the cache misses hold while reading and putting the record.
+//
+
+const lomCacheSize = 64 * 1024 // max entries per mountpath
+
+type (
+	lcStamp struct {
+		ino, size    uint64
+		mtime, ctime int64
+	}
+	lcEntry struct {
+		fqn   string
+		stamp lcStamp
+		md    lmeta
+	}
+	lomCache struct {
+		mu  sync.Mutex
+		lru *list.List               // most recently used in front
+		m   map[string]*list.Element // fqn => *lcEntry
+	}
+)
+
+var lomCaches sync.Map // mpath => *lomCache
+
+func lcache(mpath string) *lomCache {
+	if lc, ok := lomCaches.Load(mpath); ok {
+		return lc.(*lomCache)
+	}
+	lc, _ := lomCaches.LoadOrStore(mpath, &lomCache{lru: list.New(), m: make(map[string]*list.Element, 1024)})
+	return lc.(*lomCache)
+}
+
+func lcStampOf(finfo os.FileInfo) (stamp lcStamp, ok bool) {
+	st, ok := finfo.Sys().(*syscall.Stat_t)
+	if !ok {
+		return
+	}
+	stamp = lcStamp{ino: st.Ino, size: uint64(st.Size), mtime: st.Mtim.Nano(), ctime: st.Ctim.Nano()}
+	return
+}
+
+func (lc *lomCache) get(fqn string, finfo os.FileInfo) (md *lmeta) {
+	stamp, ok := lcStampOf(finfo)
+	if !ok {
+		return
+	}
+	lc.mu.Lock()
+	if el, ok := lc.m[fqn]; ok {
+		e := el.Value.(*lcEntry)
+		if e.stamp == stamp {
+			lc.lru.MoveToFront(el)
+			md = &lmeta{}
+			*md = e.md
+		} else {
+			lc.lru.Remove(el)
+			delete(lc.m, fqn)
+		}
+	}
+	lc.mu.Unlock()
+	return
+}
+
+func (lc *lomCache) put(fqn string, finfo os.FileInfo, md *lmeta) {
+	stamp, ok := lcStampOf(finfo)
+	if !ok {
+		return
+	}
+	lc.mu.Lock()
+	if el, ok := lc.m[fqn]; ok {
+		e := el.Value.(*lcEntry)
+		e.stamp, e.md = stamp, *md
+		lc.lru.MoveToFront(el)
+	} else {
+		lc.m[fqn] = lc.lru.PushFront(&lcEntry{fqn: fqn, stamp: stamp, md: *md})
+		if lc.lru.Len() > lomCacheSize {
+			el := lc.lru.Back()
+			lc.lru.Remove(el)
+			delete(lc.m, el.Value.(*lcEntry).fqn)
+		}
+	}
+	lc.mu.Unlock()
+}
+
+func (lc *lomCache) del(fqn string) {
+	lc.mu.Lock()
+	if el, ok := lc.m[fqn]; ok {
+		lc.lru.Remove(el)
+		delete(lc.m, fqn)
+	}
+	lc.mu.Unlock()
+}
+
+func invalidateMeta(fqn string) {
+	parsedFQN, err := fs.Mountpaths.FQN2Info(fqn)
+	if err != nil {
+		return
+	}
+	if lc, ok := lomCaches.Load(parsedFQN.MpathInfo.Path); ok {
+		lc.(*lomCache).del(fqn)
+	}
+}
@@ -0,0 +1,214 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/OneOfOne/xxhash"
+)
+
+//
+// Object metadata is stored in a single extended attribute (cmn.XattrLOM):
+//
+//   | format version (1 byte) | xxhash64 of the payload (8 bytes) | payload |
+//
+// The payload is a sequence of (tag: 1 byte, length: uvarint, value) fields, so
+// that fields can be added without bumping the format version (unknown tags are
+// skipped). Objects stored by the previous versions keep their metadata in
+// separate xattrs (cmn.XattrXXHash, cmn.XattrVersion, cmn.XattrCopies) - those
+// are read as is and get migrated upon the first metadata update.
+//
+// NOTE: atime is not a part of the record - it changes on every GET and is
+//       maintained by the atime runner.
+//
+
+const (
+	lmetaFormat = 1
+	lmetaHdrLen = 1 + 8
+
+	// payload tags
+	lmetaTagCksumType  = 1
+	lmetaTagCksumValue = 2
+	lmetaTagVersion    = 3
+	lmetaTagCopyFQN    = 4
+)
+
+type lmeta struct {
+	cksumType, cksumValue string
+	version               string
+	copyFQN               string
+}
+
+func (md *lmeta) cksum() cmn.CksumProvider {
+	if md.cksumValue == "" {
+		return nil
+	}
+	return cmn.NewCksum(md.cksumType, md.cksumValue)
+}
+
+func (md *lmeta) setCksum(cksum cmn.CksumProvider) {
+	if cksum == nil {
+		md.cksumType, md.cksumValue = "", ""
+		return
+	}
+	md.cksumType, md.cksumValue = cksum.Get()
+}
+
+func (md *lmeta) marshal() []byte {
+	b := make([]byte, lmetaHdrLen, lmetaHdrLen+len(md.cksumType)+len(md.cksumValue)+len(md.version)+len(md.copyFQN)+16)
+	b = lmetaAppend(b, lmetaTagCksumType, md.cksumType)
+	b = lmetaAppend(b, lmetaTagCksumValue, md.cksumValue)
+	b = lmetaAppend(b, lmetaTagVersion, md.version)
+	b = lmetaAppend(b, lmetaTagCopyFQN, md.copyFQN)
+	b[0] = lmetaFormat
+	binary.BigEndian.PutUint64(b[1:lmetaHdrLen], xxhash.Checksum64(b[lmetaHdrLen:]))
+	return b
+}
+
+func lmetaAppend(b []byte, tag byte, value string) []byte {
+	if value == "" {
+		return b
+	}
+	var l [binary.MaxVarintLen64]byte
+	b = append(b, tag)
+	b = append(b, l[:binary.PutUvarint(l[:], uint64(len(value)))]...)
+	return append(b, value...)
+}
+
+func (md *lmeta) unmarshal(b []byte) error {
+	if len(b) < lmetaHdrLen {
+		return errors.New("metadata record is too short")
+	}
+	if b[0] != lmetaFormat {
+		return fmt.Errorf("unknown metadata format %d", b[0])
+	}
+	payload := b[lmetaHdrLen:]
+	if binary.BigEndian.Uint64(b[1:lmetaHdrLen]) != xxhash.Checksum64(payload) {
+		return errors.New("metadata record is corrupted (checksum mismatch)")
+	}
+	for len(payload) > 0 {
+		tag := payload[0]
+		l, n := binary.Uvarint(payload[1:])
+		if n <= 0 || uint64(len(payload)-1-n) < l {
+			return errors.New("metadata record is truncated")
+		}
+		value := string(payload[1+n : 1+n+int(l)])
+		payload = payload[1+n+int(l):]
+		switch tag {
+		case lmetaTagCksumType:
+			md.cksumType = value
+		case lmetaTagCksumValue:
+			md.cksumValue = value
+		case lmetaTagVersion:
+			md.version = value
+		case lmetaTagCopyFQN:
+			md.copyFQN = value
+		}
+	}
+	return nil
+}
+
+// readMeta reads the object's metadata record or, if there's none, the legacy
+// xattrs (and returns their names); no metadata - empty record
+//
+// NOTE: read-only - the legacy layout gets migrated by the next update (below)
+func readMeta(fqn string) (md *lmeta, legacy []string, errstr string) {
+	var b []byte
+	if b, errstr = fs.GetXattr(fqn, cmn.XattrLOM); errstr != "" {
+		return
+	}
+	md = &lmeta{}
+	if b != nil {
+		if err := md.unmarshal(b); err != nil {
+			md, errstr = nil, fmt.Sprintf("%s: %v", fqn, err)
+		}
+		return
+	}
+	return readLegacyMeta(fqn)
+}
+
+var legacyXattrs = []string{cmn.XattrXXHash, cmn.XattrVersion, cmn.XattrCopies}
+
+func readLegacyMeta(fqn string) (md *lmeta, legacy []string, errstr string) {
+	values := make([]string, len(legacyXattrs))
+	for i, name := range legacyXattrs {
+		var b []byte
+		if b, errstr = fs.GetXattr(fqn, name); errstr != "" {
+			return
+		}
+		if b != nil {
+			values[i], legacy = string(b), append(legacy, name)
+		}
+	}
+	md = &lmeta{version: values[1], copyFQN: values[2]}
+	if values[0] != "" {
+		md.cksumType, md.cksumValue = cmn.ChecksumXXHash, values[0]
+	}
+	return
+}
+
+// removes the legacy xattrs once the record that supersedes them is written
+func delLegacyMeta(fqn string, legacy []string) {
+	for _, name := range legacy {
+		if errstr := fs.DelXattr(fqn, name); errstr != "" {
+			glog.Warningf("%s: failed to remove legacy metadata: %s", fqn, errstr) // (superseded anyway)
+		}
+	}
+	if glog.V(4) {
+		glog.Infof("%s: migrated metadata", fqn)
+	}
+}
+
+func writeMeta(fqn string, md *lmeta) (errstr string) {
+	b := md.marshal()
+	if len(b) >= fs.MaxAttrSize {
+		return fmt.Sprintf("%s: metadata record is too large (%d)", fqn, len(b))
+	}
+	errstr = fs.SetXattr(fqn, cmn.XattrLOM, b)
+	invalidateMeta(fqn)
+	return
+}
+
+//
+// All the metadata updates are read-modify-write of the same record and are
+// serialized by the (striped) per-FQN meta locks - independently of the name
+// locks that the callers may (or may not) hold, and of their mode.
+//
+
+const metaLockStripes = 256
+
+var metaLocks [metaLockStripes]sync.Mutex
+
+func metaLock(fqn string) *sync.Mutex {
+	return &metaLocks[xxhash.ChecksumString64(fqn)%metaLockStripes]
+}
+
+// read-modify-write (and migrate)
+func updateMeta(fqn string, update func(md *lmeta)) (errstr string) {
+	mtx := metaLock(fqn)
+	mtx.Lock()
+	defer mtx.Unlock()
+	md, legacy, errstr := readMeta(fqn)
+	if errstr != "" {
+		return
+	}
+	update(md)
+	if errstr = writeMeta(fqn, md); errstr == "" && len(legacy) > 0 {
+		delLegacyMeta(fqn, legacy)
+	}
+	return
+}
+
+// SetXattrCksum updates the checksum stored with the object - for tools and tests
+func SetXattrCksum(fqn string, cksum cmn.CksumProvider) (errstr string) {
+	return updateMeta(fqn, func(md *lmeta) { md.setCksum(cksum) })
+}
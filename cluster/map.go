@@ -33,16 +33,57 @@ type NetInfo struct {
 	DirectURL  string `json:"direct_url"`
 }
 
+// failure domains, from the largest to the smallest (see NodeLabels)
+const (
+	DomainZone = "zone"
+	DomainRack = "rack"
+	DomainHost = "host"
+)
+
+// NodeLabels locate the target in the cluster's topology; targets that share a
+// label value share the corresponding failure domain (empty value - unknown)
+type NodeLabels struct {
+	Zone string `json:"zone,omitempty"`
+	Rack string `json:"rack,omitempty"`
+	Host string `json:"host,omitempty"`
+}
+
+func (l *NodeLabels) IsEmpty() bool { return l.Zone == "" && l.Rack == "" && l.Host == "" }
+
+// domain returns the label's value for a given failure domain qualified with
+// the values of the enclosing domains (e.g., rack "r1" in zone "z1" => "z1/r1")
+func (l *NodeLabels) domain(level int) string {
+	switch level {
+	case 0:
+		return l.Zone
+	case 1:
+		if l.Rack == "" {
+			return ""
+		}
+		return l.Zone + "/" + l.Rack
+	default:
+		if l.Host == "" {
+			return ""
+		}
+		return l.Zone + "/" + l.Rack + "/" + l.Host
+	}
+}
+
+var domainLevels = [3]string{DomainZone, DomainRack, DomainHost}
+
 //==================================================================
 //
 // Snode: represents storage daemon in a cluster (gateway or target)
 //
 //==================================================================
 type Snode struct {
-	DaemonID        string  `json:"daemon_id"`
-	PublicNet       NetInfo `json:"public_net"`        // cmn.NetworkPublic
-	IntraControlNet NetInfo `json:"intra_control_net"` // cmn.NetworkIntraControl
-	IntraDataNet    NetInfo `json:"intra_data_net"`    // cmn.NetworkIntraData
+	DaemonID        string     `json:"daemon_id"`
+	PublicNet       NetInfo    `json:"public_net"`           // cmn.NetworkPublic
+	IntraControlNet NetInfo    `json:"intra_control_net"`    // cmn.NetworkIntraControl
+	IntraDataNet    NetInfo    `json:"intra_data_net"`       // cmn.NetworkIntraData
+	Weight          int64      `json:"weight,omitempty"`     // relative placement weight declared by the target (0 - default)
+	WeightCap       bool       `json:"weight_cap,omitempty"` // the declared weight is the target's capacity (GiB)
+	Labels          NodeLabels `json:"labels"`               // failure domains: zone, rack, host (target only)
 	idDigest        uint64
 }
 
@@ -80,7 +121,9 @@ func (a *Snode) Equals(b *Snode) bool {
 	return a.DaemonID == b.DaemonID &&
 		reflect.DeepEqual(a.PublicNet, b.PublicNet) &&
 		reflect.DeepEqual(a.IntraControlNet, b.IntraControlNet) &&
-		reflect.DeepEqual(a.IntraDataNet, b.IntraDataNet)
+		reflect.DeepEqual(a.IntraDataNet, b.IntraDataNet) &&
+		a.Weight == b.Weight && a.WeightCap == b.WeightCap &&
+		a.Labels == b.Labels
 }
 
 //===============================================================
@@ -98,11 +141,30 @@ type (
 		Tmap      NodeMap       `json:"tmap"` // daemonID -> Snode
 		Pmap      NodeMap       `json:"pmap"` // proxyID -> proxyInfo
 		NonElects cmn.SimpleKVs `json:"non_electable"`
-		ProxySI   *Snode        `json:"proxy_si"`
-		Version   int64         `json:"version"`
+		// targets in maintenance: daemonID -> mode (cmn.MaintenanceReboot, etc.)
+		Maintenance cmn.SimpleKVs `json:"maintenance,omitempty"`
+		// placement weights set by the operator: daemonID -> weight (overrides Snode.Weight)
+		Weights map[string]int64 `json:"weights,omitempty"`
+		ProxySI *Snode           `json:"proxy_si"`
+		Version int64            `json:"version"`
+		// whether all targets weigh the same and whether any of them is labeled -
+		// cached when the Smap gets installed (see InitPlacement); zero for the
+		// Smaps that are not (e.g., being modified)
+		weights int32
+		labels  int32
 	}
 )
 
+// Smap.weights and Smap.labels
+const (
+	weightsUniform = int32(1) + iota
+	weightsVaried
+)
+const (
+	labelsNone = int32(1) + iota
+	labelsDeclared
+)
+
 func (m *Smap) CountTargets() int { return len(m.Tmap) }
 func (m *Smap) CountProxies() int { return len(m.Pmap) }
 
@@ -122,6 +184,122 @@ func (m *Smap) GetProxy(pid string) *Snode {
 	return pi
 }
 
+// MaintenanceMode returns the target's maintenance mode or empty string
+func (m *Smap) MaintenanceMode(sid string) string { return m.Maintenance[sid] }
+
+// Decommissioning returns true if the target must not be selected
+// for new placements (see HrwTarget)
+func (m *Smap) Decommissioning(sid string) bool {
+	return m.Maintenance[sid] == cmn.MaintenanceDecommission
+}
+
+// CountPlacementTargets returns the number of targets eligible for new placements
+func (m *Smap) CountPlacementTargets() int {
+	cnt := len(m.Tmap)
+	for sid := range m.Maintenance {
+		if m.Decommissioning(sid) && m.GetTarget(sid) != nil {
+			cnt--
+		}
+	}
+	return cnt
+}
+
+// Weight returns the target's effective placement weight: the one set by the
+// operator, if any, otherwise the one declared by the target itself (default 1)
+func (m *Smap) Weight(sid string) int64 {
+	if w, ok := m.Weights[sid]; ok && w > 0 {
+		return w
+	}
+	if si := m.GetTarget(sid); si != nil && si.Weight > 0 {
+		return si.Weight
+	}
+	return 1
+}
+
+// FailureDomain returns the largest failure domain (zone, rack or host) that
+// counts at least two distinct values across the placement targets, and the
+// number of those values; empty string when the targets are not labeled
+func (m *Smap) FailureDomain() (domain string, n int) {
+	for level, name := range domainLevels {
+		values := make(map[string]struct{})
+		for sid, si := range m.Tmap {
+			if m.Decommissioning(sid) {
+				continue
+			}
+			if v := si.Labels.domain(level); v != "" {
+				values[v] = struct{}{}
+			}
+		}
+		if len(values) > 1 {
+			return name, len(values)
+		}
+	}
+	return "", 0
+}
+
+// labeled returns true if at least one placement target declares its failure domains
+func (m *Smap) labeled() bool {
+	switch m.labels {
+	case labelsDeclared:
+		return true
+	case labelsNone:
+		return false
+	}
+	return m.computeLabeled()
+}
+
+func (m *Smap) computeLabeled() bool {
+	for sid, si := range m.Tmap {
+		if !m.Decommissioning(sid) && !si.Labels.IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
+
+// InitPlacement computes and caches whether all the (placement) targets weigh
+// the same and whether any of them is labeled - to be called once the Smap is
+// installed and is no longer modified
+func (m *Smap) InitPlacement() {
+	m.weights, m.labels = weightsVaried, labelsNone
+	if m.computeUniformWeights() {
+		m.weights = weightsUniform
+	}
+	if m.computeLabeled() {
+		m.labels = labelsDeclared
+	}
+}
+
+// ClearPlacement drops the cached values - for a copy of the installed Smap that gets modified
+func (m *Smap) ClearPlacement() { m.weights, m.labels = 0, 0 }
+
+// uniformWeights returns true if all the (placement) targets weigh the same,
+// in which case HRW reduces to its classic unweighted variant
+func (m *Smap) uniformWeights() bool {
+	switch m.weights {
+	case weightsUniform:
+		return true
+	case weightsVaried:
+		return false
+	}
+	return m.computeUniformWeights()
+}
+
+func (m *Smap) computeUniformWeights() bool {
+	var w int64
+	for sid := range m.Tmap {
+		if m.Decommissioning(sid) {
+			continue
+		}
+		if sw := m.Weight(sid); w == 0 {
+			w = sw
+		} else if sw != w {
+			return false
+		}
+	}
+	return true
+}
+
 func (a *Smap) Equals(b *Smap) bool {
 	if a.Version != b.Version {
 		return false
@@ -132,6 +310,12 @@ func (a *Smap) Equals(b *Smap) bool {
 	if !reflect.DeepEqual(a.NonElects, b.NonElects) {
 		return false
 	}
+	if len(a.Maintenance) != len(b.Maintenance) || (len(a.Maintenance) > 0 && !reflect.DeepEqual(a.Maintenance, b.Maintenance)) {
+		return false
+	}
+	if len(a.Weights) != len(b.Weights) || (len(a.Weights) > 0 && !reflect.DeepEqual(a.Weights, b.Weights)) {
+		return false
+	}
 	return mapsEq(a.Tmap, b.Tmap) && mapsEq(a.Pmap, b.Pmap)
 }
 func mapsEq(a, b NodeMap) bool {
@@ -4,6 +4,8 @@
  */
 package cluster
 
+import "context"
+
 // NameLocker interface locks and unlocks (and try-locks, etc.)
 // arbitrary strings.
 // NameLocker is currently utilized to lock objects stored in the cluster
@@ -11,12 +13,15 @@ package cluster
 // the object in question to get updated or evicted concurrently.
 // Objects are locked by their unique (string) names aka unames.
 // The lock can be exclusive (write) or shared (read).
+// LockCtx waits for the lock at most until the context is done (canceled or
+// past its deadline), and returns the context's error if it did not get the lock.
 //
 // For implementation, please refer to ais/rtnames.go
 
 type NameLocker interface {
 	TryLock(uname string, exclusive bool) bool
 	Lock(uname string, exclusive bool)
+	LockCtx(ctx context.Context, uname string, exclusive bool) error
 	DowngradeLock(uname string)
 	Unlock(uname string, exclusive bool)
 }
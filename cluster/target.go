@@ -25,4 +25,5 @@ type Target interface {
 	GetMem2() *memsys.Mem2
 	Receive(workFQN string, reader io.ReadCloser, lom *LOM) error
 	GetFSPRG() fs.PathRunGroup
+	Demote(lom *LOM) error
 }
@@ -18,8 +18,9 @@ var (
 
 // TargetMock implements Target interface with mocked return values.
 type TargetMock struct {
-	Atime *atime.Runner
-	BO    Bowner
+	Atime    *atime.Runner
+	BO       Bowner
+	DemoteFn func(lom *LOM) error // nil: demotes successfully
 }
 
 func NewTargetMock(bo Bowner) *TargetMock {
@@ -40,3 +41,10 @@ func (t *TargetMock) GetAtimeRunner() *atime.Runner
 func (t *TargetMock) GetMem2() *memsys.Mem2                                        { return memsys.Init() }
 func (t *TargetMock) Receive(workFQN string, reader io.ReadCloser, lom *LOM) error { return nil }
 func (t *TargetMock) GetFSPRG() fs.PathRunGroup                                    { return nil }
+
+func (t *TargetMock) Demote(lom *LOM) error {
+	if t.DemoteFn != nil {
+		return t.DemoteFn(lom)
+	}
+	return nil
+}
@@ -13,6 +13,8 @@ import (
 // string enum: http header, checksum, versioning
 const (
 	// http header
+	XattrLOM = "user.ais.lom" // object metadata record (see cluster.LOM)
+	// legacy object metadata layout - migrated to XattrLOM upon first access
 	XattrXXHash  = "user.obj.xxhash"
 	XattrVersion = "user.obj.version"
 	XattrCopies  = "user.obj.copies"
@@ -42,6 +44,7 @@ const (
 	ActGlobalReb    = "rebalance"      // global cluster-wide rebalance
 	ActLocalReb     = "localrebalance" // local rebalance
 	ActRechecksum   = "rechecksum"
+	ActResync       = "resync" // resync cached objects with their cloud bucket
 	ActLRU          = "lru"
 	ActSyncLB       = "synclb"
 	ActCreateLB     = "createlb"
@@ -62,12 +65,27 @@ const (
 	ActRegProxy     = "regproxy"
 	ActUnregTarget  = "unregtarget"
 	ActUnregProxy   = "unregproxy"
+	ActStartMaint   = "startmaintenance" // put target in maintenance (e.g., for reboot)
+	ActStopMaint    = "stopmaintenance"  // bring target back from maintenance
+	ActDecommission = "decommission"     // migrate target's content and then unregister
+	ActSetWeight    = "setweight"        // set target's placement weight (0 - revert to the one declared by the target)
 	ActNewPrimary   = "newprimary"
 	ActRevokeToken  = "revoketoken"
 	ActElection     = "election"
 	ActPutCopies    = "putcopies"
 	ActEraseCopies  = "erasecopies"
-	ActEC           = "ec" // erasure (en)code objects
+	ActEC           = "ec"        // erasure (en)code objects
+	ActXactStart    = "xactstart" // start xaction (see XactMsg and /v1/xactions)
+	ActXactStop     = "xactstop"  // abort xaction(s) by (target, ID) or kind
+	ActAddJob       = "addjob"    // add or replace scheduled job (see Job)
+	ActRemoveJob    = "removejob" // remove scheduled job by name
+	ActScrub        = "scrub"     // background data scrubber (see ScrubConf)
+	ActGC           = "gc"        // garbage collection of orphaned workfiles and intermediate content (see GCConf)
+
+	// cluster-wide configuration (see ClusterConf); setconfig sets values as well
+	ActUnsetConfig    = "unsetconfig"    // remove cluster-wide value or per-node override
+	ActRollbackConfig = "rollbackconfig" // make a previous version current again
+	ActReloadConfig   = "reloadconfig"   // re-read and apply the configuration file (see ConfigReload)
 
 	// Actions for manipulating mountpaths (/v1/daemon/mountpaths)
 	ActMountpathEnable  = "enable"
@@ -79,6 +97,25 @@ const (
 	ActPersist = "persist" // store a piece of metadata or configuration
 )
 
+// Target maintenance modes (see Smap.Maintenance)
+const (
+	// the target is (or is about to be) briefly down: the cluster map keeps it,
+	// HRW placement does not change and rebalance is not triggered
+	MaintenanceReboot = "reboot"
+	// the target accepts no new placements but keeps serving reads
+	// while migrating its content to the new HRW owners
+	MaintenanceDecommission = "decommission"
+)
+
+// Placement policies reported per bucket (see HeaderBucketPlacement)
+const (
+	// EC slices and replicas go to the HRW-selected targets
+	PlacementHRW = "hrw"
+	// EC slices and replicas are spread across distinct failure domains,
+	// reported as "spread:<domain>", e.g. "spread:rack"
+	PlacementSpread = "spread"
+)
+
 // Cloud Provider enum
 const (
 	ProviderAmazon = "aws"
@@ -122,6 +159,9 @@ const (
 	HeaderBucketECData          = "ec.data_slices"          // number of data chunks for EC
 	HeaderBucketECParity        = "ec.parity_slices"        // number of parity chunks for EC/copies for small files
 
+	// bucket placement (read-only)
+	HeaderBucketPlacement = "placement" // placement policy of the bucket's EC slices and replicas
+
 	// object meta
 	HeaderObjCksumType = "ObjCksumType" // Checksum Type (xxhash, md5, none)
 	HeaderObjCksumVal  = "ObjCksumVal"  // Checksum Value
@@ -140,6 +180,10 @@ const (
 	URLParamOffset      = "offset"       // Offset from where the object should be read
 	URLParamLength      = "length"       // the total number of bytes that need to be read from the offset
 	URLParamBckProvider = "bprovider"    // "local" | "cloud"
+
+	// rebalance preview: comma-separated IDs of the hypothetically added and removed targets
+	URLParamAddTargets    = "add_targets"
+	URLParamRemoveTargets = "remove_targets"
 	// internal use
 	URLParamFromID           = "fid" // source target ID
 	URLParamToID             = "tid" // destination target ID
@@ -153,11 +197,30 @@ const (
 	URLParamBMDVersion       = "vbm" // version of the bucket-metadata
 	URLParamUnixTime         = "utm" // Unix time: number of nanoseconds elapsed since 01/01/70 UTC
 	URLParamReadahead        = "rah" // Proxy to target: readeahed
+	URLParamTierHops         = "thp" // number of AIStore tiers the request has traversed so far
 
 	// dsort
 	URLParamTotalCompressedSize   = "tcs"
 	URLParamTotalUncompressedSize = "tunc"
 	URLParamTotalInputShardsSeen  = "tiss"
+
+	// cluster event log filters (see GetWhatEvents)
+	URLParamEventType = "etype" // comma-separated event types (the Event* enum)
+	URLParamSince     = "since" // RFC 3339 time: only the events (xactions) that happened (ran) at or after
+	URLParamUntil     = "until" // RFC 3339 time: only the events (xactions) that happened (ran) before
+
+	// xaction filters (see /v1/xactions)
+	URLParamXactKind   = "kind"   // e.g. "rebalance" | "lru" - see ActionMsg.Action enum
+	URLParamXactBucket = "bucket" // bucket-specific xactions only
+	URLParamXactID     = "xid"    // target-assigned xaction ID
+	URLParamXactAll    = "all"    // true: include finished xactions
+
+	// cluster-wide configuration (see GetWhatClusterConf and GetWhatConfDiff)
+	URLParamNode        = "node"    // daemon ID: set (unset) the node's override rather than cluster-wide value
+	URLParamConfVersion = "version" // version of the cluster-wide configuration
+	URLParamConfFrom    = "from"    // diff: from version
+	URLParamConfTo      = "to"      // diff: to version (default: current)
+	URLParamDryRun      = "dry_run" // reloadconfig, setconfig (node): validate (and report changes) without applying
 )
 
 // TODO: sort and some props are TBD
@@ -200,6 +263,231 @@ type MountpathList struct {
 	Disabled  []string `json:"disabled"`
 }
 
+// NameLockInfo describes an object lock that is currently held and/or waited for
+// on a given target (see GetWhatLocks)
+type (
+	NameLockWaiter struct {
+		Exclusive bool          `json:"exclusive"`
+		Waiting   time.Duration `json:"waiting"` // for how long
+	}
+	NameLockInfo struct {
+		Uname     string           `json:"uname"`
+		Exclusive bool             `json:"exclusive"`
+		Readers   int              `json:"readers"` // number of shared holders
+		Age       time.Duration    `json:"age"`     // held for how long
+		Waiters   []NameLockWaiter `json:"waiters,omitempty"`
+	}
+)
+
+// ClusterConf is a version of the cluster-wide configuration: named values (the
+// same names that setconfig accepts) that apply to all nodes, and per-node overrides
+// layered on top; ConfChange is a single difference between two versions
+type (
+	ClusterConf struct {
+		Version   int64                `json:"version"`
+		Time      time.Time            `json:"time"`
+		Action    string               `json:"action"` // the change that produced this version
+		Values    SimpleKVs            `json:"values"`
+		Overrides map[string]SimpleKVs `json:"overrides,omitempty"` // daemon ID => values
+	}
+	ConfChange struct {
+		Node string `json:"node,omitempty"` // daemon ID (override), empty for cluster-wide value
+		Name string `json:"name"`
+		From string `json:"from"` // empty: not set
+		To   string `json:"to"`   // empty: unset
+	}
+)
+
+// ConfigReload is the result of re-reading the configuration file (see ActReloadConfig);
+// ConfigFileChange is a value in the file that differs from the one in effect
+type (
+	ConfigReload struct {
+		DryRun  bool               `json:"dry_run"`
+		Changes []ConfigFileChange `json:"changes"`
+		Err     string             `json:"error,omitempty"` // cluster-wide reload: the node failed to reload
+	}
+	ConfigFileChange struct {
+		Name       string `json:"name"`
+		From       string `json:"from"`
+		To         string `json:"to"`
+		Restart    bool   `json:"restart,omitempty"`    // cannot be changed at runtime: takes effect upon restart
+		Overridden bool   `json:"overridden,omitempty"` // cluster-wide configuration takes precedence
+	}
+)
+
+// CloudBreakerStats is the current state of the circuit breaker of the target's Cloud
+// provider (see cmn.CloudConf), reported with the target's statistics
+type CloudBreakerStats struct {
+	Provider string    `json:"provider"`
+	State    string    `json:"state"`    // CloudBreaker* enum
+	Failures int       `json:"failures"` // consecutive transient failures
+	OpenedAt time.Time `json:"opened_at,omitempty"`
+}
+
+// CloudBreakerStats.State enum
+const (
+	CloudBreakerDisabled = "disabled" // cloud.breaker_threshold is zero
+	CloudBreakerClosed   = "closed"
+	CloudBreakerOpen     = "open"
+	CloudBreakerHalfOpen = "half-open" // letting a probe request through
+)
+
+// KeepaliveStats is the current state of the proxy's keepalive tracking (see
+// cmn.KeepaliveTrackerConf), reported with the proxy's statistics
+type KeepaliveStats struct {
+	Tracker  string               `json:"tracker"`            // KeepaliveTrackerConf.Name
+	Phi      map[string]float64   `json:"phi,omitempty"`      // suspicion levels (phi tracker only)
+	Suspects map[string]time.Time `json:"suspects,omitempty"` // non-responding nodes and when first suspected
+}
+
+// RebPreview is the result of the rebalance preview for a hypothetical cluster
+// change (see GetWhatRebPreview): objects and bytes that would move between
+// the targets - by source and destination, and in total
+type (
+	RebPreviewMove struct {
+		Objects int64 `json:"objects"`
+		Size    int64 `json:"size"`
+	}
+	RebPreview struct {
+		Matrix map[string]map[string]RebPreviewMove `json:"matrix"` // source => destination => moved
+		From   map[string]RebPreviewMove            `json:"from"`   // totals by source
+		To     map[string]RebPreviewMove            `json:"to"`     // totals by destination
+		Total  RebPreviewMove                       `json:"total"`
+	}
+)
+
+func NewRebPreview() *RebPreview {
+	return &RebPreview{
+		Matrix: make(map[string]map[string]RebPreviewMove),
+		From:   make(map[string]RebPreviewMove),
+		To:     make(map[string]RebPreviewMove),
+	}
+}
+
+func (m *RebPreviewMove) add(other RebPreviewMove) {
+	m.Objects += other.Objects
+	m.Size += other.Size
+}
+
+// Add aggregates the moves computed by a given source target
+func (p *RebPreview) Add(from string, moves map[string]RebPreviewMove) {
+	for to, move := range moves {
+		if p.Matrix[from] == nil {
+			p.Matrix[from] = make(map[string]RebPreviewMove, len(moves))
+		}
+		m := p.Matrix[from][to]
+		m.add(move)
+		p.Matrix[from][to] = m
+		f := p.From[from]
+		f.add(move)
+		p.From[from] = f
+		t := p.To[to]
+		t.add(move)
+		p.To[to] = t
+		p.Total.add(move)
+	}
+}
+
+// cluster event types
+const (
+	EventSmap      = "smap"      // cluster map version, with the nodes that joined or left
+	EventBMD       = "bmd"       // bucket metadata version, with the buckets that were created, destroyed, or changed
+	EventElection  = "election"  // new primary
+	EventRebalance = "rebalance" // global and local rebalance start and end, by target
+	EventMountpath = "mountpath" // mountpath enabled, disabled, added, or removed, by target
+	EventConfig    = "config"    // cluster-wide configuration change
+	EventJob       = "job"       // scheduled job started, skipped, queued, or finished (with the outcome)
+)
+
+// ClusterEvent is a record in the cluster event log that the primary proxy
+// maintains and replicates to all proxies (see GetWhatEvents)
+type ClusterEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`              // one of the Event* enum above
+	Node    string    `json:"node"`              // ID of the node that reported the event
+	Version int64     `json:"version,omitempty"` // Smap or BMD version, if applicable
+	Msg     string    `json:"msg"`
+}
+
+// XactMsg identifies xaction(s) to start, abort, or query (see /v1/xactions);
+// it is carried in the ActionMsg.Value
+type XactMsg struct {
+	ID     int64  `json:"id,omitempty"`     // abort: xaction ID (takes precedence over kind)
+	Target string `json:"target,omitempty"` // abort by ID: the target that runs the xaction (IDs are unique only within a target)
+	Kind   string `json:"kind,omitempty"`   // e.g. ActGlobalReb, ActLRU
+	Bucket string `json:"bucket,omitempty"` // bucket-specific xactions, e.g. ActRechecksum
+	// query only: the xactions that were running at any time within [Since, Until),
+	// where zero time means no limit (see URLParamSince and URLParamUntil)
+	Since time.Time `json:"-"`
+	Until time.Time `json:"-"`
+}
+
+// ScrubConf.Policy enum: what the scrubber does about the problems it finds
+const (
+	ScrubReport     = "report"     // only report (default)
+	ScrubQuarantine = "quarantine" // move corrupted objects and orphaned content aside (see fs.QuarantineType)
+	ScrubRepair     = "repair"     // restore corrupted objects from a mirror, EC, or the Cloud; clean up the rest
+)
+
+// ScrubFinding.Type and ScrubFinding.Action enums
+const (
+	ScrubCorrupted    = "corrupted"         // object or its mirror copy does not match its stored checksum
+	ScrubMisplaced    = "misplaced"         // object is stored on the wrong mountpath or target
+	ScrubWorkfile     = "orphaned-workfile" // workfile left behind by a previous run of the target
+	ScrubECSlice      = "ec-slice-no-meta"  // EC slice without its metafile
+	ScrubDanglingCopy = "dangling-copy"     // mirror copy that points nowhere (or the object that points to a missing copy)
+
+	ScrubActReported    = "reported"
+	ScrubActRepaired    = "repaired"
+	ScrubActQuarantined = "quarantined"
+	ScrubActRemoved     = "removed"
+)
+
+// ScrubStats is the progress of the scrubber (see XactStats) with its most recent findings
+type (
+	ScrubStats struct {
+		Policy      string         `json:"policy"`
+		Pass        int64          `json:"pass"`      // current pass, starting from 1
+		Resumed     bool           `json:"resumed"`   // current pass resumed from checkpoint
+		Checked     int64          `json:"checked"`   // objects verified
+		Corrupted   int64          `json:"corrupted"` // (see ScrubFinding.Type)
+		Misplaced   int64          `json:"misplaced"`
+		Workfiles   int64          `json:"orphaned_workfiles"`
+		ECSlices    int64          `json:"ec_slices_no_meta"`
+		Copies      int64          `json:"dangling_copies"`
+		Repaired    int64          `json:"repaired"` // (see ScrubFinding.Action)
+		Quarantined int64          `json:"quarantined"`
+		Removed     int64          `json:"removed"`
+		Findings    []ScrubFinding `json:"findings,omitempty"`
+	}
+	ScrubFinding struct {
+		Time   time.Time `json:"time"`
+		Type   string    `json:"type"`
+		FQN    string    `json:"fqn"`
+		Action string    `json:"action"`
+		Detail string    `json:"detail,omitempty"` // e.g., where from the object was restored, or why it was not
+	}
+)
+
+// Job.Overlap enum: what to do when a job is due while its previous run is still in progress
+const (
+	JobOverlapSkip  = "skip"  // skip this run (default)
+	JobOverlapQueue = "queue" // run again as soon as the previous run finishes (at most one run gets queued)
+)
+
+// Job is a cron-style scheduled run of an xaction that the primary proxy starts
+// on all targets (see ActAddJob and GetWhatJobs); job definitions are replicated
+// to all proxies and survive primary failover
+type Job struct {
+	Name        string      `json:"name"`
+	Schedule    string      `json:"schedule"`            // cron expression, e.g. "0 2 * * *" (see ParseCron)
+	Kind        string      `json:"kind"`                // ActLRU, ActRechecksum, ActPrefetch, ActEraseCopies, ActGlobalReb, or ActLocalReb
+	Bucket      string      `json:"bucket,omitempty"`    // required for rechecksum, prefetch, and erasecopies
+	BckProvider string      `json:"bprovider,omitempty"` // ditto, optional
+	Value       interface{} `json:"value,omitempty"`     // kind-specific parameters, e.g. prefetch ListMsg or RangeMsg
+	Overlap     string      `json:"overlap,omitempty"`   // JobOverlapSkip or JobOverlapQueue
+}
+
 //===================
 //
 // RESTful GET
@@ -217,6 +505,15 @@ const (
 	GetWhatMountpaths = "mountpaths"
 	GetWhatDaemonInfo = "daemoninfo"
 	GetWhatSysInfo    = "sysinfo"
+	GetWhatRebPreview = "rebpreview" // dry-run rebalance: see URLParamAddTargets and URLParamRemoveTargets
+	GetWhatEvents     = "events"     // cluster event log: see URLParamEventType, URLParamSince, and URLParamUntil
+	GetWhatJobs       = "jobs"       // scheduled jobs (see Job)
+	GetWhatLocks      = "locks"      // object (name) locks held and waited for on a target (see NameLockInfo)
+
+	// cluster-wide configuration (see ClusterConf)
+	GetWhatClusterConf = "clusterconf" // current or given version (see URLParamConfVersion)
+	GetWhatConfHistory = "confhistory" // all versions, the oldest first
+	GetWhatConfDiff    = "confdiff"    // changes between two versions: see URLParamConfFrom and URLParamConfTo
 )
 
 // GetMsg.GetSort enum
@@ -287,6 +584,82 @@ type BucketList struct {
 	PageMarker string         `json:"pagemarker"`
 }
 
+// ResyncMsg contains parameters of the cloud bucket resync (ActResync) xaction
+type ResyncMsg struct {
+	Prefix   string `json:"prefix"`   // resync only the objects with names that start with the prefix
+	Evict    bool   `json:"evict"`    // evict cached objects that were changed or deleted in the Cloud
+	Refresh  bool   `json:"refresh"`  // re-fetch (cold GET) cached objects that were changed in the Cloud
+	Prefetch bool   `json:"prefetch"` // prefetch objects that were added to the Cloud bucket
+	Cksum    bool   `json:"cksum"`    // compare MD5 of cached objects with the Cloud (expensive)
+}
+
+// ResyncReport is the result of the ActResync xaction: objects that were added,
+// changed or deleted in the Cloud, as compared with their cached copies.
+// NOTE: the names are capped at ResyncMaxNames (per category) and the errors at
+// ResyncMaxErrors, the counts are not
+type ResyncReport struct {
+	Added        []string `json:"added"`
+	Changed      []string `json:"changed"`
+	Deleted      []string `json:"deleted"`
+	AddedCount   int64    `json:"added_n"`
+	ChangedCount int64    `json:"changed_n"`
+	DeletedCount int64    `json:"deleted_n"`
+	Evicted      int64    `json:"evicted_n"`
+	Refreshed    int64    `json:"refreshed_n"`
+	Prefetched   int64    `json:"prefetched_n"`
+	Errors       []string `json:"errors,omitempty"`
+	ErrorsCount  int64    `json:"errors_n"`
+}
+
+const (
+	ResyncMaxNames  = 1000
+	ResyncMaxErrors = 100
+)
+
+func (rep *ResyncReport) Merge(other *ResyncReport) {
+	rep.Added = appendCapped(rep.Added, ResyncMaxNames, other.Added...)
+	rep.Changed = appendCapped(rep.Changed, ResyncMaxNames, other.Changed...)
+	rep.Deleted = appendCapped(rep.Deleted, ResyncMaxNames, other.Deleted...)
+	rep.AddedCount += other.AddedCount
+	rep.ChangedCount += other.ChangedCount
+	rep.DeletedCount += other.DeletedCount
+	rep.Evicted += other.Evicted
+	rep.Refreshed += other.Refreshed
+	rep.Prefetched += other.Prefetched
+	rep.Errors = appendCapped(rep.Errors, ResyncMaxErrors, other.Errors...)
+	rep.ErrorsCount += other.ErrorsCount
+}
+
+func (rep *ResyncReport) AddAdded(name string) {
+	rep.AddedCount++
+	rep.Added = appendCapped(rep.Added, ResyncMaxNames, name)
+}
+
+func (rep *ResyncReport) AddChanged(name string) {
+	rep.ChangedCount++
+	rep.Changed = appendCapped(rep.Changed, ResyncMaxNames, name)
+}
+
+func (rep *ResyncReport) AddDeleted(name string) {
+	rep.DeletedCount++
+	rep.Deleted = appendCapped(rep.Deleted, ResyncMaxNames, name)
+}
+
+func (rep *ResyncReport) AddError(errstr string) {
+	rep.ErrorsCount++
+	rep.Errors = appendCapped(rep.Errors, ResyncMaxErrors, errstr)
+}
+
+func appendCapped(names []string, max int, add ...string) []string {
+	for _, name := range add {
+		if len(names) >= max {
+			break
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
 // BucketNames is used to transfer all bucket names known to the system
 type BucketNames struct {
 	Cloud []string `json:"cloud"`
@@ -314,6 +687,7 @@ const (
 	Health    = "health"
 	Vote      = "vote"
 	Transport = "transport"
+	Xactions  = "xactions"
 	// l3
 	SyncSmap   = "syncsmap"
 	Keepalive  = "keepalive"
@@ -323,6 +697,7 @@ const (
 	Voteres    = "result"
 	VoteInit   = "init"
 	Mountpaths = "mountpaths"
+	Events     = "events"
 
 	// dsort
 	Init        = "init"
@@ -407,6 +782,7 @@ const (
 	// Denote the status of an Xaction
 	XactionStatusInProgress = "InProgress"
 	XactionStatusCompleted  = "Completed"
+	XactionStatusAborted    = "Aborted"
 )
 const (
 	RWPolicyCloud    = "cloud"
@@ -451,6 +827,11 @@ type BucketProps struct {
 
 	// EC defines erasure coding setting for the bucket
 	EC ECConf `json:"ec"`
+
+	// Placement is the (read-only) placement policy of the bucket's EC slices
+	// and replicas as per the current cluster map: PlacementHRW or
+	// PlacementSpread qualified with the failure domain
+	Placement string `json:"placement,omitempty"`
 }
 
 // ECConfig - per-bucket erasure coding configuration
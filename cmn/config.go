@@ -5,9 +5,13 @@
 package cmn
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -31,6 +35,14 @@ const (
 	MountpathBackupFile = "mpaths"          // base name to persist fs.Mountpaths
 	GlobalRebMarker     = ".global_rebalancing"
 	LocalRebMarker      = ".local_rebalancing"
+	GlobalRebCheckpoint = ".global_rebalancing.ckpt" // progress of the interrupted rebalance
+	LocalRebCheckpoint  = ".local_rebalancing.ckpt"
+	EventsBackupFile    = "events.json"   // cluster event log (proxies only)
+	XactHistoryFile     = "xactions.json" // finished xactions (targets only)
+	JobsBackupFile      = "jobs.json"     // scheduled jobs (proxies only)
+
+	ClusterConfFile = "clusterconf.json" // cluster-wide configuration and its previous versions
+	ScrubCheckpoint = ".scrubbing.ckpt"  // progress of the interrupted scrubbing pass
 )
 
 const (
@@ -39,6 +51,7 @@ const (
 
 	KeepaliveHeartbeatType = "heartbeat"
 	KeepaliveAverageType   = "average"
+	KeepalivePhiType       = "phi"
 )
 
 const (
@@ -99,6 +112,8 @@ type globalConfigOwner struct {
 	lmtx      sync.Mutex // mutex for protecting listeners
 	listeners []ConfigListener
 	confFile  string
+	fc        unsafe.Pointer // config as last loaded from (or stored into) the file - see DiffConfigFile
+	sc        unsafe.Pointer // config as loaded from the file at startup
 }
 
 var (
@@ -170,6 +185,30 @@ func (gco *globalConfigOwner) GetConfigFile() string {
 	return gco.confFile
 }
 
+// SetFileConfig records the config as loaded from (or stored into) the configuration file;
+// the first one (loaded at startup) is also kept as the baseline for the values that
+// require restart
+func (gco *globalConfigOwner) SetFileConfig(config *Config) {
+	fc := &Config{}
+	CopyStruct(fc, config)
+	atomic.StorePointer(&gco.fc, unsafe.Pointer(fc))
+	atomic.CompareAndSwapPointer(&gco.sc, nil, unsafe.Pointer(fc))
+}
+
+// FileConfig returns the config as last loaded from (or stored into) the configuration file
+func (gco *globalConfigOwner) FileConfig() *Config {
+	return (*Config)(atomic.LoadPointer(&gco.fc))
+}
+
+// SaveConfigFile stores the config into the configuration file (path)
+func SaveConfigFile(path string, config *Config) error {
+	if err := LocalSave(path, config); err != nil {
+		return err
+	}
+	GCO.SetFileConfig(config)
+	return nil
+}
+
 func (gco *globalConfigOwner) notifyListeners(oldConf *Config) {
 	gco.lmtx.Lock()
 	newConf := gco.Get()
@@ -210,6 +249,9 @@ type Config struct {
 	FSHC             FSHCConf        `json:"fshc"`
 	Auth             AuthConf        `json:"auth"`
 	KeepaliveTracker KeepaliveConf   `json:"keepalivetracker"`
+	Cloud            CloudConf       `json:"cloud"`
+	Scrub            ScrubConf       `json:"scrub"`
+	GC               GCConf          `json:"gc"`
 }
 
 type MirrorConf struct {
@@ -309,14 +351,19 @@ type LRUConf struct {
 }
 
 type XactionConf struct {
-	DiskUtilLowWM  int64 `json:"disk_util_low_wm"`  // Low watermark below which no throttling is required
-	DiskUtilHighWM int64 `json:"disk_util_high_wm"` // High watermark above which throttling is required for longer duration
+	DiskUtilLowWM  int64  `json:"disk_util_low_wm"`  // Low watermark below which no throttling is required
+	DiskUtilHighWM int64  `json:"disk_util_high_wm"` // High watermark above which throttling is required for longer duration
+	BandwidthStr   string `json:"bandwidth"`         // max aggregate transmit rate of all xactions per target (bytes/s); "" or 0 - unlimited
+	Bandwidth      int64  `json:"-"`                 //
 }
 
 type RebalanceConf struct {
 	DestRetryTimeStr string        `json:"dest_retry_time"`
 	DestRetryTime    time.Duration `json:"-"` //
 	Enabled          bool          `json:"enabled"`
+	BandwidthStr     string        `json:"bandwidth"`     // max rebalance transmit rate per target, e.g. "100MB" (bytes/s); "" or 0 - unlimited
+	Bandwidth        int64         `json:"-"`             //
+	DiskUtilMax      int64         `json:"disk_util_max"` // pause reading while the mountpath's disk utilization (%) exceeds; 0 - no limit
 }
 
 type ReplicationConf struct {
@@ -406,8 +453,9 @@ type AuthConf struct {
 type KeepaliveTrackerConf struct {
 	IntervalStr string        `json:"interval"` // keepalives are sent(target)/checked(promary proxy) every interval
 	Interval    time.Duration `json:"-"`
-	Name        string        `json:"name"`   // "heartbeat", "average"
-	Factor      uint8         `json:"factor"` // only average
+	Name        string        `json:"name"`      // "heartbeat", "average", "phi"
+	Factor      uint8         `json:"factor"`    // only average
+	Threshold   float64       `json:"threshold"` // only phi: suspicion level at which the server is considered down
 }
 
 type KeepaliveConf struct {
@@ -415,6 +463,43 @@ type KeepaliveConf struct {
 	Target        KeepaliveTrackerConf `json:"target"` // how target tracks primary proxies keepalives
 	RetryFactor   uint8                `json:"retry_factor"`
 	TimeoutFactor uint8                `json:"timeout_factor"`
+	// non-responding nodes remain suspect (and in the Smap) for so long before
+	// the primary removes them; zero - remove right away
+	SuspectTimeStr string        `json:"suspect_time"`
+	SuspectTime    time.Duration `json:"-"`
+}
+
+// CloudConf configures the resilience of the Cloud client: retries of idempotent
+// operations (with jittered exponential backoff) and the circuit breaker
+type CloudConf struct {
+	MaxRetries         int           `json:"max_retries"`       // max number of retries of a failed idempotent operation
+	RetryBackoffStr    string        `json:"retry_backoff"`     // initial backoff (doubles with every retry)
+	RetryBackoff       time.Duration `json:"-"`                 //
+	RetryBackoffMaxStr string        `json:"retry_backoff_max"` // max backoff, including the one requested via Retry-After
+	RetryBackoffMax    time.Duration `json:"-"`                 //
+	BreakerThreshold   int           `json:"breaker_threshold"` // consecutive failures that open the circuit (0 - disabled)
+	BreakerTimeoutStr  string        `json:"breaker_timeout"`   // time the circuit stays open prior to probing the Cloud again
+	BreakerTimeout     time.Duration `json:"-"`                 //
+	NegCacheTTLStr     string        `json:"neg_cache_ttl"`     // time to remember that an object does not exist in the Cloud (0 - disabled)
+	NegCacheTTL        time.Duration `json:"-"`                 //
+	NegCacheMax        int           `json:"neg_cache_max"`     // max number of remembered non-existent objects
+}
+
+// ScrubConf configures the background data scrubber (see ActScrub)
+type ScrubConf struct {
+	Enabled     bool          `json:"enabled"`  // scrub continuously, pass after pass
+	Policy      string        `json:"policy"`   // what to do about the problems found: report, quarantine, or repair
+	IntervalStr string        `json:"interval"` // pause between passes
+	Interval    time.Duration `json:"-"`        //
+}
+
+// GCConf configures garbage collection of the workfiles and other intermediate
+// content left behind by crashed PUTs, aborted jobs, etc. (see ActGC)
+type GCConf struct {
+	IntervalStr string        `json:"interval"` // between periodic passes (the first pass runs at startup); zero - startup only
+	Interval    time.Duration `json:"-"`        //
+	MaxAgeStr   string        `json:"max_age"`  // intermediate content older than that is stale regardless of its origin; zero - no limit
+	MaxAge      time.Duration `json:"-"`        //
 }
 
 //==============================
@@ -442,8 +527,10 @@ func LoadConfig(clivars *ConfigCLI) (changed bool) {
 		os.Exit(1)
 	}
 	if err = validateConfig(config); err != nil {
+		glog.Errorf("Invalid config %q, err: %v", clivars.ConfFile, err)
 		os.Exit(1)
 	}
+	GCO.SetFileConfig(config) // prior to CLI overrides
 
 	// glog rotate
 	glog.MaxSize = config.Log.MaxSize
@@ -541,6 +628,15 @@ func validateConfig(config *Config) (err error) {
 	if config.Rebalance.DestRetryTime, err = time.ParseDuration(config.Rebalance.DestRetryTimeStr); err != nil {
 		return fmt.Errorf(badfmt, config.Rebalance.DestRetryTimeStr, err)
 	}
+	if config.Rebalance.Bandwidth, err = S2B(config.Rebalance.BandwidthStr); err != nil {
+		return fmt.Errorf(badfmt, config.Rebalance.BandwidthStr, err)
+	}
+	if config.Rebalance.Bandwidth < 0 || config.Rebalance.DiskUtilMax < 0 || config.Rebalance.DiskUtilMax > 100 {
+		return fmt.Errorf("invalid rebalance configuration %+v", config.Rebalance)
+	}
+	if config.Xaction.Bandwidth, err = S2B(config.Xaction.BandwidthStr); err != nil || config.Xaction.Bandwidth < 0 {
+		return fmt.Errorf("invalid Xaction configuration %+v", config.Xaction)
+	}
 
 	hwm, lwm, oos := lru.HighWM, lru.LowWM, lru.OOS
 	if hwm <= 0 || lwm <= 0 || oos <= 0 || hwm < lwm || oos < hwm || lwm > 100 || hwm > 100 || oos > 100 {
@@ -602,6 +698,26 @@ func validateConfig(config *Config) (err error) {
 	if !validKeepaliveType(keepalive.Target.Name) {
 		return fmt.Errorf("bad target keepalive tracker type %s", keepalive.Target.Name)
 	}
+	if err = validateKeepaliveThreshold(&keepalive.Proxy); err != nil {
+		return err
+	}
+	if err = validateKeepaliveThreshold(&keepalive.Target); err != nil {
+		return err
+	}
+	if keepalive.SuspectTimeStr != "" {
+		if keepalive.SuspectTime, err = time.ParseDuration(keepalive.SuspectTimeStr); err != nil {
+			return fmt.Errorf("bad keepalive suspect_time format %s, err %v", keepalive.SuspectTimeStr, err)
+		}
+	}
+	if err = validateCloudConf(&config.Cloud); err != nil {
+		return err
+	}
+	if err = validateScrubConf(&config.Scrub); err != nil {
+		return err
+	}
+	if err = validateGCConf(&config.GC); err != nil {
+		return err
+	}
 
 	// NETWORK
 
@@ -690,6 +806,82 @@ func TestingEnv() bool {
 	return GCO.Get().TestFSP.Count > 0
 }
 
+// NOTE: the "cloud" section is optional - missing values are set to defaults
+func validateCloudConf(cloud *CloudConf) (err error) {
+	const badfmt = "bad cloud %s format %q, err: %v"
+	if cloud.RetryBackoffStr == "" {
+		cloud.RetryBackoffStr = "200ms"
+	}
+	if cloud.RetryBackoffMaxStr == "" {
+		cloud.RetryBackoffMaxStr = "10s"
+	}
+	if cloud.BreakerTimeoutStr == "" {
+		cloud.BreakerTimeoutStr = "30s"
+	}
+	if cloud.NegCacheTTLStr == "" {
+		cloud.NegCacheTTLStr = "30s"
+	}
+	if cloud.NegCacheMax == 0 {
+		cloud.NegCacheMax = 64 * 1024
+	}
+	if cloud.RetryBackoff, err = time.ParseDuration(cloud.RetryBackoffStr); err != nil {
+		return fmt.Errorf(badfmt, "retry_backoff", cloud.RetryBackoffStr, err)
+	}
+	if cloud.RetryBackoffMax, err = time.ParseDuration(cloud.RetryBackoffMaxStr); err != nil {
+		return fmt.Errorf(badfmt, "retry_backoff_max", cloud.RetryBackoffMaxStr, err)
+	}
+	if cloud.BreakerTimeout, err = time.ParseDuration(cloud.BreakerTimeoutStr); err != nil {
+		return fmt.Errorf(badfmt, "breaker_timeout", cloud.BreakerTimeoutStr, err)
+	}
+	if cloud.NegCacheTTL, err = time.ParseDuration(cloud.NegCacheTTLStr); err != nil {
+		return fmt.Errorf(badfmt, "neg_cache_ttl", cloud.NegCacheTTLStr, err)
+	}
+	if cloud.RetryBackoffMax < cloud.RetryBackoff {
+		return fmt.Errorf("invalid cloud retry_backoff %v > retry_backoff_max %v", cloud.RetryBackoff, cloud.RetryBackoffMax)
+	}
+	if cloud.MaxRetries < 0 || cloud.BreakerThreshold < 0 || cloud.RetryBackoff <= 0 ||
+		cloud.NegCacheTTL < 0 || cloud.NegCacheMax < 0 {
+		return fmt.Errorf("invalid cloud configuration %+v", cloud)
+	}
+	return nil
+}
+
+func validateScrubConf(scrub *ScrubConf) (err error) {
+	if scrub.Policy == "" {
+		scrub.Policy = ScrubReport
+	}
+	if scrub.IntervalStr == "" {
+		scrub.IntervalStr = "1h"
+	}
+	if !validScrubPolicy(scrub.Policy) {
+		return fmt.Errorf("invalid scrub policy %q (expecting: %s|%s|%s)", scrub.Policy, ScrubReport, ScrubQuarantine, ScrubRepair)
+	}
+	if scrub.Interval, err = time.ParseDuration(scrub.IntervalStr); err != nil {
+		return fmt.Errorf("bad scrub interval format %s, err %v", scrub.IntervalStr, err)
+	}
+	return nil
+}
+
+func validScrubPolicy(policy string) bool {
+	return policy == ScrubReport || policy == ScrubQuarantine || policy == ScrubRepair
+}
+
+func validateGCConf(gc *GCConf) (err error) {
+	if gc.IntervalStr == "" {
+		gc.IntervalStr = "1h"
+	}
+	if gc.MaxAgeStr == "" {
+		gc.MaxAgeStr = "24h"
+	}
+	if gc.Interval, err = time.ParseDuration(gc.IntervalStr); err != nil || gc.Interval < 0 {
+		return fmt.Errorf("bad gc interval %s, err %v", gc.IntervalStr, err)
+	}
+	if gc.MaxAge, err = time.ParseDuration(gc.MaxAgeStr); err != nil || gc.MaxAge < 0 {
+		return fmt.Errorf("bad gc max_age %s, err %v", gc.MaxAgeStr, err)
+	}
+	return nil
+}
+
 // ipv4ListsOverlap checks if two comma-separated ipv4 address lists
 // contain at least one common ipv4 address
 func ipv4ListsOverlap(alist, blist string) (overlap bool, addr string) {
@@ -711,7 +903,14 @@ func ipv4ListsOverlap(alist, blist string) (overlap bool, addr string) {
 
 // validKeepaliveType returns true if the keepalive type is supported.
 func validKeepaliveType(t string) bool {
-	return t == KeepaliveHeartbeatType || t == KeepaliveAverageType
+	return t == KeepaliveHeartbeatType || t == KeepaliveAverageType || t == KeepalivePhiType
+}
+
+func validateKeepaliveThreshold(c *KeepaliveTrackerConf) error {
+	if c.Name == KeepalivePhiType && c.Threshold <= 0 {
+		return fmt.Errorf("invalid %s keepalive tracker threshold %v (expecting positive value)", c.Name, c.Threshold)
+	}
+	return nil
 }
 
 //
@@ -831,6 +1030,12 @@ func setConfig(config *Config, name, value string) (errstr string) {
 		} else {
 			config.Xaction.DiskUtilHighWM = v
 		}
+	case "xaction.bandwidth":
+		if v, err := S2B(value); err != nil || v < 0 {
+			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
+		} else {
+			config.Xaction.Bandwidth, config.Xaction.BandwidthStr = v, value
+		}
 	case "dest_retry_time", "rebalance.dest_retry_time":
 		if v, err := time.ParseDuration(value); err != nil {
 			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
@@ -843,6 +1048,18 @@ func setConfig(config *Config, name, value string) (errstr string) {
 		} else {
 			config.Rebalance.Enabled = v
 		}
+	case "rebalance.bandwidth":
+		if v, err := S2B(value); err != nil || v < 0 {
+			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
+		} else {
+			config.Rebalance.Bandwidth, config.Rebalance.BandwidthStr = v, value
+		}
+	case "rebalance.disk_util_max":
+		if v, err := atoi(value); err != nil || v < 0 || v > 100 {
+			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
+		} else {
+			config.Rebalance.DiskUtilMax = v
+		}
 	case "validate_checksum_cold_get", "cksum.validate_cold_get":
 		if v, err := strconv.ParseBool(value); err != nil {
 			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
@@ -880,6 +1097,48 @@ func setConfig(config *Config, name, value string) (errstr string) {
 		} else {
 			config.Ver.Versioning = value
 		}
+	case "cloud.max_retries":
+		if v, err := strconv.Atoi(value); err != nil || v < 0 {
+			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
+		} else {
+			config.Cloud.MaxRetries = v
+		}
+	case "cloud.retry_backoff":
+		if v, err := time.ParseDuration(value); err != nil || v <= 0 {
+			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
+		} else {
+			config.Cloud.RetryBackoff, config.Cloud.RetryBackoffStr = v, value
+		}
+	case "cloud.retry_backoff_max":
+		if v, err := time.ParseDuration(value); err != nil || v <= 0 {
+			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
+		} else {
+			config.Cloud.RetryBackoffMax, config.Cloud.RetryBackoffMaxStr = v, value
+		}
+	case "cloud.breaker_threshold":
+		if v, err := strconv.Atoi(value); err != nil || v < 0 {
+			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
+		} else {
+			config.Cloud.BreakerThreshold = v
+		}
+	case "cloud.breaker_timeout":
+		if v, err := time.ParseDuration(value); err != nil {
+			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
+		} else {
+			config.Cloud.BreakerTimeout, config.Cloud.BreakerTimeoutStr = v, value
+		}
+	case "cloud.neg_cache_ttl":
+		if v, err := time.ParseDuration(value); err != nil || v < 0 {
+			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
+		} else {
+			config.Cloud.NegCacheTTL, config.Cloud.NegCacheTTLStr = v, value
+		}
+	case "cloud.neg_cache_max":
+		if v, err := strconv.Atoi(value); err != nil || v <= 0 {
+			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
+		} else {
+			config.Cloud.NegCacheMax = v
+		}
 	case "fshc_enabled", "fshc.enabled":
 		if v, err := strconv.ParseBool(value); err != nil {
 			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
@@ -906,6 +1165,36 @@ func setConfig(config *Config, name, value string) (errstr string) {
 		} else {
 			config.Mirror.UtilThresh = v
 		}
+	case "scrub.enabled":
+		if v, err := strconv.ParseBool(value); err != nil {
+			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
+		} else {
+			config.Scrub.Enabled = v
+		}
+	case "scrub.policy":
+		if !validScrubPolicy(value) {
+			errstr = fmt.Sprintf("%s: invalid %s=%s", ActSetConfig, name, value)
+		} else {
+			config.Scrub.Policy = value
+		}
+	case "scrub.interval":
+		if v, err := time.ParseDuration(value); err != nil {
+			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
+		} else {
+			config.Scrub.Interval, config.Scrub.IntervalStr = v, value
+		}
+	case "gc.interval":
+		if v, err := time.ParseDuration(value); err != nil || v < 0 {
+			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
+		} else {
+			config.GC.Interval, config.GC.IntervalStr = v, value
+		}
+	case "gc.max_age":
+		if v, err := time.ParseDuration(value); err != nil || v < 0 {
+			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
+		} else {
+			config.GC.MaxAge, config.GC.MaxAgeStr = v, value
+		}
 	case "keepalivetracker.proxy.interval":
 		if v, err := time.ParseDuration(value); err != nil {
 			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
@@ -930,19 +1219,56 @@ func setConfig(config *Config, name, value string) (errstr string) {
 		} else {
 			config.KeepaliveTracker.Target.Factor = uint8(v)
 		}
+	case "keepalivetracker.proxy.threshold":
+		if v, err := strconv.ParseFloat(value, 64); err != nil {
+			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
+		} else if v <= 0 {
+			errstr = fmt.Sprintf("%s: invalid %s=%s", ActSetConfig, name, value)
+		} else {
+			config.KeepaliveTracker.Proxy.Threshold = v
+		}
+	case "keepalivetracker.target.threshold":
+		if v, err := strconv.ParseFloat(value, 64); err != nil {
+			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
+		} else if v <= 0 {
+			errstr = fmt.Sprintf("%s: invalid %s=%s", ActSetConfig, name, value)
+		} else {
+			config.KeepaliveTracker.Target.Threshold = v
+		}
+	case "keepalivetracker.suspect_time":
+		if v, err := time.ParseDuration(value); err != nil {
+			errstr = fmt.Sprintf(fmtFailedParse, name, value, err)
+		} else {
+			config.KeepaliveTracker.SuspectTime, config.KeepaliveTracker.SuspectTimeStr = v, value
+		}
 	default:
 		errstr = fmt.Sprintf("%s: '%s' is readonly or invalid", ActSetConfig, name) // FIXME: remove "or" (#235)
 	}
-	if errstr == "" {
-		lwm, hwm := config.LRU.LowWM, config.LRU.HighWM
-		if hwm <= 0 || lwm <= 0 || hwm < lwm || lwm > 100 || hwm > 100 {
-			errstr = fmt.Sprintf("%s: invalid LRU watermarks hwm=%d, lwm=%d", ActSetConfig, hwm, lwm)
-		}
+	return
+}
 
-		lwm, hwm = config.Xaction.DiskUtilLowWM, config.Xaction.DiskUtilHighWM
-		if hwm <= 0 || lwm <= 0 || hwm < lwm || lwm > 100 || hwm > 100 {
-			errstr = fmt.Sprintf("%s: invalid Xaction disk util watermarks hwm=%d, lwm=%d", ActSetConfig, hwm, lwm)
-		}
+// validateWatermarks is called once all the values are set - so that, e.g., both
+// watermarks could be moved up at once
+func validateWatermarks(config *Config) (errstr string) {
+	lwm, hwm := config.LRU.LowWM, config.LRU.HighWM
+	if hwm <= 0 || lwm <= 0 || hwm < lwm || lwm > 100 || hwm > 100 {
+		errstr = fmt.Sprintf("%s: invalid LRU watermarks hwm=%d, lwm=%d", ActSetConfig, hwm, lwm)
+	}
+
+	lwm, hwm = config.Xaction.DiskUtilLowWM, config.Xaction.DiskUtilHighWM
+	if hwm <= 0 || lwm <= 0 || hwm < lwm || lwm > 100 || hwm > 100 {
+		errstr = fmt.Sprintf("%s: invalid Xaction disk util watermarks hwm=%d, lwm=%d", ActSetConfig, hwm, lwm)
+	}
+	return
+}
+
+// validateSettable validates the values that depend on each other, once all of them are set
+func validateSettable(config *Config) (errstr string) {
+	if errstr = validateWatermarks(config); errstr != "" {
+		return
+	}
+	if err := validateCloudConf(&config.Cloud); err != nil {
+		errstr = fmt.Sprintf("%s: %v", ActSetConfig, err)
 	}
 	return
 }
@@ -973,11 +1299,15 @@ func SetConfigMany(nvmap SimpleKVs) (errstr string) {
 
 		glog.Infof("%s: %s=%s", ActSetConfig, name, value)
 	}
+	if errstr = validateSettable(config); errstr != "" {
+		GCO.DiscardUpdate()
+		return
+	}
 	GCO.CommitUpdate(config)
 
 	if persist {
 		config := GCO.Get()
-		if err := LocalSave(GCO.GetConfigFile(), config); err != nil {
+		if err := SaveConfigFile(GCO.GetConfigFile(), config); err != nil {
 			glog.Errorf("%s: failed to write, err: %v", ActSetConfig, err)
 		} else {
 			glog.Infof("%s: stored", ActSetConfig)
@@ -985,3 +1315,231 @@ func SetConfigMany(nvmap SimpleKVs) (errstr string) {
 	}
 	return
 }
+
+// ValidateConfigMany checks the named values against a copy of the current config -
+// without applying them (log level and vmodule are only parsed, if at all)
+func ValidateConfigMany(nvmap SimpleKVs) (errstr string) {
+	config := &Config{}
+	CopyStruct(config, GCO.Get())
+	for name, value := range nvmap {
+		switch name {
+		case ActPersist, "vmodule":
+		case "log_level", "log.level":
+			if _, err := strconv.Atoi(value); err != nil {
+				return fmt.Sprintf("%s: invalid %s=%s", ActSetConfig, name, value)
+			}
+		default:
+			if errstr = setConfig(config, name, value); errstr != "" {
+				return
+			}
+		}
+	}
+	return validateSettable(config)
+}
+
+// short names of the configuration variables (see setConfig) => their full (section.name) names
+var configAliases = map[string]string{
+	"log_level":                  "log.level",
+	"stats_time":                 "periodic.stats_time",
+	"iostat_time":                "periodic.iostat_time",
+	"send_file_time":             "timeout.send_file_time",
+	"default_timeout":            "timeout.default_timeout",
+	"default_long_timeout":       "timeout.default_long_timeout",
+	"proxy_ping":                 "timeout.proxy_ping",
+	"cplane_operation":           "timeout.cplane_operation",
+	"max_keepalive":              "timeout.max_keepalive",
+	"dont_evict_time":            "lru.dont_evict_time",
+	"capacity_upd_time":          "lru.capacity_upd_time",
+	"lowwm":                      "lru.lowwm",
+	"highwm":                     "lru.highwm",
+	"lru_enabled":                "lru.enabled",
+	"lru_local_buckets":          "lru.local_buckets",
+	"disk_util_low_wm":           "xaction.disk_util_low_wm",
+	"disk_util_high_wm":          "xaction.disk_util_high_wm",
+	"dest_retry_time":            "rebalance.dest_retry_time",
+	"rebalance_enabled":          "rebalance.enabled",
+	"validate_checksum_cold_get": "cksum.validate_cold_get",
+	"validate_checksum_warm_get": "cksum.validate_warm_get",
+	"enable_read_range_checksum": "cksum.enable_read_range",
+	"checksum":                   "cksum.type",
+	"validate_version_warm_get":  "version.validate_warm_get",
+	"versioning":                 "version.versioning",
+	"fshc_enabled":               "fshc.enabled",
+	"mirror_enabled":             "mirror.enabled",
+	"mirror_burst_buffer":        "mirror.burst_buffer",
+	"mirror_util_thresh":         "mirror.util_thresh",
+}
+
+// ConfigKey returns the full name of the configuration variable
+func ConfigKey(name string) string {
+	if full, ok := configAliases[name]; ok {
+		return full
+	}
+	return name
+}
+
+// ConfigValue returns the current value of the named configuration variable in the
+// form setConfig accepts: the name is the path to the value in the config JSON
+func ConfigValue(config *Config, name string) (string, error) {
+	name = ConfigKey(name)
+	if name == "vmodule" {
+		if f := flag.Lookup("vmodule"); f != nil {
+			return f.Value.String(), nil
+		}
+		return "", nil
+	}
+	v, err := configJSON(config)
+	if err != nil {
+		return "", err
+	}
+	for _, key := range strings.Split(name, ".") {
+		section, ok := v.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("%s: invalid config name %q", ActSetConfig, name)
+		}
+		if v, ok = section[key]; !ok {
+			return "", fmt.Errorf("%s: unknown config name %q", ActSetConfig, name)
+		}
+	}
+	if value, ok := configLeaf(v); ok {
+		return value, nil
+	}
+	return "", fmt.Errorf("%s: %q is not a value", ActSetConfig, name)
+}
+
+// configJSON returns the config as a tree of JSON sections and values
+func configJSON(config *Config) (v interface{}, err error) {
+	b, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	err = decoder.Decode(&v)
+	return
+}
+
+func configLeaf(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case string:
+		return val, true
+	case bool:
+		return strconv.FormatBool(val), true
+	case json.Number:
+		return val.String(), true
+	}
+	return "", false
+}
+
+// configValues flattens the config into full (section.name) names and their values
+func configValues(config *Config) (SimpleKVs, error) {
+	v, err := configJSON(config)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		kvs  = make(SimpleKVs)
+		walk func(prefix string, v interface{})
+	)
+	walk = func(prefix string, v interface{}) {
+		if value, ok := configLeaf(v); ok {
+			kvs[prefix] = value
+			return
+		}
+		if section, ok := v.(map[string]interface{}); ok {
+			for key, sub := range section {
+				if prefix != "" {
+					key = prefix + "." + key
+				}
+				walk(key, sub)
+			}
+		}
+	}
+	walk("", v)
+	return kvs, nil
+}
+
+// configSettable tells whether the named variable can be changed at runtime (see setConfig)
+func configSettable(config *Config, name string) bool {
+	if name == "log.level" {
+		return true // setConfig would apply it right away
+	}
+	value, err := ConfigValue(config, name)
+	if err != nil {
+		return false
+	}
+	scratch := &Config{}
+	CopyStruct(scratch, config)
+	return setConfig(scratch, name, value) == ""
+}
+
+// DiffConfigFile loads the configuration file anew, validates it as a whole, and returns
+// the values that differ from the ones in the file as it was last loaded (or stored),
+// sorted by name, along with the newly loaded config (see GCO.SetFileConfig). Values
+// the file does not change - including the ones overridden via command line or set
+// at runtime - are not reported. The values that setconfig cannot change are marked
+// as requiring restart and are compared with the file as loaded at startup instead -
+// so that they keep being reported until the restart.
+func DiffConfigFile() (changes []ConfigFileChange, config *Config, err error) {
+	var (
+		path = GCO.GetConfigFile()
+		cur  = GCO.Get()
+		prev = GCO.FileConfig()
+		boot = (*Config)(atomic.LoadPointer(&GCO.sc))
+	)
+	if path == "" {
+		return nil, nil, errors.New("configuration file is not set")
+	}
+	config = &Config{}
+	if err = LocalLoad(path, config); err != nil {
+		return nil, nil, fmt.Errorf("failed to load config %q, err: %v", path, err)
+	}
+	if err = validateConfig(config); err != nil {
+		return nil, nil, fmt.Errorf("invalid config %q, err: %v", path, err)
+	}
+	if prev == nil {
+		prev = cur
+	}
+	if boot == nil {
+		boot = prev
+	}
+	before, err := configValues(prev)
+	if err != nil {
+		return nil, nil, err
+	}
+	initial, err := configValues(boot)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := configValues(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	from, err := configValues(cur)
+	if err != nil {
+		return nil, nil, err
+	}
+	for name, value := range to {
+		prev, ok := before[name]
+		if ok && prev == value {
+			if initial[name] == value || configSettable(cur, name) {
+				continue
+			}
+		} else if initial[name] == value && !configSettable(cur, name) {
+			continue // back to the value in effect
+		}
+		changes = append(changes, ConfigFileChange{Name: name, From: from[name], To: value})
+	}
+	for name := range before {
+		if _, ok := to[name]; !ok {
+			changes = append(changes, ConfigFileChange{Name: name, From: from[name], Restart: true})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	for i := range changes {
+		if !changes[i].Restart {
+			changes[i].Restart = !configSettable(cur, changes[i].Name)
+		}
+	}
+	return
+}
@@ -0,0 +1,214 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package cmn
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestConfigValue(t *testing.T) {
+	// full names of the settable variables => sample values
+	values := map[string]string{
+		"xaction.bandwidth":                 "10MB",
+		"rebalance.bandwidth":               "1GB",
+		"rebalance.disk_util_max":           "70",
+		"cloud.max_retries":                 "5",
+		"cloud.retry_backoff":               "500ms",
+		"cloud.retry_backoff_max":           "30s",
+		"cloud.breaker_threshold":           "3",
+		"cloud.breaker_timeout":             "1m",
+		"cloud.neg_cache_ttl":               "10s",
+		"cloud.neg_cache_max":               "1024",
+		"keepalivetracker.proxy.interval":   "7s",
+		"keepalivetracker.proxy.factor":     "4",
+		"keepalivetracker.target.interval":  "9s",
+		"keepalivetracker.target.factor":    "5",
+		"keepalivetracker.proxy.threshold":  "8.5",
+		"keepalivetracker.target.threshold": "9",
+		"keepalivetracker.suspect_time":     "30s",
+		"log.level":                         "4",
+		"periodic.stats_time":               "11s",
+		"periodic.iostat_time":              "3s",
+		"timeout.send_file_time":            "6m",
+		"timeout.default_timeout":           "31s",
+		"timeout.default_long_timeout":      "29m",
+		"timeout.proxy_ping":                "120ms",
+		"timeout.cplane_operation":          "2s",
+		"timeout.max_keepalive":             "5s",
+		"lru.dont_evict_time":               "2h",
+		"lru.capacity_upd_time":             "11m",
+		"lru.lowwm":                         "61",
+		"lru.highwm":                        "91",
+		"lru.enabled":                       "true",
+		"lru.local_buckets":                 "true",
+		"xaction.disk_util_low_wm":          "21",
+		"xaction.disk_util_high_wm":         "81",
+		"rebalance.dest_retry_time":         "3m",
+		"rebalance.enabled":                 "true",
+		"cksum.validate_cold_get":           "true",
+		"cksum.validate_warm_get":           "true",
+		"cksum.enable_read_range":           "true",
+		"cksum.type":                        ChecksumNone,
+		"version.validate_warm_get":         "true",
+		"version.versioning":                VersionLocal,
+		"fshc.enabled":                      "true",
+		"mirror.enabled":                    "true",
+		"mirror.burst_buffer":               "256",
+		"mirror.util_thresh":                "15",
+		"scrub.enabled":                     "true",
+		"scrub.policy":                      ScrubRepair,
+		"scrub.interval":                    "30m",
+		"gc.interval":                       "2h",
+		"gc.max_age":                        "48h",
+	}
+	// every alias must resolve to one of the above
+	for alias, name := range configAliases {
+		if _, ok := values[name]; !ok {
+			t.Errorf("alias %q: no sample value for %q", alias, name)
+		}
+	}
+	for name, value := range values {
+		config := &Config{}
+		if name == "log.level" { // (sets glog's -v)
+			config.Log.Level = value
+		} else if errstr := setConfig(config, name, value); errstr != "" {
+			t.Errorf("%s=%s: %s", name, value, errstr)
+			continue
+		}
+		if v, err := ConfigValue(config, name); err != nil || v != value {
+			t.Errorf("%s: expecting %q, got %q (err: %v)", name, value, v, err)
+		}
+	}
+	if _, err := ConfigValue(&Config{}, "lru.no_such_name"); err == nil {
+		t.Error("expecting error for unknown name")
+	}
+	if _, err := ConfigValue(&Config{}, "lru"); err == nil {
+		t.Error("expecting error for config section")
+	}
+}
+
+func TestValidateSettable(t *testing.T) {
+	config := &Config{}
+	config.LRU.LowWM, config.LRU.HighWM = 75, 90
+	config.Xaction.DiskUtilLowWM, config.Xaction.DiskUtilHighWM = 60, 80
+	if err := validateCloudConf(&config.Cloud); err != nil {
+		t.Fatal(err)
+	}
+	if errstr := validateSettable(config); errstr != "" {
+		t.Fatal(errstr)
+	}
+	if errstr := setConfig(config, "cloud.retry_backoff", "20s"); errstr != "" {
+		t.Fatal(errstr)
+	}
+	if errstr := validateSettable(config); !strings.Contains(errstr, "retry_backoff_max") {
+		t.Fatalf("expecting invalid retry backoff, got %q", errstr)
+	}
+	if errstr := setConfig(config, "cloud.retry_backoff_max", "1m"); errstr != "" {
+		t.Fatal(errstr)
+	}
+	if errstr := validateSettable(config); errstr != "" {
+		t.Fatal(errstr)
+	}
+}
+
+func TestDiffConfigFile(t *testing.T) {
+	config := &Config{}
+	config.Periodic.StatsTimeStr, config.Periodic.IostatTimeStr, config.Periodic.RetrySyncTimeStr = "10s", "2s", "2s"
+	config.LRU.DontEvictTimeStr, config.LRU.CapacityUpdTimeStr = "2h", "10m"
+	config.LRU.LowWM, config.LRU.HighWM, config.LRU.OOS = 75, 90, 95
+	config.Rebalance.DestRetryTimeStr = "2m"
+	config.Xaction.DiskUtilLowWM, config.Xaction.DiskUtilHighWM = 60, 80
+	config.Cksum.Type, config.Ver.Versioning = ChecksumXXHash, VersionAll
+	timeout := &config.Timeout
+	timeout.DefaultStr, timeout.DefaultLongStr, timeout.MaxKeepaliveStr = "10s", "30m", "4s"
+	timeout.ProxyPingStr, timeout.CplaneOperationStr, timeout.SendFileStr, timeout.StartupStr = "100ms", "1s", "5m", "1m"
+	config.KeepaliveTracker.Proxy = KeepaliveTrackerConf{IntervalStr: "10s", Name: KeepaliveHeartbeatType, Factor: 3}
+	config.KeepaliveTracker.Target = config.KeepaliveTracker.Proxy
+	config.Net.L4.PortStr = "8080"
+	if err := validateConfig(config); err != nil {
+		t.Fatal(err)
+	}
+
+	// the file differs from the config in effect in one runtime-changeable value and one that is not
+	file := &Config{}
+	CopyStruct(file, config)
+	file.LRU.LowWM = 70
+	file.Net.L4.PortStr = "8081"
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ais.json")
+	writeConfig := func(c *Config) {
+		b, err := json.Marshal(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig(file)
+
+	prev, prevFile, prevBoot, prevPath := GCO.Get(), GCO.FileConfig(), atomic.LoadPointer(&GCO.sc), GCO.GetConfigFile()
+	defer func() {
+		GCO.BeginUpdate()
+		GCO.CommitUpdate(prev)
+		GCO.SetConfigFile(prevPath)
+		atomic.StorePointer(&GCO.fc, unsafe.Pointer(prevFile))
+		atomic.StorePointer(&GCO.sc, prevBoot)
+	}()
+	// the config in effect differs from the file as loaded at startup (e.g., command-line
+	// override and setconfig) - the values the file does not change are not reported
+	atomic.StorePointer(&GCO.sc, nil)
+	GCO.SetFileConfig(config)
+	live := &Config{}
+	CopyStruct(live, config)
+	live.Periodic.StatsTime, live.Periodic.StatsTimeStr = time.Minute, "1m"
+	live.LRU.HighWM = 85
+	GCO.BeginUpdate()
+	GCO.CommitUpdate(live)
+	GCO.SetConfigFile(path)
+
+	changes, loaded, err := DiffConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ConfigFileChange{
+		{Name: "lru.lowwm", From: "75", To: "70"},
+		{Name: "net.l4.port", From: "8080", To: "8081", Restart: true},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("expecting %+v, got %+v", expected, changes)
+	}
+
+	// once reloaded, the same file has no changes - except for the ones that require restart
+	GCO.SetFileConfig(loaded)
+	if changes, _, err = DiffConfigFile(); err != nil || !reflect.DeepEqual(changes, expected[1:]) {
+		t.Fatalf("expecting %+v, got %+v, err: %v", expected[1:], changes, err)
+	}
+	// (and the ones reverted before restart are not)
+	file.Net.L4.Port, file.Net.L4.PortStr = config.Net.L4.Port, config.Net.L4.PortStr
+	writeConfig(file)
+	if changes, _, err = DiffConfigFile(); err != nil || len(changes) != 0 {
+		t.Fatalf("expecting no changes, got %+v, err: %v", changes, err)
+	}
+
+	// invalid file is rejected as a whole
+	file.LRU.LowWM = 99
+	writeConfig(file)
+	if _, _, err := DiffConfigFile(); err == nil {
+		t.Fatal("expecting validation error")
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package cmn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	// Monday, June 3, 2019, 02:30
+	mon := time.Date(2019, time.June, 3, 2, 30, 0, 0, time.Local)
+	tcs := []struct {
+		expr  string
+		t     time.Time
+		match bool
+	}{
+		{"* * * * *", mon, true},
+		{"30 2 * * *", mon, true},
+		{"@daily", mon, false},
+		{"@daily", mon.Add(-150 * time.Minute), true},
+		{"*/15 1-3 * * *", mon, true},
+		{"*/20 * * * *", mon, false},
+		{"0,30 2 * * 1-5", mon, true},
+		{"30 2 * * 0,6", mon, false},
+		{"30 2 * * 7", mon.AddDate(0, 0, 6), true},         // Sunday
+		{"30 2 1 * 1", mon, true},                          // day of week matches
+		{"30 2 3 * 0", mon, true},                          // day of month matches
+		{"30 2 * 7 *", mon, false},                         // July
+		{"30 2 1-31/2 6 *", mon, true},                     // odd days
+		{"30 2 2-30/2 6 *", mon.AddDate(0, 0, 1), true},    // even days
+		{"30 2 2-30/2 6 *", mon.AddDate(0, 0, 2), false},   // ditto
+		{"5/10 2 * * *", mon.Add(5 * time.Minute), true},   // 5, 15, ...
+		{"5/10 2 * * *", mon.Add(-4 * time.Minute), false}, // 26
+	}
+	for _, tc := range tcs {
+		c, err := ParseCron(tc.expr)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.expr, err)
+		}
+		if c.Match(tc.t) != tc.match {
+			t.Errorf("%q at %s: expecting match=%t", tc.expr, tc.t.Format(time.RFC1123), tc.match)
+		}
+	}
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *",
+		"* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@often"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("%q: expecting error", expr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	c, err := ParseCron("0 3 * * 6") // Saturdays at 3am
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2019, time.June, 3, 2, 30, 0, 0, time.Local)
+	if next, expected := c.Next(from), time.Date(2019, time.June, 8, 3, 0, 0, 0, time.Local); !next.Equal(expected) {
+		t.Errorf("expecting %s, got %s", expected, next)
+	}
+	if c, _ = ParseCron("0 0 30 2 *"); !c.Next(from).IsZero() {
+		t.Errorf("expecting no next run on February 30")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"sync/atomic"
 	"time"
+	"unsafe"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 )
@@ -25,16 +26,49 @@ type (
 		EndTime(e ...time.Time) time.Time
 		String() string
 		Abort()
+		AbortWith(reason string)
 		ChanAbort() <-chan struct{}
 		Finished() bool
+		// progress
+		ObjectsAdd(cnt int64) int64
+		BytesAdd(size int64) int64
+		TotalAdd(cnt int64) int64
+		AddErr(err error)
+		Snapshot() XactStats
 	}
 	XactBase struct {
-		id     int64
-		sutime int64
-		eutime int64
-		kind   string
-		bucket string
-		abrt   chan struct{}
+		id      int64
+		sutime  int64
+		eutime  int64
+		kind    string
+		bucket  string
+		abrt    chan struct{}
+		aborted int32
+		reason  unsafe.Pointer // *string: why aborted
+		// progress
+		objects int64
+		bytes   int64
+		total   int64 // objects to process, if known - to estimate ETA
+		errcnt  int64
+		lasterr unsafe.Pointer // *string
+	}
+	// XactStats is a point-in-time snapshot of an xaction (see GET /v1/xactions)
+	XactStats struct {
+		ID        int64         `json:"id"`
+		Kind      string        `json:"kind"`
+		Bucket    string        `json:"bucket"`
+		StartTime time.Time     `json:"start_time"`
+		EndTime   time.Time     `json:"end_time"`
+		Status    string        `json:"status"`  // XactionStatus* enum
+		Objects   int64         `json:"objects"` // processed so far
+		Bytes     int64         `json:"bytes"`
+		Total     int64         `json:"total,omitempty"` // objects to process, if known
+		ETA       time.Duration `json:"eta,omitempty"`
+		ErrCnt    int64         `json:"err_cnt"`
+		LastErr   string        `json:"last_err,omitempty"`
+		Reason    string        `json:"abort_reason,omitempty"`
+		Scrub     *ScrubStats   `json:"scrub,omitempty"`  // scrubber only
+		Resync    *ResyncReport `json:"resync,omitempty"` // cloud bucket resync only
 	}
 	//
 	// xaction that self-terminates after staying idle for a while
@@ -130,12 +164,68 @@ func (xact *XactBase) EndTime(e ...time.Time) time.Time {
 	return etime
 }
 
+// Abort is idempotent: only the first call takes effect
 func (xact *XactBase) Abort() {
+	if !atomic.CompareAndSwapInt32(&xact.aborted, 0, 1) {
+		return
+	}
 	atomic.StoreInt64(&xact.eutime, time.Now().UnixNano())
 	close(xact.abrt)
 	glog.Infof("ABORT: " + xact.String())
 }
 
+// AbortWith records the reason (the first one wins) and aborts
+func (xact *XactBase) AbortWith(reason string) {
+	atomic.CompareAndSwapPointer(&xact.reason, nil, unsafe.Pointer(&reason))
+	xact.Abort()
+}
+
+func (xact *XactBase) ObjectsAdd(cnt int64) int64 { return atomic.AddInt64(&xact.objects, cnt) }
+func (xact *XactBase) BytesAdd(size int64) int64  { return atomic.AddInt64(&xact.bytes, size) }
+func (xact *XactBase) SetTotal(cnt int64)         { atomic.StoreInt64(&xact.total, cnt) }
+func (xact *XactBase) TotalAdd(cnt int64) int64   { return atomic.AddInt64(&xact.total, cnt) }
+
+func (xact *XactBase) AddErr(err error) {
+	if err == nil {
+		return
+	}
+	s := err.Error()
+	atomic.AddInt64(&xact.errcnt, 1)
+	atomic.StorePointer(&xact.lasterr, unsafe.Pointer(&s))
+}
+
+func (xact *XactBase) Snapshot() XactStats {
+	stats := XactStats{
+		ID:        xact.ID(),
+		Kind:      xact.Kind(),
+		Bucket:    xact.Bucket(),
+		StartTime: xact.StartTime(),
+		EndTime:   xact.EndTime(),
+		Status:    XactionStatusInProgress,
+		Objects:   atomic.LoadInt64(&xact.objects),
+		Bytes:     atomic.LoadInt64(&xact.bytes),
+		Total:     atomic.LoadInt64(&xact.total),
+		ErrCnt:    atomic.LoadInt64(&xact.errcnt),
+	}
+	if p := atomic.LoadPointer(&xact.lasterr); p != nil {
+		stats.LastErr = *(*string)(p)
+	}
+	switch {
+	case xact.Aborted():
+		stats.Status = XactionStatusAborted
+		if p := atomic.LoadPointer(&xact.reason); p != nil {
+			stats.Reason = *(*string)(p)
+		}
+	case xact.Finished():
+		stats.Status = XactionStatusCompleted
+	case stats.Total > stats.Objects && stats.Objects > 0:
+		// linear extrapolation of the rate so far
+		elapsed := time.Since(stats.StartTime)
+		stats.ETA = time.Duration(float64(elapsed) * float64(stats.Total-stats.Objects) / float64(stats.Objects))
+	}
+	return stats
+}
+
 //
 // XactDemandBase - implements XactDemand interface
 //
@@ -14,6 +14,7 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/governor"
 	"github.com/NVIDIA/aistore/stats"
 )
 
@@ -399,6 +400,8 @@ func (d *Downloader) Download(body *cmn.DlBody) (resp string, err error, statusC
 	r := <-t.responseCh
 	if r.err != nil {
 		d.DecPending()
+	} else {
+		d.TotalAdd(1)
 	}
 	return r.resp, r.err, r.statusCode
 }
@@ -576,12 +579,19 @@ Loop:
 		// await abort or completion
 		if err := <-t.waitForFinish(); err != nil {
 			glog.Errorf("error occurred when downloading %s: %v", t, err)
+			j.parent.AddErr(err)
+		} else {
+			t.Lock()
+			j.parent.ObjectsAdd(1)
+			j.parent.BytesAdd(t.currentSize)
+			t.Unlock()
 		}
 		j.Lock()
 		j.task = nil
 		j.Unlock()
 		j.q.delete(t.request)
 		j.parent.DecPending()
+		governor.Gov.Throttle(j.mpath, governor.PrioNormal)
 
 	}
 
@@ -5,6 +5,7 @@
 package dsort
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net"
@@ -109,6 +110,9 @@ func (n *nameLockerMock) TryLock(uname string, exclusive bool) bool { return tru
 func (n *nameLockerMock) Lock(uname string, exclusive bool)         {}
 func (n *nameLockerMock) DowngradeLock(uname string)                {}
 func (n *nameLockerMock) Unlock(uname string, exclusive bool)       {}
+func (n *nameLockerMock) LockCtx(ctx context.Context, uname string, exclusive bool) error {
+	return nil
+}
 
 type extractCreatorMock struct {
 	useCompression bool
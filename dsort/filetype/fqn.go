@@ -20,14 +20,31 @@ const (
 
 var (
 	_ fs.ContentResolver = &DSortFile{}
+	_ fs.ContentResolver = &DSortWorkfile{}
+	_ fs.ContentInUse    = &DSortFile{}
+	_ fs.ContentInUse    = &DSortWorkfile{}
 )
 
-type DSortFile struct{}
+type (
+	DSortFile struct {
+		Active func() bool // true: dsort is running - its content is not to be garbage collected
+	}
+	// same naming as regular workfiles: tells the workfiles of a previous run by pid
+	DSortWorkfile struct {
+		fs.WorkfileContentResolver
+		Active func() bool
+	}
+)
 
 func (df *DSortFile) PermToEvict() bool                       { return false }
 func (df *DSortFile) PermToMove() bool                        { return false }
 func (df *DSortFile) PermToProcess() bool                     { return false }
+func (df *DSortFile) PermToGC() bool                          { return true }
 func (df *DSortFile) GenUniqueFQN(base, prefix string) string { return base }
 func (df *DSortFile) ParseUniqueFQN(base string) (orig string, old bool, ok bool) {
 	return base, false, true
 }
+func (df *DSortFile) InUse(string) bool { return df.Active != nil && df.Active() }
+
+func (dw *DSortWorkfile) PermToEvict() bool { return false }
+func (dw *DSortWorkfile) InUse(string) bool { return dw.Active != nil && dw.Active() }
@@ -42,8 +42,7 @@ var (
 	mem      *memsys.Mem2
 	once     sync.Once
 	initOnce = func() {
-		fs.CSM.RegisterFileType(filetype.DSortFileType, &filetype.DSortFile{})
-		fs.CSM.RegisterFileType(filetype.DSortWorkfileType, &filetype.DSortFile{})
+		regFileTypes() // (no-op if already registered)
 
 		mem = &memsys.Mem2{
 			Name:     "DSort.Mem2",
@@ -146,6 +145,17 @@ func RegisterNode(smap cluster.Sowner, snode *cluster.Snode, t cluster.Target, n
 	ctx.node = snode
 	ctx.t = t
 	ctx.nameLocker = nameLocker
+
+	// target: register upfront so that the content left behind by the previous
+	// run gets garbage collected (see fs.ContentResolver.PermToGC)
+	if t != nil {
+		regFileTypes()
+	}
+}
+
+func regFileTypes() {
+	fs.CSM.RegisterFileType(filetype.DSortFileType, &filetype.DSortFile{Active: Managers.active})
+	fs.CSM.RegisterFileType(filetype.DSortWorkfileType, &filetype.DSortWorkfile{Active: Managers.active})
 }
 
 // init initializes all necessary fields.
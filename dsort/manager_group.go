@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cmn"
@@ -86,6 +87,19 @@ func (mg *ManagerGroup) Get(managerUUID string, ap ...bool) (*Manager, bool) {
 	return manager, exists
 }
 
+// active returns true if any manager has not cleaned up yet: the content created
+// by dsort is not to be garbage collected while in use (see fs.ContentInUse)
+func (mg *ManagerGroup) active() bool {
+	mg.mtx.Lock()
+	defer mg.mtx.Unlock()
+	for _, manager := range mg.managers {
+		if atomic.LoadInt32(&manager.state.cleaned) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // persist removes manager from manager group (memory) and moves all information
 // about it to persistent storage (file). This operation allows for later access
 // of old managers (including managers' metrics).
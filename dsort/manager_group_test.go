@@ -72,6 +72,18 @@ var _ = Describe("ManagerGroup", func() {
 		})
 	})
 
+	Context("active", func() {
+		It("should be active until the manager cleans up", func() {
+			Expect(mgrp.active()).To(BeFalse())
+			m, err := mgrp.Add("uuid")
+			m.unlock()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(mgrp.active()).To(BeTrue())
+			m.state.cleaned = 1
+			Expect(mgrp.active()).To(BeFalse())
+		})
+	})
+
 	Context("persist", func() {
 		ctx.smap = newTestSmap("target")
 		ctx.node = ctx.smap.Get().Tmap["target"]
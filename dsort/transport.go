@@ -10,6 +10,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/NVIDIA/aistore/governor"
 	"github.com/NVIDIA/aistore/transport"
 )
 
@@ -43,6 +44,7 @@ func (sp *StreamPool) Stop() {
 func NewStream(url string) *transport.Stream {
 	extra := &transport.Extra{
 		IdleTimeout: time.Second * 30,
+		Limiter:     governor.Gov.NetLimiter(governor.PrioNormal),
 	}
 	client := transport.NewDefaultClient()
 	return transport.NewStream(client, url, extra)
@@ -5,8 +5,11 @@
 package ec
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"sync/atomic"
 	"time"
@@ -156,6 +159,9 @@ type (
 		SliceID  int    `json:"sliceid,omitempty"` // 0 for full replica, 1 to N for slices
 		Checksum string `json:"chk"`               // checksum of the original object
 		IsCopy   bool   `json:"copy"`              // object is replicated(true) or encoded(false)
+		// HRW list of targets the object is placed on: the main target first and
+		// then the ones that keep replicas or slices; updated by global rebalance
+		Targets []string `json:"targets,omitempty"`
 	}
 
 	// request - structure to request an object to be EC'ed or restored
@@ -252,11 +258,32 @@ func (m *Metadata) marshal() ([]byte, error) {
 	return jsoniter.Marshal(m)
 }
 
-//lint:ignore U1000 unused
 func (m *Metadata) unmarshal(b []byte) error {
 	return jsoniter.Unmarshal(b, m)
 }
 
+// LoadMetadata reads EC information from the metafile
+func LoadMetadata(fqn string) (*Metadata, error) {
+	b, err := ioutil.ReadFile(fqn)
+	if err != nil {
+		return nil, err
+	}
+	md := &Metadata{}
+	if err := md.unmarshal(b); err != nil {
+		return nil, fmt.Errorf("damaged metafile %q: %v", fqn, err)
+	}
+	return md, nil
+}
+
+// SaveMetadata stores EC information in the metafile
+func SaveMetadata(fqn string, md *Metadata) error {
+	b, err := md.marshal()
+	if err != nil {
+		return err
+	}
+	return cmn.SaveReader(fqn, bytes.NewReader(b), nil)
+}
+
 var (
 	mem2         = &memsys.Mem2{Name: "ec", MinPctFree: 10}
 	slicePadding = make([]byte, 64) // for padding EC slices
@@ -19,6 +19,7 @@ var (
 func (wf *SliceSpec) PermToMove() bool    { return true }
 func (wf *SliceSpec) PermToEvict() bool   { return false }
 func (wf *SliceSpec) PermToProcess() bool { return false }
+func (wf *SliceSpec) PermToGC() bool      { return false }
 
 func (wf *SliceSpec) GenUniqueFQN(base, prefix string) string { return base }
 func (wf *SliceSpec) ParseUniqueFQN(base string) (orig string, old bool, ok bool) {
@@ -28,6 +29,7 @@ func (wf *SliceSpec) ParseUniqueFQN(base string) (orig string, old bool, ok bool
 func (wf *MetaSpec) PermToMove() bool    { return true }
 func (wf *MetaSpec) PermToEvict() bool   { return false }
 func (wf *MetaSpec) PermToProcess() bool { return false }
+func (wf *MetaSpec) PermToGC() bool      { return false }
 
 func (wf *MetaSpec) GenUniqueFQN(base, prefix string) string { return base }
 func (wf *MetaSpec) ParseUniqueFQN(base string) (orig string, old bool, ok bool) {
@@ -87,6 +87,10 @@ func (c *getJogger) ec(req *Request) {
 			}
 			if err == nil {
 				c.parent.stats.updateObjTime(time.Since(req.putTime))
+				c.parent.ObjectsAdd(1)
+				c.parent.BytesAdd(req.LOM.Size)
+			} else {
+				c.parent.AddErr(err)
 			}
 			<-c.sema
 		}
@@ -102,6 +106,7 @@ func (c *getJogger) ec(req *Request) {
 		err := fmt.Errorf("invalid EC action for getJogger: %v", req.Action)
 		glog.Errorf("Error occurred during restoring object [%s/%s], fqn: %q, err: %v",
 			req.LOM.Bucket, req.LOM.Objname, req.LOM.FQN, err)
+		c.parent.AddErr(err)
 		if req.ErrCh != nil {
 			req.ErrCh <- err
 			close(req.ErrCh)
@@ -17,6 +17,7 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/governor"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/transport"
 	"github.com/klauspost/reedsolomon"
@@ -50,6 +51,7 @@ func (c *putJogger) run() {
 			req.tm = time.Now()
 			c.ec(req)
 			c.parent.DecPending()
+			governor.Gov.Throttle(c.mpath, governor.PrioHigh)
 		case <-c.stopCh:
 			c.slab.Free(c.buffer)
 			c.buffer = nil
@@ -87,6 +89,10 @@ func (c *putJogger) ec(req *Request) {
 	if err != nil {
 		glog.Errorf("Error occurred during %s object [%s/%s], fqn: %q, err: %v",
 			act, req.LOM.Bucket, req.LOM.Objname, req.LOM.FQN, err)
+		c.parent.AddErr(err)
+	} else {
+		c.parent.ObjectsAdd(1)
+		c.parent.BytesAdd(req.LOM.Size)
 	}
 
 	if req.ErrCh != nil {
@@ -129,11 +135,18 @@ func (c *putJogger) encode(req *Request) error {
 	if !req.IsCopy {
 		reqTargets += ecConf.DataSlices
 	}
-	targetCnt := len(c.parent.smap.Get().Tmap)
+	targetCnt := c.parent.smap.Get().CountPlacementTargets()
 	if targetCnt < reqTargets {
 		return fmt.Errorf("object %s/%s requires %d targets to encode, only %d found",
 			req.LOM.Bucket, req.LOM.Objname, reqTargets, targetCnt)
 	}
+	targets, errstr := cluster.HrwTargetList(req.LOM.Bucket, req.LOM.Objname, c.parent.smap.Get(), reqTargets)
+	if errstr != "" {
+		return errors.New(errstr)
+	}
+	for _, tgt := range targets {
+		meta.Targets = append(meta.Targets, tgt.DaemonID)
+	}
 
 	metabuf, err := meta.marshal()
 	if err != nil {
@@ -19,6 +19,7 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/governor"
 	"github.com/NVIDIA/aistore/transport"
 )
 
@@ -95,7 +96,8 @@ func NewXact(netReq, netResp string, t cluster.Target, bmd cluster.Bowner, smap
 	}
 
 	client := transport.NewDefaultClient()
-	extraReq := transport.Extra{Callback: cbReq}
+	limiter := governor.Gov.NetLimiter(governor.PrioHigh) // (shared with all xactions of the class)
+	extraReq := transport.Extra{Callback: cbReq, Limiter: limiter}
 
 	reqSbArgs := transport.SBArgs{
 		Multiplier: transport.IntraBundleMultiplier,
@@ -108,6 +110,7 @@ func NewXact(netReq, netResp string, t cluster.Target, bmd cluster.Bowner, smap
 		Multiplier: transport.IntraBundleMultiplier,
 		Trname:     RespStreamName,
 		Network:    netResp,
+		Extra:      &transport.Extra{Limiter: limiter},
 	}
 
 	runner.reqBundle = transport.NewStreamBundle(smap, si, client, reqSbArgs)
@@ -628,6 +631,30 @@ func (r *XactEC) dataResponse(act intraReqType, fqn, bucket, objname, id string)
 	return r.sendByDaemonID([]string{id}, rHdr, reader, cb, false)
 }
 
+// Migrate sends a replica or slice (fqn) along with its metadata to the target
+// that is going to keep it from now on - the latter saves both (see Case #2
+// in DispatchResp). Used when the local target is being decommissioned.
+func (r *XactEC) Migrate(daemonID, fqn string, meta *Metadata, cb transport.SendCallback) error {
+	lom := &cluster.LOM{FQN: fqn, T: r.t}
+	if errstr := lom.Fill("", cluster.LomFstat|cluster.LomAtime|cluster.LomVersion|cluster.LomCksum); errstr != "" {
+		return errors.New(errstr)
+	}
+	if !lom.Exists() {
+		return fmt.Errorf("%s/%s: %s (slice #%d)", lom.Bucket, lom.Objname, cmn.DoesNotExist, meta.SliceID)
+	}
+	fh, err := cmn.NewFileHandle(fqn)
+	if err != nil {
+		return err
+	}
+	src := &dataSource{
+		reader:   fh,
+		size:     lom.Size,
+		metadata: meta,
+		isSlice:  meta.SliceID != 0,
+	}
+	return r.writeRemote([]string{daemonID}, lom, src, cb)
+}
+
 // save data from a target response to SGL. When exists is false it
 // just drains the response body and returns - because it does not contain
 // any data. On completion the function must call writer.wg.Done to notify
@@ -707,6 +734,7 @@ func (r *XactEC) Cleanup(req *Request) {
 
 func (r *XactEC) dispatchRequest(req *Request) {
 	r.IncPending()
+	r.TotalAdd(1)
 	switch req.Action {
 	case ActRestore:
 		jogger, ok := r.getJoggers[req.LOM.ParsedFQN.MpathInfo.Path]
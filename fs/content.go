@@ -32,8 +32,9 @@ import (
  */
 
 const (
-	ObjectType   = "obj"
-	WorkfileType = "work"
+	ObjectType     = "obj"
+	WorkfileType   = "work"
+	QuarantineType = "quarantine" // content moved aside by the scrubber
 )
 
 type (
@@ -45,6 +46,9 @@ type (
 		PermToEvict() bool
 		// When set to true, content can be checksumed, shown or processed in other ways.
 		PermToProcess() bool
+		// When set to true, content is intermediate (e.g., workfiles) and gets
+		// garbage collected once it is left behind (see Stale).
+		PermToGC() bool
 
 		// Generates unique base name for original one. This function may add
 		// additional information to the base name.
@@ -53,6 +57,12 @@ type (
 		// Parses generated unique fqn to the original one.
 		ParseUniqueFQN(base string) (orig string, old bool, ok bool)
 	}
+	// ContentInUse is optionally implemented by the resolvers of the content
+	// that is in use by a running job - e.g., dsort - for longer than gc.max_age:
+	// such content is never stale (see Stale)
+	ContentInUse interface {
+		InUse(fqn string) bool
+	}
 
 	ContentInfo struct {
 		Dir  string // Original directory
@@ -67,8 +77,9 @@ type (
 )
 
 var (
-	pid  int64 = 0xDEADBEEF   // pid of the current process
-	spid       = "0xDEADBEEF" // string version of the pid
+	pid     int64 = 0xDEADBEEF   // pid of the current process
+	spid          = "0xDEADBEEF" // string version of the pid
+	started       = time.Now()   // start time of the current process
 
 	CSM = &ContentSpecMgr{RegisteredContentTypes: make(map[string]ContentResolver, 8)}
 )
@@ -170,6 +181,23 @@ func (f *ContentSpecMgr) PermToEvict(fqn string) (ok, isOld bool) {
 	return spec.PermToEvict(), info.Old
 }
 
+// Stale returns true if fqn is intermediate content (see PermToGC) left behind
+// by a previous run - as per its pid or modification time - or sitting for
+// longer than maxAge (zero - no limit), unless the content is in use (see ContentInUse)
+func (f *ContentSpecMgr) Stale(fqn string, fi os.FileInfo, maxAge time.Duration) bool {
+	spec, info := f.FileSpec(fqn)
+	if spec == nil || !spec.PermToGC() {
+		return false
+	}
+	if u, ok := spec.(ContentInUse); ok && u.InUse(fqn) {
+		return false
+	}
+	if info.Old || fi.ModTime().Before(started) {
+		return true
+	}
+	return maxAge > 0 && time.Since(fi.ModTime()) > maxAge
+}
+
 func (f *ContentSpecMgr) PermToMove(fqn string) (ok bool) {
 	spec, _ := f.FileSpec(fqn)
 	if spec == nil {
@@ -191,13 +219,15 @@ func (f *ContentSpecMgr) PermToProcess(fqn string) (ok bool) {
 // FIXME: This should be probably placed somewhere else \/
 
 type (
-	ObjectContentResolver   struct{}
-	WorkfileContentResolver struct{}
+	ObjectContentResolver     struct{}
+	WorkfileContentResolver   struct{}
+	QuarantineContentResolver struct{}
 )
 
 func (wf *ObjectContentResolver) PermToMove() bool    { return true }
 func (wf *ObjectContentResolver) PermToEvict() bool   { return true }
 func (wf *ObjectContentResolver) PermToProcess() bool { return true }
+func (wf *ObjectContentResolver) PermToGC() bool      { return false }
 
 func (wf *ObjectContentResolver) GenUniqueFQN(base, prefix string) string {
 	return base
@@ -210,6 +240,7 @@ func (wf *ObjectContentResolver) ParseUniqueFQN(base string) (orig string, old b
 func (wf *WorkfileContentResolver) PermToMove() bool    { return false }
 func (wf *WorkfileContentResolver) PermToEvict() bool   { return true }
 func (wf *WorkfileContentResolver) PermToProcess() bool { return false }
+func (wf *WorkfileContentResolver) PermToGC() bool      { return true }
 
 func (wf *WorkfileContentResolver) GenUniqueFQN(base, prefix string) string {
 	// append prefix to mark what created the workfile
@@ -244,3 +275,26 @@ func (wf *WorkfileContentResolver) ParseUniqueFQN(base string) (orig string, old
 
 	return base[:tieIndex], filePID != pid, true
 }
+
+// quarantined content is kept as is: not moved, evicted, or processed
+
+func (qf *QuarantineContentResolver) PermToMove() bool    { return false }
+func (qf *QuarantineContentResolver) PermToEvict() bool   { return false }
+func (qf *QuarantineContentResolver) PermToProcess() bool { return false }
+func (qf *QuarantineContentResolver) PermToGC() bool      { return false }
+
+func (qf *QuarantineContentResolver) GenUniqueFQN(base, prefix string) string {
+	// append the time of quarantining, so that the same object could be quarantined more than once
+	return base + "." + strconv.FormatInt(time.Now().UnixNano(), 16)
+}
+
+func (qf *QuarantineContentResolver) ParseUniqueFQN(base string) (orig string, old bool, ok bool) {
+	tsIndex := strings.LastIndex(base, ".")
+	if tsIndex < 0 {
+		return "", false, false
+	}
+	if _, err := strconv.ParseInt(base[tsIndex+1:], 16, 64); err != nil {
+		return "", false, false
+	}
+	return base[:tsIndex], false, true
+}
@@ -0,0 +1,95 @@
+package fs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// workfiles of a running job
+type jobContentResolver struct {
+	WorkfileContentResolver
+	running bool
+}
+
+func (jc *jobContentResolver) InUse(string) bool { return jc.running }
+
+func TestStale(t *testing.T) {
+	mpath, err := ioutil.TempDir("", "content")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(mpath)
+
+	mfs := NewMountedFS()
+	mfs.DisableFsIDCheck()
+	if err := mfs.Add(mpath); err != nil {
+		t.Fatal(err)
+	}
+	prevMountpaths, prevCSM := Mountpaths, CSM
+	defer func() { Mountpaths, CSM = prevMountpaths, prevCSM }()
+	Mountpaths = mfs
+	CSM = &ContentSpecMgr{RegisteredContentTypes: make(map[string]ContentResolver, 2)}
+	CSM.RegisterFileType(ObjectType, &ObjectContentResolver{})
+	CSM.RegisterFileType(WorkfileType, &WorkfileContentResolver{})
+	job := &jobContentResolver{running: true}
+	CSM.RegisterFileType("job", job)
+
+	var (
+		available, _ = mfs.Get()
+		mpathInfo    = available[mpath]
+		parsedFQN    = ParsedFQN{MpathInfo: mpathInfo, IsLocal: true, Bucket: "bucket", Objname: "dir/obj"}
+		objFQN       = CSM.FQN(mpathInfo, ObjectType, true, "bucket", "dir/obj")
+		workFQN      = CSM.GenContentParsedFQN(parsedFQN, WorkfileType, "put")
+		orphanFQN    = CSM.FQN(mpathInfo, WorkfileType, true, "bucket", "dir/put.obj.abcdef.1") // pid 1
+		oldFQN       = CSM.GenContentParsedFQN(parsedFQN, WorkfileType, "cold")
+		jobFQN       = CSM.GenContentParsedFQN(parsedFQN, "job", "sort")
+		hourAgo      = time.Now().Add(-time.Hour)
+	)
+	for _, fqn := range []string{objFQN, workFQN, orphanFQN, oldFQN, jobFQN} {
+		if err := cmn.CreateDir(filepath.Dir(fqn)); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(fqn, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.Chtimes(objFQN, hourAgo, hourAgo)
+	os.Chtimes(oldFQN, hourAgo, hourAgo) // predates the process
+	os.Chtimes(jobFQN, hourAgo, hourAgo)
+
+	stale := func(fqn string, maxAge time.Duration) bool {
+		fi, err := os.Stat(fqn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return CSM.Stale(fqn, fi, maxAge)
+	}
+	if stale(objFQN, time.Minute) {
+		t.Errorf("object %s must never be stale", objFQN)
+	}
+	if stale(workFQN, time.Minute) {
+		t.Errorf("workfile %s of the current process must not be stale", workFQN)
+	}
+	if !stale(orphanFQN, 0) {
+		t.Errorf("workfile %s of another process must be stale", orphanFQN)
+	}
+	if !stale(oldFQN, 0) {
+		t.Errorf("workfile %s created before the process started must be stale", oldFQN)
+	}
+	if stale(jobFQN, time.Minute) {
+		t.Errorf("content %s of the running job must not be stale", jobFQN)
+	}
+	job.running = false
+	if !stale(jobFQN, time.Minute) {
+		t.Errorf("content %s of the finished job must be stale", jobFQN)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if !stale(workFQN, time.Millisecond) {
+		t.Errorf("workfile %s older than max age must be stale", workFQN)
+	}
+}
@@ -12,14 +12,14 @@ import (
 )
 
 const (
-	maxAttrSize = 1024
+	MaxAttrSize = 1024
 )
 
 // GetXattr gets xattr by name
 func GetXattr(fqn, attrname string) ([]byte, string) {
-	data := make([]byte, maxAttrSize)
+	data := make([]byte, MaxAttrSize)
 	read, err := syscall.Getxattr(fqn, attrname, data)
-	cmn.Assert(read < maxAttrSize)
+	cmn.Assert(read < MaxAttrSize)
 	if err != nil && err != syscall.ENODATA {
 		return nil, fmt.Sprintf("Failed to get xattr %s: %s, err [%v]", attrname, fqn, err)
 	}
@@ -31,7 +31,7 @@ func GetXattr(fqn, attrname string) ([]byte, string) {
 
 // SetXattr sets xattr name = value
 func SetXattr(fqn, attrname string, data []byte) (errstr string) {
-	cmn.Assert(len(data) < maxAttrSize)
+	cmn.Assert(len(data) < MaxAttrSize)
 	err := syscall.Setxattr(fqn, attrname, data, 0)
 	if err != nil {
 		errstr = fmt.Sprintf("Failed to set xattr %s: %s, err [%v]", attrname, fqn, err)
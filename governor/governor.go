@@ -0,0 +1,300 @@
+// Package governor allocates the target's disk and network budgets among the running xactions
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package governor
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/transport"
+)
+
+//
+// IO governor
+//
+// The governor allocates each target's disk and network budgets among the running
+// xactions by priority class, while always reserving headroom for user GET/PUT.
+//
+// Disk: for each mountpath, the governor maintains the share (0, 1] of the disk
+// throughput that xactions may use. The share is adjusted every iostat period
+// (config.Periodic.IostatTime) based on the disk utilization measured by the
+// ios.IostatRunner (see fs.MountpathInfo.GetIOstats): halved when the latter
+// exceeds config.Xaction.DiskUtilHighWM, and increased step by step while it
+// stays below config.Xaction.DiskUtilLowWM - the (100 - DiskUtilHighWM)% headroom
+// is thus left to the user workload. The share is given to the highest active
+// priority class in full; lower classes get exponentially less - share^(w/wclass),
+// where w is the weight of the highest active class. Xactions call Throttle once
+// per object, to pause for the time that corresponds to their class's allocation.
+//
+// Network: the aggregate transmit rate of all xactions is limited by
+// config.Xaction.Bandwidth, divided among the active classes by weight;
+// intra-cluster streams of the xactions share the limiters of their classes
+// (see NetLimiter).
+//
+
+// priority classes of xactions (see KindPrio)
+const (
+	PrioLow    = iota // LRU, rechecksum, prefetch, evict/delete, erasing copies, and the rest
+	PrioNormal        // mirroring, downloads, and dsort (the latter - network only)
+	PrioHigh          // global and local rebalance, erasure coding
+	NumPrio
+)
+
+const (
+	minDiskShare  = 1.0 / 64 // xactions never stop completely
+	diskShareStep = 1.0 / 8  // additive increase while the disks are not busy
+)
+
+var (
+	PrioNames   = [NumPrio]string{"low", "normal", "high"}
+	prioWeights = [NumPrio]float64{1, 2, 4}
+
+	// Gov is the target's (only) IO governor - a runner that updates the allocations
+	Gov = NewGovernor()
+)
+
+type (
+	Governor struct {
+		cmn.Named
+		stopCh  chan struct{}
+		mtx     sync.RWMutex
+		disk    map[string]*diskBudget // by mountpath
+		net     [NumPrio]*transport.Limiter
+		netWait [NumPrio]int64 // stats: throttled transmissions, by class
+		active  func() [NumPrio]int
+		nactive [NumPrio]int // running xactions, by class, as of the last update
+	}
+	diskBudget struct {
+		util  float32
+		share float64
+		delay [NumPrio]int64 // time.Duration
+		waits [NumPrio]int64 // stats: throttling pauses, by class
+	}
+
+	// GovStats reports the governor's current allocations (see target stats)
+	GovStats struct {
+		Classes []GovClassStats `json:"classes"`
+		Mpaths  []GovMpathStats `json:"mpaths"`
+	}
+	GovClassStats struct {
+		Name    string `json:"name"`
+		Active  int    `json:"active"`    // running xactions
+		NetRate int64  `json:"net_bps"`   // allocated transmit rate (bytes/s); 0 - unlimited
+		NetWait int64  `json:"net_wait"`  // throttled transmissions
+		Waits   int64  `json:"disk_wait"` // throttling pauses, all mountpaths
+	}
+	GovMpathStats struct {
+		Mpath string    `json:"mpath"`
+		Util  float32   `json:"util"`  // disk utilization (%)
+		Share float64   `json:"share"` // of the disk throughput given to xactions
+		Delay []float64 `json:"delay"` // per-object pause (ms), by class
+	}
+)
+
+func NewGovernor() *Governor {
+	g := &Governor{disk: make(map[string]*diskBudget, 4), stopCh: make(chan struct{})}
+	for prio := 0; prio < NumPrio; prio++ {
+		prio := prio
+		g.net[prio] = transport.NewLimiter(0, func(time.Duration) { atomic.AddInt64(&g.netWait[prio], 1) })
+	}
+	return g
+}
+
+// KindPrio returns the priority class of a given xaction kind
+func KindPrio(kind string) int {
+	if i := strings.IndexByte(kind, '/'); i > 0 { // e.g. "erasecopies/<bucket>"
+		kind = kind[:i]
+	}
+	switch kind {
+	case cmn.ActGlobalReb, cmn.ActLocalReb, cmn.ActEC:
+		return PrioHigh
+	case cmn.ActPutCopies, cmn.ActDownload:
+		return PrioNormal
+	default:
+		return PrioLow
+	}
+}
+
+// SetActive registers the callback that counts the target's running xactions by class
+func (g *Governor) SetActive(active func() [NumPrio]int) {
+	g.mtx.Lock()
+	g.active = active
+	g.mtx.Unlock()
+}
+
+// NetLimiter returns the transmit rate limiter shared by all intra-cluster
+// streams of the given class
+func (g *Governor) NetLimiter(prio int) *transport.Limiter { return g.net[prio] }
+
+// Throttle pauses the calling xaction for the time allotted to its class on the given mountpath
+func (g *Governor) Throttle(mpath string, prio int) {
+	g.mtx.RLock()
+	b, ok := g.disk[mpath]
+	g.mtx.RUnlock()
+	if !ok {
+		return
+	}
+	if delay := time.Duration(atomic.LoadInt64(&b.delay[prio])); delay > 0 {
+		atomic.AddInt64(&b.waits[prio], 1)
+		time.Sleep(delay)
+	}
+}
+
+func (g *Governor) Run() error {
+	glog.Infof("Starting %s", g.Getname())
+	period := cmn.GCO.Get().Periodic.IostatTime
+	ticker := time.NewTicker(period)
+	for {
+		select {
+		case <-ticker.C:
+			config := cmn.GCO.Get()
+			g.update(config)
+			if config.Periodic.IostatTime != period { // (adjustable at runtime)
+				period = config.Periodic.IostatTime
+				ticker.Stop()
+				ticker = time.NewTicker(period)
+			}
+		case <-g.stopCh:
+			ticker.Stop()
+			return nil
+		}
+	}
+}
+
+func (g *Governor) Stop(err error) {
+	glog.Infof("Stopping %s, err: %v", g.Getname(), err)
+	close(g.stopCh)
+}
+
+// update re-computes the allocations
+func (g *Governor) update(config *cmn.Config) {
+	var (
+		availablePaths, _ = fs.Mountpaths.Get()
+		lwm, hwm          = float32(config.Xaction.DiskUtilLowWM), float32(config.Xaction.DiskUtilHighWM)
+	)
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	if g.active != nil {
+		g.nactive = g.active()
+	}
+	for mpath := range g.disk {
+		if _, ok := availablePaths[mpath]; !ok {
+			delete(g.disk, mpath)
+		}
+	}
+	for mpath, mpathInfo := range availablePaths {
+		b, ok := g.disk[mpath]
+		if !ok {
+			b = &diskBudget{share: 1}
+			g.disk[mpath] = b
+		}
+		_, curr := mpathInfo.GetIOstats(fs.StatDiskUtil)
+		b.util = curr.Max
+		b.share = nextDiskShare(b.share, b.util, lwm, hwm)
+		for prio, delay := range diskDelays(b.share, g.nactive) {
+			atomic.StoreInt64(&b.delay[prio], int64(delay))
+		}
+	}
+	for prio, rate := range netRates(config.Xaction.Bandwidth, g.nactive) {
+		g.net[prio].SetRate(rate)
+	}
+}
+
+// AIMD: multiplicative decrease above the high watermark, additive increase below the low one
+func nextDiskShare(share float64, util, lwm, hwm float32) float64 {
+	switch {
+	case util >= hwm:
+		share = math.Max(share/2, minDiskShare)
+	case util < lwm:
+		share = math.Min(share+diskShareStep, 1)
+	}
+	return share
+}
+
+// diskDelays converts the share into per-object pauses, by class
+func diskDelays(share float64, active [NumPrio]int) (delays [NumPrio]time.Duration) {
+	if share >= 1 {
+		return
+	}
+	wmax := prioWeights[PrioLow]
+	for prio := NumPrio - 1; prio >= 0; prio-- {
+		if active[prio] > 0 {
+			wmax = prioWeights[prio]
+			break
+		}
+	}
+	for prio := 0; prio < NumPrio; prio++ {
+		f := math.Pow(share, math.Max(wmax/prioWeights[prio], 1))
+		delay := time.Duration(float64(cmn.ThrottleSleepAvg) * (1/f - 1))
+		if delay > cmn.ThrottleSleepMax {
+			delay = cmn.ThrottleSleepMax
+		}
+		delays[prio] = delay
+	}
+	return
+}
+
+// netRates divides the bandwidth among the active classes by weight;
+// an idle class gets the share it would have if it were to start
+func netRates(bandwidth int64, active [NumPrio]int) (rates [NumPrio]int64) {
+	if bandwidth == 0 {
+		return
+	}
+	var wactive float64
+	for prio := 0; prio < NumPrio; prio++ {
+		if active[prio] > 0 {
+			wactive += prioWeights[prio]
+		}
+	}
+	for prio := 0; prio < NumPrio; prio++ {
+		w := wactive
+		if active[prio] == 0 {
+			w += prioWeights[prio]
+		}
+		rates[prio] = int64(float64(bandwidth) * prioWeights[prio] / w)
+	}
+	return
+}
+
+func (g *Governor) Stats() *GovStats {
+	g.mtx.RLock()
+	defer g.mtx.RUnlock()
+	stats := &GovStats{Classes: make([]GovClassStats, NumPrio), Mpaths: make([]GovMpathStats, 0, len(g.disk))}
+	for prio := 0; prio < NumPrio; prio++ {
+		stats.Classes[prio] = GovClassStats{
+			Name:    PrioNames[prio],
+			Active:  g.nactive[prio],
+			NetRate: g.net[prio].Rate(),
+			NetWait: atomic.LoadInt64(&g.netWait[prio]),
+		}
+	}
+	for mpath, b := range g.disk {
+		st := GovMpathStats{Mpath: mpath, Util: b.util, Share: b.share, Delay: make([]float64, NumPrio)}
+		for prio := 0; prio < NumPrio; prio++ {
+			st.Delay[prio] = float64(atomic.LoadInt64(&b.delay[prio])) / float64(time.Millisecond)
+			stats.Classes[prio].Waits += atomic.LoadInt64(&b.waits[prio])
+		}
+		stats.Mpaths = append(stats.Mpaths, st)
+	}
+	sort.Slice(stats.Mpaths, func(i, j int) bool { return stats.Mpaths[i].Mpath < stats.Mpaths[j].Mpath })
+	return stats
+}
+
+// Throttling returns true if xactions are currently being throttled on any mountpath
+func (s *GovStats) Throttling() bool {
+	for _, st := range s.Mpaths {
+		if st.Share < 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package governor
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+func TestKindPrio(t *testing.T) {
+	tcs := map[string]int{
+		cmn.ActGlobalReb:                       PrioHigh,
+		cmn.ActEC:                              PrioHigh,
+		cmn.ActPutCopies:                       PrioNormal,
+		path.Join(cmn.ActPutCopies, "bucket"):  PrioNormal,
+		cmn.ActLRU:                             PrioLow,
+		path.Join(cmn.ActEraseCopies, "abc"):   PrioLow,
+		path.Join(cmn.ActRechecksum, "bucket"): PrioLow,
+	}
+	for kind, prio := range tcs {
+		if p := KindPrio(kind); p != prio {
+			t.Errorf("%s: expecting %s priority, got %s", kind, PrioNames[prio], PrioNames[p])
+		}
+	}
+}
+
+func TestDiskShare(t *testing.T) {
+	share := 1.0
+	for i := 0; i < 10; i++ {
+		share = nextDiskShare(share, 95, 20, 80)
+	}
+	if share != minDiskShare {
+		t.Fatalf("expecting the share to bottom out at %f, got %f", minDiskShare, share)
+	}
+	if s := nextDiskShare(share, 50, 20, 80); s != share {
+		t.Fatalf("expecting no change between the watermarks, got %f => %f", share, s)
+	}
+	for i := 0; i < 8; i++ {
+		share = nextDiskShare(share, 10, 20, 80)
+	}
+	if share != 1 {
+		t.Fatalf("expecting full share on idle disks, got %f", share)
+	}
+}
+
+func TestDiskDelays(t *testing.T) {
+	var active [NumPrio]int
+	if delays := diskDelays(1, [NumPrio]int{1, 1, 1}); delays != [NumPrio]time.Duration{} {
+		t.Fatalf("expecting no throttling at full share, got %v", delays)
+	}
+	// only low-priority xactions running: they get the entire share
+	active[PrioLow] = 2
+	lowOnly := diskDelays(0.5, active)
+	if lowOnly[PrioLow] != cmn.ThrottleSleepAvg {
+		t.Fatalf("expecting %v delay, got %v", cmn.ThrottleSleepAvg, lowOnly)
+	}
+	// with rebalance running, the lower classes pay
+	active[PrioHigh] = 1
+	delays := diskDelays(0.5, active)
+	if delays[PrioHigh] != lowOnly[PrioLow] ||
+		!(delays[PrioHigh] < delays[PrioNormal] && delays[PrioNormal] < delays[PrioLow]) {
+		t.Fatalf("expecting delays to decrease with priority, got %v", delays)
+	}
+	if delays[PrioLow] != cmn.ThrottleSleepMax {
+		t.Fatalf("expecting the delay capped at %v, got %v", cmn.ThrottleSleepMax, delays[PrioLow])
+	}
+}
+
+func TestNetRates(t *testing.T) {
+	if rates := netRates(0, [NumPrio]int{1, 1, 1}); rates != [NumPrio]int64{} {
+		t.Fatalf("expecting unlimited, got %v", rates)
+	}
+	bw := int64(70 * cmn.MiB)
+	rates := netRates(bw, [NumPrio]int{1, 1, 1})
+	if rates[PrioLow] != 10*cmn.MiB || rates[PrioNormal] != 20*cmn.MiB || rates[PrioHigh] != 40*cmn.MiB {
+		t.Fatalf("expecting bandwidth divided by weight, got %v", rates)
+	}
+	rates = netRates(bw, [NumPrio]int{0, 0, 3})
+	if rates[PrioHigh] != bw || rates[PrioLow] != bw/5 {
+		t.Fatalf("expecting entire bandwidth for the only active class, got %v", rates)
+	}
+}
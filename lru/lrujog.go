@@ -19,6 +19,7 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/governor"
 	"github.com/NVIDIA/aistore/stats"
 )
 
@@ -138,6 +139,7 @@ func (lctx *lructx) walk(fqn string, osfi os.FileInfo, err error) error {
 func (lctx *lructx) evict() (err error) {
 	var (
 		fevicted, bevicted int64
+		fdemoted, bdemoted int64
 		capCheck           int64
 		h                  = lctx.heap
 	)
@@ -156,11 +158,18 @@ func (lctx *lructx) evict() (err error) {
 		}
 		glog.Infof("Removed old %q", fi.fqn)
 	}
+	lctx.ini.Xlru.TotalAdd(int64(h.Len())) // (the heap holds just enough to free totsize)
 	for h.Len() > 0 && lctx.totsize > 0 {
 		fi := heap.Pop(h).(*fileInfo)
-		if lctx.evictObj(fi) {
+		if ok, demoted := lctx.evictObj(fi); ok {
 			bevicted += fi.lom.Size
 			fevicted++
+			lctx.ini.Xlru.ObjectsAdd(1)
+			lctx.ini.Xlru.BytesAdd(fi.lom.Size)
+			if demoted {
+				bdemoted += fi.lom.Size
+				fdemoted++
+			}
 			if capCheck, err = lctx.postRemove(capCheck, fi); err != nil {
 				return
 			}
@@ -168,6 +177,10 @@ func (lctx *lructx) evict() (err error) {
 	}
 	lctx.ini.Statsif.Add(stats.LruEvictSize, bevicted)
 	lctx.ini.Statsif.Add(stats.LruEvictCount, fevicted)
+	if fdemoted > 0 {
+		lctx.ini.Statsif.AddMany(stats.NamedVal64{Name: stats.LruDemoteSize, Val: bdemoted},
+			stats.NamedVal64{Name: stats.LruDemoteCount, Val: fdemoted})
+	}
 	return nil
 }
 
@@ -184,24 +197,24 @@ func (lctx *lructx) postRemove(capCheck int64, fi *fileInfo) (int64, error) {
 		lctx.config = cmn.GCO.Get()
 		now := time.Now()
 		lctx.dontevictime = now.Add(-lctx.config.LRU.DontEvictTime)
-		if ok && usedpct < lctx.config.LRU.HighWM {
-			if !lctx.mpathInfo.IsIdle(lctx.config) {
-				// throttle self
-				ratioCapacity := cmn.Ratio(lctx.config.LRU.HighWM, lctx.config.LRU.LowWM, usedpct)
-				_, curr := lctx.mpathInfo.GetIOstats(fs.StatDiskUtil)
-				ratioUtilization := cmn.Ratio(lctx.config.Xaction.DiskUtilHighWM, lctx.config.Xaction.DiskUtilLowWM, int64(curr.Max))
-				if ratioUtilization > ratioCapacity {
-					lctx.throttle = true
-					time.Sleep(cmn.ThrottleSleepMax)
-				}
-			}
-		}
+		// unless running out of space, give way to user IO and higher-priority xactions
+		lctx.throttle = ok && usedpct < lctx.config.LRU.HighWM
 	}
 	return capCheck, nil
 }
 
-func (lctx *lructx) evictObj(fi *fileInfo) (ok bool) {
+func (lctx *lructx) evictObj(fi *fileInfo) (ok, demoted bool) {
 	lctx.ini.Namelocker.Lock(fi.lom.Uname, true)
+	// local bucket with the next tier configured: instead of simply deleting
+	// the object, demote it first - and keep it if demotion fails
+	if fi.lom.BckIsLocal && fi.lom.BckProps != nil && fi.lom.BckProps.NextTierURL != "" {
+		if err := lctx.ini.T.Demote(fi.lom); err != nil {
+			glog.Errorf("%v - not evicting", err)
+			lctx.ini.Namelocker.Unlock(fi.lom.Uname, true)
+			return
+		}
+		demoted = true
+	}
 	// local replica must be go with the object; the replica, however, is
 	// located in a different local FS and belongs, therefore, to a different LRU jogger
 	// (hence, precise size accounting TODO)
@@ -211,7 +224,11 @@ func (lctx *lructx) evictObj(fi *fileInfo) (ok bool) {
 		}
 	}
 	if err := os.Remove(fi.lom.FQN); err == nil {
-		glog.Infof("Evicted %s", fi.lom)
+		if demoted {
+			glog.Infof("Evicted (demoted) %s", fi.lom)
+		} else {
+			glog.Infof("Evicted %s", fi.lom)
+		}
 		ok = true
 	} else if os.IsNotExist(err) {
 		ok = true
@@ -253,7 +270,7 @@ func (lctx *lructx) yieldTerm() error {
 		return fmt.Errorf("%s aborted, exiting", xlru)
 	default:
 		if lctx.throttle {
-			time.Sleep(cmn.ThrottleSleepMin)
+			governor.Gov.Throttle(lctx.mpathInfo.Path, governor.PrioLow)
 		} else {
 			runtime.Gosched()
 		}
@@ -6,6 +6,7 @@
 package lru
 
 import (
+	"context"
 	"crypto/rand"
 	"fmt"
 	"io/ioutil"
@@ -249,6 +250,45 @@ var _ = Describe("LRU tests", func() {
 			})
 		})
 
+		Describe("demote files", func() {
+			BeforeEach(func() {
+				t.BO.(cluster.BownerMock).LBmap[bucketName].NextTierURL = "http://tier2:8080"
+			})
+
+			It("should evict demoted files", func() {
+				const numberOfFiles = 6
+				ini.GetFSStats = getMockGetFSStats(numberOfFiles, initialDiskUsagePct)
+				saveRandomFiles(filesPath, numberOfFiles, fileSize)
+
+				demoted := make(map[string]bool)
+				t.DemoteFn = func(lom *cluster.LOM) error {
+					demoted[lom.FQN] = true
+					return nil
+				}
+				InitAndRun(ini)
+
+				files, err := ioutil.ReadDir(filesPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(files)).To(BeNumerically("<", numberOfFiles))
+				Expect(len(demoted)).To(Equal(numberOfFiles - len(files)))
+			})
+
+			It("should not evict files that failed to demote", func() {
+				const numberOfFiles = 6
+				ini.GetFSStats = getMockGetFSStats(numberOfFiles, initialDiskUsagePct)
+				saveRandomFiles(filesPath, numberOfFiles, fileSize)
+
+				t.DemoteFn = func(lom *cluster.LOM) error {
+					return fmt.Errorf("next tier %s is unreachable", lom.BckProps.NextTierURL)
+				}
+				InitAndRun(ini)
+
+				files, err := ioutil.ReadDir(filesPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(files)).To(Equal(numberOfFiles))
+			})
+		})
+
 		Describe("not evict files", func() {
 			It("should do nothing when disk usage is below hwm", func() {
 				const numberOfFiles = 4
@@ -296,6 +336,9 @@ func (n nameLockerMock) TryLock(uname string, exclusive bool) bool { return true
 func (n nameLockerMock) Lock(uname string, exclusive bool)         {}
 func (n nameLockerMock) DowngradeLock(uname string)                {}
 func (n nameLockerMock) Unlock(uname string, exclusive bool)       {}
+func (n nameLockerMock) LockCtx(ctx context.Context, uname string, exclusive bool) error {
+	return nil
+}
 
 // TODO: Maybe this should be in stats_mock.go
 
@@ -315,5 +358,11 @@ func (x *xactMock) StartTime(s ...time.Time) time.Time { return time.Now() }
 func (x *xactMock) EndTime(e ...time.Time) time.Time   { return time.Now() }
 func (x *xactMock) String() string                     { return "" }
 func (x *xactMock) Abort()                             {}
+func (x *xactMock) AbortWith(reason string)            {}
 func (x *xactMock) ChanAbort() <-chan struct{}         { return nil }
 func (x *xactMock) Finished() bool                     { return false }
+func (x *xactMock) ObjectsAdd(cnt int64) int64         { return 0 }
+func (x *xactMock) BytesAdd(size int64) int64          { return 0 }
+func (x *xactMock) TotalAdd(cnt int64) int64           { return 0 }
+func (x *xactMock) AddErr(err error)                   {}
+func (x *xactMock) Snapshot() cmn.XactStats            { return cmn.XactStats{} }
@@ -17,6 +17,7 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/governor"
 )
 
 const (
@@ -159,8 +160,12 @@ func (j *eraser) walk(fqn string, osfi os.FileInfo, err error) error {
 	defer j.parent.Namelocker.Unlock(lom.Uname, true)
 
 	if errstr := lom.DelCopy(); errstr != "" {
-		return errors.New(errstr)
+		err = errors.New(errstr)
+		j.parent.AddErr(err)
+		return err
 	}
+	j.parent.ObjectsAdd(1)
+	j.parent.BytesAdd(lom.Size)
 	j.num++
 	if (j.num % throttleNumErased) == 0 {
 		if err = j.yieldTerm(); err != nil {
@@ -178,17 +183,11 @@ func (j *eraser) walk(fqn string, osfi os.FileInfo, err error) error {
 
 // [throttle]
 func (j *eraser) yieldTerm() error {
-	xaction := &j.config.Xaction
 	select {
 	case <-j.stopCh:
 		return fmt.Errorf("eraser[%s/%s] aborted, exiting", j.mpathInfo, j.parent.Bucket())
 	default:
-		_, curr := j.mpathInfo.GetIOstats(fs.StatDiskUtil)
-		if curr.Max >= float32(xaction.DiskUtilHighWM) && curr.Min > float32(xaction.DiskUtilLowWM) {
-			time.Sleep(cmn.ThrottleSleepAvg)
-		} else {
-			time.Sleep(cmn.ThrottleSleepMin)
-		}
+		governor.Gov.Throttle(j.mpathInfo.Path, governor.PrioLow)
 		break
 	}
 	return nil
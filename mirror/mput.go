@@ -5,6 +5,7 @@
 package mirror
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"sync"
@@ -15,6 +16,7 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/governor"
 	"github.com/NVIDIA/aistore/memsys"
 )
 
@@ -120,6 +122,7 @@ func (r *XactCopy) Copy(lom *cluster.LOM) (err error) {
 		}
 	}
 	r.IncPending() // ref-count via base to support on-demand action
+	r.TotalAdd(1)
 	r.workCh <- lom
 
 	// [throttle]
@@ -210,6 +213,7 @@ loop:
 		case lom := <-j.workCh:
 			j.mirror(lom)
 			j.parent.DecPending() // to support action renewal on-demand
+			governor.Gov.Throttle(j.mpathInfo.Path, governor.PrioNormal)
 		case <-j.stopCh:
 			break loop
 		}
@@ -233,11 +237,15 @@ func (j *copier) mirror(lom *cluster.LOM) {
 	}
 	if err := cmn.MvFile(workFQN, cpyFQN); err != nil {
 		glog.Errorln(err)
+		j.parent.AddErr(err)
 		goto fail
 	}
 	if errstr := lom.SetXcopy(cpyFQN); errstr != "" {
 		glog.Errorln(errstr)
+		j.parent.AddErr(errors.New(errstr))
 	} else {
+		j.parent.ObjectsAdd(1)
+		j.parent.BytesAdd(lom.Size)
 		if glog.V(4) {
 			glog.Infof("copied %s/%s %s=>%s", lom.Bucket, lom.Objname, lom.ParsedFQN.MpathInfo, j.mpathInfo)
 		}
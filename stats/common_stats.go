@@ -49,6 +49,8 @@ const (
 	ErrListCount     = "err.list.n"
 	ErrRangeCount    = "err.range.n"
 	ErrDownloadCount = "err.dl.n"
+	// nodes that failed to respond to keepalives and became suspect (primary only)
+	KeepAliveSuspectCount = "kalive.suspect.n"
 
 	// KindLatency
 	GetLatency          = "get.µs"
@@ -175,6 +177,7 @@ func (tracker statsTracker) registerCommonStats() {
 	tracker.register(ErrListCount, KindCounter, true)
 	tracker.register(ErrRangeCount, KindCounter, true)
 	tracker.register(ErrDownloadCount, KindCounter, true)
+	tracker.register(KeepAliveSuspectCount, KindCounter, true)
 	//
 	tracker.register(Uptime, KindSpecial, true)
 }
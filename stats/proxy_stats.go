@@ -28,6 +28,12 @@ type (
 	Prunner struct {
 		statsRunner
 		Core *ProxyCoreStats `json:"core"`
+		// the state of the keepalive tracking (set by the proxy)
+		Keepalive func() *cmn.KeepaliveStats `json:"-"`
+	}
+	copyPrunner struct {
+		Tracker   copyTracker         `json:"core"`
+		Keepalive *cmn.KeepaliveStats `json:"keepalive,omitempty"`
 	}
 	ClusterStats struct {
 		Proxy  *ProxyCoreStats     `json:"proxy"`
@@ -158,7 +164,12 @@ func (r *Prunner) ConfigUpdate(oldConf, newConf *cmn.Config) {
 func (r *Prunner) GetWhatStats() ([]byte, error) {
 	ctracker := make(copyTracker, 24)
 	r.Core.copyCumulative(ctracker)
-	return jsonCompat.Marshal(ctracker)
+
+	crunner := &copyPrunner{Tracker: ctracker}
+	if r.Keepalive != nil {
+		crunner.Keepalive = r.Keepalive()
+	}
+	return jsonCompat.Marshal(crunner)
 }
 
 // statslogger interface impl
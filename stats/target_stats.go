@@ -18,6 +18,7 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/governor"
 	"github.com/NVIDIA/aistore/ios"
 	"github.com/NVIDIA/aistore/stats/statsd"
 	jsoniter "github.com/json-iterator/go"
@@ -33,6 +34,8 @@ const (
 	GetColdSize      = "get.cold.size"
 	LruEvictSize     = "lru.evict.size"
 	LruEvictCount    = "lru.evict.n"
+	LruDemoteSize    = "lru.demote.size"
+	LruDemoteCount   = "lru.demote.n"
 	TxCount          = "tx.n"
 	TxSize           = "tx.size"
 	RxCount          = "rx.n"
@@ -50,6 +53,16 @@ const (
 	RebLocalSize     = "reb.local.size"
 	ReplPutCount     = "repl.n"
 	DownloadSize     = "dl.size"
+	// rebalance throttling (config.Rebalance)
+	RebThrottleNetCount  = "reb.throttle.net.n"  // rebalance transmissions delayed by the bandwidth limit
+	RebThrottleDiskCount = "reb.throttle.disk.n" // rebalance pauses due to high disk utilization
+	// cloud client health
+	CloudRetryCount         = "cloud.retry.n"          // retried Cloud requests
+	CloudBreakerTripCount   = "cloud.breaker.trip.n"   // circuit breaker transitions to open
+	CloudBreakerRejectCount = "cloud.breaker.reject.n" // Cloud requests failed fast while the circuit is open
+	// garbage collection of orphaned workfiles and intermediate content
+	GCCount = "gc.n"
+	GCSize  = "gc.size"
 
 	// KindLatency
 	PutLatency      = "put.µs"
@@ -72,6 +85,8 @@ type (
 		Riostat  *ios.IostatRunner      `json:"-"`
 		Core     *targetCoreStats       `json:"core"`
 		Capacity map[string]*fscapacity `json:"capacity"`
+		// the state of the Cloud circuit breaker (set by the target; nil - no Cloud)
+		CloudBreaker func() *cmn.CloudBreakerStats `json:"-"`
 		// inner state
 		timecounts struct {
 			capLimit, capIdx int64 // update capacity: time interval counting
@@ -82,6 +97,8 @@ type (
 	copyRunner struct {
 		Tracker  copyTracker            `json:"core"`
 		Capacity map[string]*fscapacity `json:"capacity"`
+		Governor *governor.GovStats     `json:"governor"` // current IO allocations among xactions
+		Cloud    *cmn.CloudBreakerStats `json:"cloud,omitempty"`
 	}
 )
 
@@ -140,7 +157,10 @@ func (r *Trunner) GetWhatStats() ([]byte, error) {
 	ctracker := make(copyTracker, 48)
 	r.Core.copyCumulative(ctracker)
 
-	crunner := &copyRunner{Tracker: ctracker, Capacity: r.Capacity}
+	crunner := &copyRunner{Tracker: ctracker, Capacity: r.Capacity, Governor: governor.Gov.Stats()}
+	if r.CloudBreaker != nil {
+		crunner.Cloud = r.CloudBreaker()
+	}
 	return jsonCompat.Marshal(crunner)
 }
 
@@ -168,7 +188,14 @@ func (r *Trunner) log() (runlru bool) {
 		}
 	}
 
-	// 3. log
+	// 3. IO governor, when throttling
+	if gstats := governor.Gov.Stats(); gstats.Throttling() {
+		if b, err := jsoniter.Marshal(gstats); err == nil {
+			r.lines = append(r.lines, "governor: "+string(b))
+		}
+	}
+
+	// 4. log
 	for _, ln := range r.lines {
 		glog.Infoln(ln)
 	}
@@ -374,6 +401,11 @@ func (r *Trunner) GetRebalanceStats(allXactionDetails []XactionDetails) []byte {
 	}
 	vt.RUnlock()
 	vr.RUnlock()
+	config := cmn.GCO.Get()
+	rebalanceXactionStats.Bandwidth = config.Rebalance.Bandwidth
+	rebalanceXactionStats.DiskUtilMax = config.Rebalance.DiskUtilMax
+	rebalanceXactionStats.NumThrottledNet = r.counter(RebThrottleNetCount)
+	rebalanceXactionStats.NumThrottledDisk = r.counter(RebThrottleDiskCount)
 	jsonBytes, err := jsoniter.Marshal(rebalanceXactionStats)
 	cmn.AssertNoErr(err)
 	return jsonBytes
@@ -383,6 +415,17 @@ func (r *Trunner) GetRebalanceStats(allXactionDetails []XactionDetails) []byte {
 // misc
 //
 
+func (r *Trunner) counter(name string) (val int64) {
+	v, ok := r.Core.Tracker[name]
+	if !ok {
+		return
+	}
+	v.RLock()
+	val = v.Value
+	v.RUnlock()
+	return
+}
+
 func newFSCapacity(statfs *syscall.Statfs_t) *fscapacity {
 	pct := (statfs.Blocks - statfs.Bavail) * 100 / statfs.Blocks
 	return &fscapacity{
@@ -32,6 +32,11 @@ type (
 		NumSentBytes int64            `json:"numSentBytes"`
 		NumRecvFiles int64            `json:"numRecvFiles"`
 		NumRecvBytes int64            `json:"numRecvBytes"`
+		// throttling (see config.Rebalance)
+		Bandwidth        int64 `json:"bandwidth"`
+		DiskUtilMax      int64 `json:"diskUtilMax"`
+		NumThrottledNet  int64 `json:"numThrottledNet"`
+		NumThrottledDisk int64 `json:"numThrottledDisk"`
 	}
 	RebalanceStats struct {
 		Kind        string                          `json:"kind"`
@@ -71,6 +71,7 @@ type (
 		stopCh   chan struct{} // stop/abort stream
 		postCh   chan struct{} // to indicate that workCh has work
 		callback SendCallback  // to free SGLs, close files, etc.
+		limiter  *Limiter      // transmit rate limiter (optional)
 		time     struct {
 			start   int64         // to support idle(%)
 			idleOut time.Duration // idle timeout
@@ -95,6 +96,7 @@ type (
 		Callback    SendCallback    // typical usage: to free SGLs, close files, etc.
 		Burst       int             // SQ and CSQ buffer sizes: max num objects and send-completions
 		DryRun      bool            // dry run: short-circuit the stream on the send side
+		Limiter     *Limiter        // optional: limits transmit rate (can be shared by multiple streams)
 	}
 	// stream stats
 	Stats struct {
@@ -330,6 +332,7 @@ func NewStream(client *http.Client, toURL string, extra *Extra) (s *Stream) {
 	s.time.idleOut = defaultIdleOut
 	if extra != nil {
 		s.callback = extra.Callback
+		s.limiter = extra.Limiter
 		if extra.IdleTimeout > 0 {
 			s.time.idleOut = extra.IdleTimeout
 		}
@@ -700,6 +703,9 @@ func (s *Stream) sendHdr(b []byte) (n int, err error) {
 func (s *Stream) sendData(b []byte) (n int, err error) {
 	n, err = s.sendoff.obj.reader.Read(b)
 	s.sendoff.off += int64(n) // (avg send transfer size tbd)
+	if s.limiter != nil && n > 0 {
+		s.limiter.wait(n)
+	}
 	if err != nil {
 		if err == io.EOF {
 			err = nil
@@ -0,0 +1,78 @@
+// Package transport provides streaming object-based transport over http for intra-cluster continuous
+// intra-cluster communications (see README for details and usage example).
+/*
+ * Copyright (c) 2018, NVIDIA CORPORATION. All rights reserved.
+ */
+package transport
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Limiter is a token bucket that limits the aggregate transmit rate (bytes/s)
+// of all the streams that share it (see Extra.Limiter). The bucket holds up to
+// one second worth of tokens; the rate can be changed at runtime - zero rate
+// means no limit.
+type Limiter struct {
+	rate   int64 // bytes per second
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	onWait func(time.Duration) // optional: called upon each throttling wait
+	parent *Limiter            // optional: the aggregate limit this one is part of (see Chain)
+}
+
+func NewLimiter(rate int64, onWait func(time.Duration)) *Limiter {
+	l := &Limiter{onWait: onWait, last: time.Now()}
+	l.SetRate(rate)
+	return l
+}
+
+func (l *Limiter) Rate() int64 { return atomic.LoadInt64(&l.rate) }
+
+func (l *Limiter) SetRate(rate int64) {
+	if rate < 0 {
+		rate = 0
+	}
+	atomic.StoreInt64(&l.rate, rate)
+}
+
+// Chain makes the limiter's users wait on the parent as well - e.g., to apply
+// both the rebalance bandwidth and the aggregate limit for all xactions
+func (l *Limiter) Chain(parent *Limiter) { l.parent = parent }
+
+// wait reserves n bytes of the bandwidth (and the parent's) and blocks until the reservation is due
+func (l *Limiter) wait(n int) {
+	l.reserve(n)
+	if l.parent != nil {
+		l.parent.wait(n)
+	}
+}
+
+func (l *Limiter) reserve(n int) {
+	rate := atomic.LoadInt64(&l.rate)
+	if rate == 0 {
+		return
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * float64(rate)
+	if l.tokens > float64(rate) {
+		l.tokens = float64(rate)
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / float64(rate) * float64(time.Second))
+	}
+	l.mu.Unlock()
+	if delay > 0 {
+		time.Sleep(delay)
+		if l.onWait != nil {
+			l.onWait(delay)
+		}
+	}
+}
@@ -419,6 +419,44 @@ func Test_OnSendCallback(t *testing.T) {
 	}
 }
 
+func Test_Limiter(t *testing.T) {
+	const rate = 4 * cmn.MiB
+	mux := mux.NewServeMux()
+
+	transport.SetMux("n1", mux)
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	totalRecv, recvFunc := makeRecvFunc(t)
+	path, err := transport.Register("n1", "limiter", recvFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var waits int64
+	limiter := transport.NewLimiter(rate, func(time.Duration) { atomic.AddInt64(&waits, 1) })
+	httpclient := &http.Client{Transport: &http.Transport{}}
+	stream := transport.NewStream(httpclient, ts.URL+path, &transport.Extra{Limiter: limiter})
+
+	var totalSend int64
+	started := time.Now()
+	for totalSend < 3*rate {
+		hdr, reader := makeRandReader()
+		stream.Send(hdr, reader, nil)
+		totalSend += hdr.ObjAttrs.Size
+	}
+	stream.Fin()
+	elapsed := time.Since(started)
+
+	if *totalRecv != totalSend {
+		t.Fatalf("total received bytes %d is different from expected: %d", *totalRecv, totalSend)
+	}
+	// the bucket holds one second worth of tokens
+	if expected := time.Duration(totalSend-rate) * time.Second / rate; elapsed < expected*9/10 || waits == 0 {
+		t.Fatalf("sent %d bytes in %v (waits %d) - expected at least %v", totalSend, elapsed, waits, expected)
+	}
+}
+
 func Test_ObjAttrs(t *testing.T) {
 	testAttrs := []transport.ObjectAttrs{
 		transport.ObjectAttrs{